@@ -0,0 +1,473 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"examples/data-structure/cache"
+	"examples/data-structure/stack"
+	"examples/notification"
+	"examples/otp"
+	"examples/patterns/creational"
+	"examples/patterns/structural"
+	"examples/pricing"
+	"examples/registry"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+type apiRoute struct {
+	registry.Route
+	handler fiber.Handler
+}
+
+/*
+API endpoints which are not examples ie stats and POST endpoints working on the pattern implementations.
+Unlike the examples, they are not captured and reply with their own JSON. They are mounted in this order,
+so the static paths (ie /bookings/routes) come before the parameters (/bookings/:pnr), and registered
+for the docs (Postman collection and OpenAPI document).
+*/
+var apiRoutes = []apiRoute{
+	{registry.Route{Method: fiber.MethodGet, Path: "/pattern/creational/object-pool/stats", Title: "Object Pool Stats",
+		Description: "Stats of the connection pool shared by the object-pool runs"}, objectPoolStats},
+	{registry.Route{Method: fiber.MethodPost, Path: "/notify", Title: "Notify",
+		Description: "Send a notification via the demo notifier, 502 once all the providers failed",
+		Body:        `{"type": "SMS", "to": "9910825975", "template": "welcome", "data": {"name": "Harry"}}`}, notify},
+	{registry.Route{Method: fiber.MethodPost, Path: "/otp/send", Title: "OTP Send",
+		Description: "Send an OTP, 429 with Retry-After during the cooldown",
+		Body:        `{"type": "SMS", "to": "9910825975"}`}, otpSend},
+	{registry.Route{Method: fiber.MethodPost, Path: "/otp/verify", Title: "OTP Verify",
+		Description: "Verify the OTP, 401 if invalid, 429 after too many attempts",
+		Body:        `{"type": "SMS", "to": "9910825975", "code": "123456"}`}, otpVerify},
+	{registry.Route{Method: fiber.MethodGet, Path: "/pricing/menu", Title: "Pricing Menu",
+		Description: "Base items and decorators of the demo menu"}, pricingMenu},
+	{registry.Route{Method: fiber.MethodPost, Path: "/pricing/quote", Title: "Pricing Quote",
+		Description: "Price an order, replying the itemised breakdown",
+		Body:        `{"items": [{"base": "margherita", "decorators": [{"kind": "topping", "name": "cheese"}]}]}`}, pricingQuote},
+	{registry.Route{Method: fiber.MethodPost, Path: "/pattern/structural/facade/evaluate", Title: "Facade Evaluate",
+		Description: "Evaluate the travel services for a booking, replying the decisions and their reasons",
+		Body:        `{"booking": {"lob": "Flights", "airline": "Indigo", "travel_date": "2023-06-04T10:00:00Z", "amount": 500000, "cancellation_reason": "AIRLINE"}}`}, facadeEvaluate},
+	{registry.Route{Method: fiber.MethodGet, Path: "/pattern/structural/flyweight/roster", Title: "Flyweight Roster",
+		Description: "Memory used by N students with and without the shared houses",
+		Params:      []registry.Param{{Name: "students", Type: registry.PARAM_INT, Default: "1000", Usage: "students of the roster, at most 10000"}}}, flyweightRoster},
+	{registry.Route{Method: fiber.MethodGet, Path: "/prototypes", Title: "Prototypes",
+		Description: "Names of the registered prototypes"}, prototypeNames},
+	{registry.Route{Method: fiber.MethodPost, Path: "/prototypes/:name/clone", Title: "Prototype Clone",
+		Description: "Deep clone of the prototype, optionally renamed",
+		Body:        `{"name": "billing"}`}, prototypeClone},
+	{registry.Route{Method: fiber.MethodGet, Path: "/bookings/routes", Title: "Booking Routes",
+		Description: "Orgs with a registered booking factory and the demo routes"}, bookingRoutes},
+	{registry.Route{Method: fiber.MethodPost, Path: "/bookings", Title: "Booking Create",
+		Description: "Book via the factory of the org",
+		Body:        `{"org": "ixigo", "route_id": "6E-201", "passengers": [{"name": "Harry", "age": 41}]}`}, bookingCreate},
+	{registry.Route{Method: fiber.MethodGet, Path: "/bookings/:pnr", Title: "Booking Get",
+		Description: "Look up a booking by its PNR"}, bookingGet},
+	{registry.Route{Method: fiber.MethodPost, Path: "/bookings/:pnr/cancel", Title: "Booking Cancel",
+		Description: "Cancel the booking, releasing the seats and computing the refund"}, bookingCancel},
+	{registry.Route{Method: fiber.MethodPost, Path: "/stack/eval", Title: "Stack Eval",
+		Description: "Evaluate an infix expression, replying its value and RPN, or 400 with the column of the error",
+		Body:        `{"expression": "max(2, x) * -(3 + 4.5) ^ 2", "variables": {"x": 3}}`}, stackEval},
+	{registry.Route{Method: fiber.MethodPost, Path: "/stack/balanced", Title: "Stack Balanced",
+		Description: "Check the brackets (and the tags) are balanced, replying the position of the first error",
+		Body:        `{"input": "<p>(a [b])</p>", "html": true}`}, stackBalanced},
+	{registry.Route{Method: fiber.MethodGet, Path: "/cache", Title: "Cache Stats",
+		Description: "Stats (hits, misses, evictions, expirations) and keys of the demo LRU cache"}, cacheStats},
+	{registry.Route{Method: fiber.MethodGet, Path: "/cache/:key", Title: "Cache Get",
+		Description: "JSON value of the key, 404 if missing or expired"}, cacheGet},
+	{registry.Route{Method: fiber.MethodPut, Path: "/cache/:key", Title: "Cache Set",
+		Description: "Set the JSON value of the key, evicting the least recently used key once full",
+		Body:        `{"value": {"name": "Harry"}, "ttl": "30s"}`}, cachePut},
+	{registry.Route{Method: fiber.MethodDelete, Path: "/cache/:key", Title: "Cache Delete",
+		Description: "Delete the key, 404 if missing"}, cacheDelete},
+}
+
+func init() {
+	for _, r := range apiRoutes {
+		registry.RegisterRoute(r.Route)
+	}
+}
+
+func mountAPI(api fiber.Router) {
+	for _, r := range apiRoutes {
+		api.Add(r.Method, r.Path, r.handler)
+	}
+}
+
+// objectPoolStats of the connection pool shared by the object-pool example runs
+func objectPoolStats(c *fiber.Ctx) error {
+	p, err := creational.ConnectionPool()
+	if err != nil {
+		return errorJSON(c, fiber.StatusInternalServerError, err)
+	}
+	return c.JSON(p.Stats())
+}
+
+/*
+notify sends a notification via the demo notifier (in-memory xvendor/yvendor), ie:
+
+	{"type": "SMS", "to": "9910825975", "template": "welcome", "data": {"name": "Harry"}}
+
+Invalid requests reply 400, a delivery failure on all the providers replies 502 with the receipt.
+*/
+func notify(c *fiber.Ctx) error {
+	req := notification.Request{}
+	if err := c.BodyParser(&req); err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+
+	n, _, _ := notification.Demo()
+	r, err := n.Notify(c.UserContext(), req)
+	switch {
+	case errors.Is(err, notification.ErrDeliveryFailed):
+		return c.Status(fiber.StatusBadGateway).JSON(map[string]interface{}{"success": false, "error": err.Error(), "receipt": r})
+	case err != nil:
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	return c.JSON(map[string]interface{}{"success": true, "receipt": r})
+}
+
+type otpRequest struct {
+	Type notification.NotificationType `json:"type"`
+	To   string                        `json:"to"`
+	Code string                        `json:"code,omitempty"`
+}
+
+/*
+otpSend sends an OTP via the demo OTP service, ie {"type": "SMS", "to": "9910825975"}
+The response does not have the code, it reaches only the (in-memory) vendors, see the otp example
+for the whole flow.
+*/
+func otpSend(c *fiber.Ctx) error {
+	req := otpRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	s, err := otp.Demo()
+	if err != nil {
+		return errorJSON(c, fiber.StatusInternalServerError, err)
+	}
+
+	r, err := s.Send(c.UserContext(), req.Type, req.To)
+	var ce *otp.CooldownError
+	switch {
+	case errors.As(err, &ce):
+		c.Set(fiber.HeaderRetryAfter, fmt.Sprint(int(ce.RetryAfter.Seconds()+0.5)))
+		return errorJSON(c, fiber.StatusTooManyRequests, err)
+	case errors.Is(err, notification.ErrDeliveryFailed):
+		return errorJSON(c, fiber.StatusBadGateway, err)
+	case err != nil:
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	return c.JSON(map[string]interface{}{"success": true, "result": r})
+}
+
+// otpVerify verifies the code, ie {"type": "SMS", "to": "9910825975", "code": "123456"}
+func otpVerify(c *fiber.Ctx) error {
+	req := otpRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	s, err := otp.Demo()
+	if err != nil {
+		return errorJSON(c, fiber.StatusInternalServerError, err)
+	}
+
+	err = s.Verify(c.UserContext(), req.Type, req.To, req.Code)
+	switch {
+	case errors.Is(err, otp.ErrInvalidCode):
+		return errorJSON(c, fiber.StatusUnauthorized, err)
+	case errors.Is(err, otp.ErrTooManyAttempts):
+		return errorJSON(c, fiber.StatusTooManyRequests, err)
+	case errors.Is(err, otp.ErrNotFound):
+		return errorJSON(c, fiber.StatusNotFound, err)
+	case err != nil:
+		return errorJSON(c, fiber.StatusInternalServerError, err)
+	}
+	return c.JSON(map[string]interface{}{"success": true})
+}
+
+func pricingMenu(c *fiber.Ctx) error {
+	m := pricing.Demo()
+	return c.JSON(map[string]interface{}{"currency": m.Currency, "menu": m.Entries()})
+}
+
+// pricingQuote prices an order spec (see pricing.OrderSpec) and replies with the itemised breakdown
+func pricingQuote(c *fiber.Ctx) error {
+	spec, err := pricing.ParseOrder(c.Body())
+	if err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	q, err := pricing.Demo().Quote(spec)
+	if err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	return c.JSON(map[string]interface{}{"success": true, "quote": q})
+}
+
+type facadeRequest struct {
+	Booking structural.Booking `json:"booking"`
+	Service string             `json:"service,omitempty"` // evaluate only this service
+	Rules   json.RawMessage    `json:"rules,omitempty"`   // rules of the services, structural.DEFAULT_SERVICE_RULES if not set
+}
+
+/*
+facadeEvaluate evaluates the services for a booking and replies with the decisions and their reasons, ie:
+
+	{"booking": {"lob": "Flights", "airline": "Indigo", "travel_date": "2023-06-01T10:00:00Z", "amount": 500000, "cancellation_reason": "AIRLINE"}}
+*/
+func facadeEvaluate(c *fiber.Ctx) error {
+	req := facadeRequest{}
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	if req.Booking.TravelDate.IsZero() {
+		return errorJSON(c, fiber.StatusBadRequest, fmt.Errorf("Booking travel_date is required!"))
+	}
+
+	rules := []byte(structural.DEFAULT_SERVICE_RULES)
+	if len(req.Rules) > 0 {
+		rules = req.Rules
+	}
+	services, err := structural.LoadServices(rules)
+	if err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	if req.Service != "" {
+		filtered := services[:0]
+		for _, svc := range services {
+			if strings.EqualFold(svc.Name, req.Service) {
+				filtered = append(filtered, svc)
+			}
+		}
+		if len(filtered) == 0 {
+			return errorJSON(c, fiber.StatusNotFound, fmt.Errorf("Unknown service: %q", req.Service))
+		}
+		services = filtered
+	}
+	return c.JSON(map[string]interface{}{"success": true, "decisions": structural.EvaluateServices(services, req.Booking)})
+}
+
+func bookingRoutes(c *fiber.Ctx) error {
+	return c.JSON(map[string]interface{}{"orgs": creational.BookingOrgs(), "routes": creational.DemoBookingEngine().Routes()})
+}
+
+/*
+bookingCreate books seats via the factory of the org, ie:
+
+	{"org": "ixigo", "route_id": "6E-201", "passengers": [{"name": "Harry", "age": 41}]}
+*/
+func bookingCreate(c *fiber.Ctx) error {
+	req := creational.BookingRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	b, err := creational.DemoBookingEngine().Book(req)
+	if err != nil {
+		return bookingError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(map[string]interface{}{"success": true, "booking": b})
+}
+
+func bookingGet(c *fiber.Ctx) error {
+	b, err := creational.DemoBookingEngine().Get(c.Params("pnr"))
+	if err != nil {
+		return bookingError(c, err)
+	}
+	return c.JSON(map[string]interface{}{"success": true, "booking": b})
+}
+
+func bookingCancel(c *fiber.Ctx) error {
+	b, err := creational.DemoBookingEngine().Cancel(c.Params("pnr"))
+	if err != nil {
+		return bookingError(c, err)
+	}
+	return c.JSON(map[string]interface{}{"success": true, "booking": b})
+}
+
+func bookingError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, creational.ErrBookingNotFound), errors.Is(err, creational.ErrRouteNotFound):
+		return errorJSON(c, fiber.StatusNotFound, err)
+	case errors.Is(err, creational.ErrSoldOut), errors.Is(err, creational.ErrAlreadyCancelled), errors.Is(err, creational.ErrDeparted):
+		return errorJSON(c, fiber.StatusConflict, err)
+	}
+	return errorJSON(c, fiber.StatusBadRequest, err)
+}
+
+/*
+flyweightRoster loads ?students=N students with and without the shared houses and reports the memory used
+*/
+func flyweightRoster(c *fiber.Ctx) error {
+	n, err := strconv.Atoi(c.Query("students", "1000"))
+	if err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, structural.ErrInvalidStudents)
+	}
+	report, err := structural.MeasureRoster(n)
+	if err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	return c.JSON(map[string]interface{}{"success": true, "report": report})
+}
+
+func prototypeNames(c *fiber.Ctx) error {
+	return c.JSON(map[string]interface{}{"prototypes": creational.Prototypes().Names()})
+}
+
+/*
+prototypeClone clones the named prototype, optionally renaming the clone ie {"name": "billing"}
+*/
+func prototypeClone(c *fiber.Ctx) error {
+	req := struct {
+		Name string `json:"name"`
+	}{}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errorJSON(c, fiber.StatusBadRequest, err)
+		}
+	}
+	cloned, err := creational.Prototypes().Clone(c.Params("name"))
+	if err != nil {
+		return errorJSON(c, fiber.StatusNotFound, err)
+	}
+	inode, ok := cloned.(creational.Inode)
+	if !ok {
+		return c.JSON(map[string]interface{}{"success": true, "clone": cloned})
+	}
+	if req.Name != "" {
+		inode.SetName(req.Name)
+	}
+	return c.JSON(map[string]interface{}{"success": true, "clone": inode.View()})
+}
+
+type evalRequest struct {
+	Expression string             `json:"expression"`
+	Variables  map[string]float64 `json:"variables"`
+}
+
+/*
+stackEval evaluates an infix expression, ie:
+
+	{"expression": "max(2, x) * -(3 + 4.5) ^ 2", "variables": {"x": 3}}
+
+It replies with the value and the RPN, an invalid expression replies 400 with the column of the error.
+*/
+func stackEval(c *fiber.Ctx) error {
+	req := evalRequest{}
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	e, err := stack.Parse(req.Expression)
+	if err != nil {
+		return exprError(c, err)
+	}
+	value, err := e.Evaluate(req.Variables)
+	if err != nil {
+		return exprError(c, err)
+	}
+	return c.JSON(map[string]interface{}{"success": true, "value": value, "rpn": e.RPN()})
+}
+
+func exprError(c *fiber.Ctx, err error) error {
+	res := map[string]interface{}{"success": false, "error": err.Error()}
+	var exprErr *stack.ExprError
+	if errors.As(err, &exprErr) {
+		res["column"] = exprErr.Column
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(res)
+}
+
+type balancedRequest struct {
+	Input string `json:"input"`
+	HTML  bool   `json:"html"`
+}
+
+/*
+stackBalanced checks the brackets of the input, and its tags with "html": true, ie:
+
+	{"input": "<p>(a [b])</p>", "html": true}
+
+Unbalanced input is not a failed request, it replies balanced false with the position of the first error.
+*/
+func stackBalanced(c *fiber.Ctx) error {
+	req := balancedRequest{}
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	res := map[string]interface{}{"success": true, "balanced": true}
+	if err := stack.CheckBalanced(req.Input, req.HTML); err != nil {
+		res["balanced"] = false
+		res["error"] = err.Error()
+		var balanceErr *stack.BalanceError
+		if errors.As(err, &balanceErr) {
+			res["position"] = balanceErr
+		}
+	}
+	return c.JSON(res)
+}
+
+// cacheStats of the demo LRU cache, with its keys from the most to the least recently used
+func cacheStats(c *fiber.Ctx) error {
+	lru := cache.Demo()
+	return c.JSON(map[string]interface{}{"success": true, "stats": lru.Stats(), "keys": lru.Keys()})
+}
+
+/*
+cacheKey copies the key of the path: fiber reuses the buffer of the request once the handler
+returns (the app is not Immutable), so c.Params must not be kept, ie as a key of the cache.
+*/
+func cacheKey(c *fiber.Ctx) string {
+	return utils.CopyString(c.Params("key"))
+}
+
+func cacheGet(c *fiber.Ctx) error {
+	key := cacheKey(c)
+	value, ok := cache.Demo().Get(key)
+	if !ok {
+		return errorJSON(c, fiber.StatusNotFound, fmt.Errorf("Key not found: %q", key))
+	}
+	return c.JSON(map[string]interface{}{"success": true, "key": key, "value": value})
+}
+
+type cacheRequest struct {
+	Value json.RawMessage `json:"value"`
+	TTL   string          `json:"ttl,omitempty"` // ie "30s", cache.DEMO_TTL if not set, "0s" never expires
+}
+
+/*
+cachePut sets the JSON value of the key, evicting the least recently used key once full, ie:
+
+	{"value": {"name": "Harry"}, "ttl": "30s"}
+*/
+func cachePut(c *fiber.Ctx) error {
+	req := cacheRequest{}
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	if len(req.Value) == 0 {
+		return errorJSON(c, fiber.StatusBadRequest, fmt.Errorf("Cache value is required!"))
+	}
+	ttl := cache.DEMO_TTL
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil {
+			return errorJSON(c, fiber.StatusBadRequest, err)
+		}
+	}
+	key := cacheKey(c)
+	if err := cache.Demo().SetWithTTL(key, req.Value, ttl); err != nil {
+		return errorJSON(c, fiber.StatusBadRequest, err)
+	}
+	return c.JSON(map[string]interface{}{"success": true, "key": key, "ttl": ttl.String()})
+}
+
+func cacheDelete(c *fiber.Ctx) error {
+	key := cacheKey(c)
+	if !cache.Demo().Delete(key) {
+		return errorJSON(c, fiber.StatusNotFound, fmt.Errorf("Key not found: %q", key))
+	}
+	return c.JSON(map[string]interface{}{"success": true, "key": key})
+}
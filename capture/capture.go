@@ -0,0 +1,106 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+Capture the output of an example, so that it can be sent back in the HTTP response
+instead of being written to the stdout of the server.
+
+Every example writes to the io.Writer it receives (fmt.Fprintln(w, ...)) rather than to os.Stdout.
+Each call to Run creates its own Buffer, hence concurrent requests never share the output,
+and os.Stdout is never swapped.
+
+Examples spawn goroutines which write to the same writer, so the Buffer guards writes with a mutex.
+*/
+
+// Buffer is an io.Writer which is safe for concurrent use
+type Buffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *Buffer) Write(p []byte) (n int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// String returns everything written so far
+func (b *Buffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Lines returns everything written so far, split on new lines
+func (b *Buffer) Lines() (lines []string) {
+	out := strings.TrimSuffix(b.String(), "\n")
+	if out == "" {
+		return []string{}
+	}
+	return strings.Split(out, "\n")
+}
+
+// Result of a single example run
+type Result struct {
+	Example    string      `json:"example,omitempty"`
+	Success    bool        `json:"success"`
+	Output     []string    `json:"output"`
+	Duration   string      `json:"duration"`
+	DurationMs float64     `json:"duration_ms"`
+	Error      interface{} `json:"error"`
+	Err        error       `json:"-"` // the error itself, so the caller can classify it
+}
+
+// Func is the signature of an example, it writes its output to w
+type Func func(w io.Writer) error
+
+/*
+Run executes fn with a new Buffer and collects its output, duration and error.
+A panic in fn is recovered and reported as an error.
+
+Some examples block (ie waiting on stdin or an OS signal) and do not listen to any context,
+so fn runs in its own goroutine and Run returns as soon as ctx is done, with the output written so far.
+*/
+func Run(ctx context.Context, name string, fn Func) (res Result) {
+	buf := &Buffer{}
+	start := time.Now()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("Example panicked: %v", r)
+			}
+		}()
+		done <- fn(buf)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("Example did not finish: %w", ctx.Err())
+	}
+
+	elapsed := time.Since(start)
+	res = Result{
+		Example:    name,
+		Success:    err == nil,
+		Output:     buf.Lines(),
+		Duration:   elapsed.String(),
+		DurationMs: float64(elapsed.Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Error, res.Err = err.Error(), err
+	}
+	return
+}
@@ -0,0 +1,104 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name            string
+		fn              Func
+		output_expected []string
+		success         bool
+	}{
+		{
+			name:            "Output-TC-1",
+			fn:              func(w io.Writer) error { fmt.Fprintln(w, "a"); fmt.Fprint(w, "b"); return nil },
+			output_expected: []string{"a", "b"},
+			success:         true,
+		},
+		{
+			name:            "Error-TC-2",
+			fn:              func(w io.Writer) error { fmt.Fprintln(w, "a"); return fmt.Errorf("failed") },
+			output_expected: []string{"a"},
+		},
+		{
+			name:            "Panic-TC-3",
+			fn:              func(w io.Writer) error { panic("boom") },
+			output_expected: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res := Run(context.Background(), tc.name, tc.fn)
+			if res.Success != tc.success {
+				t.Errorf("Failed: Actual success: %v, Expected success: %v", res.Success, tc.success)
+			}
+			if fmt.Sprint(res.Output) != fmt.Sprint(tc.output_expected) {
+				t.Errorf("Failed: Actual output: %q, Expected output: %q", res.Output, tc.output_expected)
+			}
+			if !tc.success && res.Error == nil {
+				t.Errorf("Failed: Expected an error")
+			}
+		})
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+	res := Run(ctx, "", func(w io.Writer) error {
+		fmt.Fprintln(w, "waiting")
+		<-block
+		return nil
+	})
+	if res.Success || !errors.Is(res.Err, context.DeadlineExceeded) {
+		t.Errorf("Failed: Expected the run to time out, got: %+v", res)
+	}
+	if len(res.Output) != 1 || res.Output[0] != "waiting" {
+		t.Errorf("Failed: Actual output: %q, Expected output: [waiting]", res.Output)
+	}
+}
+
+// Concurrent runs must never see each other's output
+func TestRunConcurrent(t *testing.T) {
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res := Run(context.Background(), "", func(w io.Writer) error {
+				inner := sync.WaitGroup{}
+				for j := 0; j < 10; j++ {
+					inner.Add(1)
+					go func() {
+						defer inner.Done()
+						fmt.Fprintln(w, i)
+					}()
+				}
+				inner.Wait()
+				return nil
+			})
+			if len(res.Output) != 10 {
+				t.Errorf("Failed: Actual lines: %v, Expected lines: 10", len(res.Output))
+			}
+			for _, line := range res.Output {
+				if line != fmt.Sprint(i) {
+					t.Errorf("Failed: Actual output: %v, Expected output: %v", line, i)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -3,6 +3,7 @@ package channels
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"sync"
@@ -13,78 +14,70 @@ import (
 type GoChannel struct {
 }
 
-func (gc GoChannel) RoutineOne() {
-	fmt.Println("RoutineOne")
+func (gc GoChannel) RoutineOne(w io.Writer) {
+	fmt.Fprintln(w, "RoutineOne")
 
 	messageList := []int{1, 2, 3, 4}
 	for key, msg := range messageList {
-		fmt.Println(key, msg)
+		fmt.Fprintln(w, key, msg)
 	}
 }
 
-func (gc GoChannel) CheckOsSignal() {
-	ctx, cancel := context.WithCancel(context.Background())
+/*
+CheckOsSignal starts 2 goroutines and stops them via context cancellation, once the process receives
+SIGINT (Ctrl+C) or SIGTERM ie kill <pid>, or ctx is done (ie the timeout of the example).
+SIGKILL can not be caught, the process is killed without any chance to clean up.
+*/
+func (gc GoChannel) CheckOsSignal(ctx context.Context, w io.Writer) {
+	// the context is cancelled on the first signal, signal.NotifyContext does the same
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	SigChan := make(chan os.Signal, 1) // we need to reserve to buffer size 1, so the notifier are not blocked
+	signal.Notify(SigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(SigChan)
 
 	go func() {
-		signal.Notify(SigChan, os.Interrupt, syscall.SIGINT, syscall.SIGKILL, syscall.SIGTERM)
-		cancel()
+		select {
+		case sig := <-SigChan:
+			fmt.Fprintln(w, "Received signal:", sig)
+			cancel()
+		case <-ctx.Done():
+		}
 	}()
 
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
-	fmt.Println("Start: Goroutine-1")
+	fmt.Fprintln(w, "Start: Goroutine-1")
 	go func(wg *sync.WaitGroup) {
 		defer wg.Done()
-		select {
-
-		case <-ctx.Done():
-			fmt.Println("End: Goroutine-1")
-			return
-			/*
-				case <-SigChan:
-					fmt.Println("Goroutine-1")
-					wg.Done()
-					return
-			*/
-		}
+		<-ctx.Done()
+		fmt.Fprintln(w, "End: Goroutine-1")
 	}(wg)
 	wg.Add(1)
-	fmt.Println("Start: Goroutine-2")
+	fmt.Fprintln(w, "Start: Goroutine-2")
 	go func(wg *sync.WaitGroup) {
 		defer wg.Done()
-		select {
-
-		case <-ctx.Done():
-			fmt.Println("End: Goroutine-2")
-
-			return
-			/*
-				case <-SigChan:
-					fmt.Println("Goroutine-2")
-					wg.Done()
-					return
-			*/
-		}
+		<-ctx.Done()
+		fmt.Fprintln(w, "End: Goroutine-2")
 	}(wg)
 
-	fmt.Println("Waiting for goroutines to end.....")
+	fmt.Fprintf(w, "Waiting for goroutines to end, send SIGINT/SIGTERM to process %d.....\n", os.Getpid())
 	wg.Wait()
-	fmt.Println("Shutdown Goroutines....")
+	fmt.Fprintln(w, "Shutdown Goroutines....")
 }
 
-func (gc GoChannel) ShutdownViaChannel() {
+func (gc GoChannel) ShutdownViaChannel(w io.Writer) {
 	SigChan := make(chan os.Signal, 1) // we need to reserve to buffer size 1, so the notifier are not blocked
-	signal.Notify(SigChan, os.Interrupt, syscall.SIGINT, syscall.SIGKILL, syscall.SIGTERM)
+	signal.Notify(SigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(SigChan)
 
 	for {
 		select {
 		case <-SigChan:
-			fmt.Println("Stop Working....")
+			fmt.Fprintln(w, "Stop Working....")
 			return
 		case <-time.After(1 * time.Second):
-			fmt.Println("Working....")
+			fmt.Fprintln(w, "Working....")
 		}
 	}
 }
@@ -0,0 +1,75 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type syncBuffer struct {
+	mulock sync.Mutex
+	buf    bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mulock.Lock()
+	defer b.mulock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mulock.Lock()
+	defer b.mulock.Unlock()
+	return b.buf.String()
+}
+
+func TestCheckOsSignal(t *testing.T) {
+	testCases := []struct {
+		name            string
+		signal          bool
+		output_expected string
+	}{
+		{name: "Signal-TC-1", signal: true, output_expected: "Received signal: terminated"},
+		{name: "Timeout-TC-2", output_expected: "Shutdown Goroutines...."},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			if tc.signal {
+				ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+			}
+			defer cancel()
+
+			w := &syncBuffer{}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				GoChannel{}.CheckOsSignal(ctx, w)
+			}()
+			if tc.signal {
+				// the signal is sent only once it is being caught, else it would terminate the test
+				for !strings.Contains(w.String(), "Waiting for goroutines") {
+					time.Sleep(time.Millisecond)
+				}
+				syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+			}
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Expected CheckOsSignal to return")
+			}
+			output := w.String()
+			if !strings.Contains(output, tc.output_expected) || !strings.Contains(output, "End: Goroutine-1") || !strings.Contains(output, "End: Goroutine-2") {
+				t.Errorf("Expected %q, got: %v", tc.output_expected, output)
+			}
+			if tc.signal != strings.Contains(output, "Received signal") {
+				t.Errorf("Unexpected output: %v", output)
+			}
+		})
+	}
+}
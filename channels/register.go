@@ -0,0 +1,21 @@
+package channels
+
+import (
+	"context"
+	"examples/registry"
+	"io"
+)
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "channel",
+		Title:       "Channel OS Signal",
+		Category:    registry.CATEGORY_CHANNEL,
+		Description: "Stop goroutines via context cancellation on SIGINT/SIGTERM, or the timeout of the example",
+		Source:      "channels/basic.go",
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			GoChannel{}.CheckOsSignal(ctx, w)
+			return nil
+		},
+	})
+}
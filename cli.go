@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"examples/capture"
+	"examples/registry"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+/*
+Command line runner, it runs the same registered examples as the Fiber server,
+with the arguments of an example as flags ie:
+
+	go_examples run pattern/creational/object-pool -workers 10 -wait 200 -timeout 5s
+*/
+
+func list(args []string) (err error) {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the examples as JSON")
+	if err = fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	all := registry.All()
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(all)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCATEGORY\tFLAGS\tTITLE")
+	for _, e := range all {
+		flags := make([]string, 0, len(e.Params))
+		for _, p := range e.Params {
+			flags = append(flags, "-"+p.Name)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Name, e.Category, strings.Join(flags, " "), e.Title)
+	}
+	return tw.Flush()
+}
+
+/*
+run executes a single example, its output is streamed to stdout and a summary is printed to stderr.
+With -json only the captured result is printed.
+It returns an error (exit code 1) when the example fails or does not finish within -timeout or before SIGINT/SIGTERM.
+*/
+func run(ctx context.Context, args []string) (err error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("%w: run needs the name of an example, see: go_examples list", errUsage)
+	}
+	e, ok := registry.Get(args[0])
+	if !ok {
+		return fmt.Errorf("%w: unknown example %q, see: go_examples list", errUsage, args[0])
+	}
+
+	fs := flag.NewFlagSet("run "+e.Name, flag.ContinueOnError)
+	timeout := fs.Duration("timeout", 30*time.Second, "time the example may run")
+	asJSON := fs.Bool("json", false, "print the captured result as JSON")
+	values := map[string]*string{}
+	for _, p := range e.Params {
+		values[p.Name] = fs.String(p.Name, p.Default, fmt.Sprintf("%s (%s)", p.Usage, p.Type))
+	}
+	if err = fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	set := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		if val, ok := values[f.Name]; ok {
+			set[f.Name] = *val
+		}
+	})
+	exArgs, err := e.Args(set)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	// Ctrl+C cancels the example, rather than killing it
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	res := capture.Run(ctx, e.Title, func(w io.Writer) error {
+		if !*asJSON {
+			w = io.MultiWriter(w, os.Stdout)
+		}
+		return e.Run(ctx, w, exArgs)
+	})
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(res); err != nil {
+			return
+		}
+	}
+	if !res.Success {
+		return fmt.Errorf("--- FAIL: %s (%s): %v", e.Name, res.Duration, res.Error)
+	}
+	if !*asJSON {
+		fmt.Fprintf(os.Stderr, "\n--- ok: %s (%s)\n", e.Name, res.Duration)
+	}
+	return
+}
+
+func docs(args []string) (err error) {
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	postmanFile := fs.String("postman", "", "write the Postman collection to this file")
+	openapiFile := fs.String("openapi", "", "write the OpenAPI document to this file")
+	baseURL := fs.String("base-url", "http://localhost:3000/golang", "URL under which the examples are served")
+	if err = fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if *postmanFile == "" && *openapiFile == "" {
+		return fmt.Errorf("%w: docs needs -postman and/or -openapi", errUsage)
+	}
+
+	generate := []struct {
+		file string
+		gen  func(string) ([]byte, error)
+	}{
+		{*postmanFile, registry.Postman},
+		{*openapiFile, registry.OpenAPI},
+	}
+	for _, g := range generate {
+		if g.file == "" {
+			continue
+		}
+		var doc []byte
+		if doc, err = g.gen(*baseURL); err != nil {
+			return
+		}
+		if err = os.WriteFile(g.file, append(doc, '\n'), 0644); err != nil {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "Generated: ", g.file)
+	}
+	return
+}
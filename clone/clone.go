@@ -0,0 +1,157 @@
+package clone
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+/*
+DeepCopy returns a copy of src sharing no memory with it, as per misc.CopyDeepShallow
+only the value types are deep copied by an assignment.
+
+  - pointers, slices and maps are copied along with the data they refer to
+  - unexported fields are copied as well
+  - the references are copied once, so shared and cyclic references (ie a child pointing back
+    to its parent) point to the same copy in the result, rather than recursing forever
+  - channels, functions and unsafe pointers can not be copied and are shared
+  - *time.Location is immutable and shared, so times keep comparing equal via ==
+*/
+func DeepCopy[T any](src T) (dst T) {
+	return DeepCopyShare(src)
+}
+
+/*
+DeepCopyShare is DeepCopy, except the given pointers, maps or slices are shared rather than copied
+wherever they are referenced, ie the parent of a node being copied or a DB handle.
+*/
+func DeepCopyShare[T any](src T, share ...interface{}) (dst T) {
+	c := copier{visited: map[visit]reflect.Value{}}
+	for _, s := range share {
+		v := reflect.ValueOf(s)
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Map:
+			c.visited[visit{ptr: unsafe.Pointer(v.Pointer()), typ: v.Type()}] = v
+		case reflect.Slice:
+			c.visited[visit{ptr: unsafe.Pointer(v.Pointer()), typ: v.Type(), len: v.Len()}] = v
+		}
+	}
+	c.copy(reflect.ValueOf(&dst).Elem(), reflect.ValueOf(&src).Elem())
+	return
+}
+
+// shared are the types which are immutable, hence not copied
+var shared = map[reflect.Type]bool{
+	reflect.TypeOf((*time.Location)(nil)): true,
+}
+
+// visit identifies a reference, the type disambiguates a struct and its first field
+type visit struct {
+	ptr unsafe.Pointer
+	typ reflect.Type
+	len int
+}
+
+type copier struct {
+	visited map[visit]reflect.Value
+}
+
+/*
+copy sets dst, an addressable value of the same type, to a deep copy of src
+*/
+func (c *copier) copy(dst, src reflect.Value) {
+	if shared[src.Type()] {
+		dst.Set(src)
+		return
+	}
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		key := visit{ptr: unsafe.Pointer(src.Pointer()), typ: src.Type()}
+		if v, ok := c.visited[key]; ok {
+			dst.Set(v)
+			return
+		}
+		v := reflect.New(src.Type().Elem())
+		c.visited[key] = v
+		c.copy(v.Elem(), src.Elem())
+		dst.Set(v)
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		key := visit{ptr: unsafe.Pointer(src.Pointer()), typ: src.Type()}
+		if v, ok := c.visited[key]; ok {
+			dst.Set(v)
+			return
+		}
+		v := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.visited[key] = v
+		for it := src.MapRange(); it.Next(); {
+			k, e := reflect.New(src.Type().Key()).Elem(), reflect.New(src.Type().Elem()).Elem()
+			c.copy(k, it.Key())
+			c.copy(e, it.Value())
+			v.SetMapIndex(k, e)
+		}
+		dst.Set(v)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		key := visit{ptr: unsafe.Pointer(src.Pointer()), typ: src.Type(), len: src.Len()}
+		if v, ok := c.visited[key]; ok {
+			dst.Set(v)
+			return
+		}
+		v := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		c.visited[key] = v
+		for i := 0; i < src.Len(); i++ {
+			c.copy(v.Index(i), src.Index(i))
+		}
+		dst.Set(v)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			c.copy(dst.Index(i), src.Index(i))
+		}
+
+	case reflect.Struct:
+		if !src.CanAddr() {
+			// ie a map value, the unexported fields are only reachable via an address
+			v := reflect.New(src.Type()).Elem()
+			v.Set(src)
+			src = v
+		}
+		for i := 0; i < src.NumField(); i++ {
+			c.copy(exported(dst.Field(i)), exported(src.Field(i)))
+		}
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		v := reflect.New(src.Elem().Type()).Elem()
+		c.copy(v, src.Elem())
+		dst.Set(v)
+
+	default:
+		// value types along with channels, functions and unsafe pointers
+		dst.Set(src)
+	}
+}
+
+/*
+exported returns the field of an addressable struct such that it can be read and set,
+even when it is unexported.
+*/
+func exported(field reflect.Value) reflect.Value {
+	if field.CanSet() {
+		return field
+	}
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+}
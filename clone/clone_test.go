@@ -0,0 +1,113 @@
+package clone
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type node struct {
+	Name     string
+	parent   *node
+	children []*node
+	attrs    map[string][]string
+	any      interface{}
+}
+
+func TestDeepCopyValues(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name  string
+		input interface{}
+	}{
+		{name: "Int-TC-1", input: 42},
+		{name: "Array-TC-2", input: [3]int{1, 2, 3}},
+		{name: "Slice-TC-3", input: []int{1, 2, 3}},
+		{name: "Map-TC-4", input: map[string][]int{"a": {1}, "b": {2, 3}}},
+		{name: "Pointer-TC-5", input: &struct{ Name string }{"Harry"}},
+		{name: "NilValues-TC-6", input: struct {
+			S []int
+			M map[int]int
+			P *int
+			I interface{}
+		}{}},
+		{name: "Time-TC-7", input: struct{ At time.Time }{now}},
+		{name: "MapOfStructs-TC-8", input: map[string]node{"x": {Name: "x", attrs: map[string][]string{"k": {"v"}}}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if output := DeepCopy(tc.input); !reflect.DeepEqual(tc.input, output) {
+				t.Errorf("Expected: %v, got: %v", tc.input, output)
+			}
+		})
+	}
+	if c := DeepCopy(now); c != now {
+		t.Errorf("Expected the times to be ==, got: %v - %v", now, c)
+	}
+}
+
+func TestDeepCopyIndependent(t *testing.T) {
+	src := map[string][]int{"a": {1, 2}}
+	dst := DeepCopy(src)
+	dst["a"][0], dst["b"] = 99, []int{3}
+	if src["a"][0] != 1 || len(src) != 1 {
+		t.Errorf("Expected the source to be unaffected, got: %v", src)
+	}
+
+	n := &node{Name: "root", attrs: map[string][]string{"owner": {"harry"}}, any: &node{Name: "boxed"}}
+	c := DeepCopy(n)
+	c.attrs["owner"][0] = "ron"
+	c.any.(*node).Name = "changed"
+	if n.attrs["owner"][0] != "harry" || n.any.(*node).Name != "boxed" {
+		t.Errorf("Expected the unexported fields to be deep copied, got: %+v", n)
+	}
+}
+
+func TestDeepCopyCycles(t *testing.T) {
+	root := &node{Name: "root"}
+	child := &node{Name: "child", parent: root}
+	root.children = []*node{child, child} // shared reference
+	root.any = root                       // self reference
+
+	c := DeepCopy(root)
+	switch {
+	case c == root || c.children[0] == child:
+		t.Fatal("Expected new nodes")
+	case c.children[0].parent != c:
+		t.Error("Expected the parent of the copied child to be the copied root")
+	case c.children[0] != c.children[1]:
+		t.Error("Expected the shared reference to be copied once")
+	case c.any.(*node) != c:
+		t.Error("Expected the self reference to point to the copy")
+	}
+}
+
+func TestDeepCopyShared(t *testing.T) {
+	ch := make(chan int)
+	fn := func() {}
+	c := DeepCopy(struct {
+		Ch chan int
+		Fn func()
+	}{ch, fn})
+	if c.Ch != ch || c.Fn == nil {
+		t.Errorf("Expected channels and functions to be shared")
+	}
+}
+
+func TestDeepCopyShare(t *testing.T) {
+	root := &node{Name: "root"}
+	child := &node{Name: "child", parent: root, attrs: map[string][]string{"k": {"v"}}}
+	root.children = []*node{child}
+
+	c := DeepCopyShare(child, root)
+	if c == child || c.parent != root || len(root.children) != 1 {
+		t.Errorf("Expected the child to be copied with the same parent, got: %+v", c)
+	}
+	if c = DeepCopyShare(child, child.attrs); c.attrs["k"][0] != "v" {
+		t.Errorf("Expected the shared map in the copy")
+	}
+	c.attrs["k"] = nil
+	if child.attrs["k"] != nil {
+		t.Errorf("Expected the map to be shared")
+	}
+}
@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const (
+	DEMO_CAPACITY = 100
+	DEMO_TTL      = 10 * time.Minute
+)
+
+var (
+	demoOnce  sync.Once
+	demoCache *LRUCache[string, json.RawMessage]
+)
+
+// Demo cache of JSON values shared by the API, DEMO_CAPACITY entries expiring after DEMO_TTL by default
+func Demo() *LRUCache[string, json.RawMessage] {
+	demoOnce.Do(func() {
+		demoCache, _ = NewLRUCache(DEMO_CAPACITY, Options[string, json.RawMessage]{TTL: DEMO_TTL})
+	})
+	return demoCache
+}
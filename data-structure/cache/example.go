@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+func LRUExample(w io.Writer) error {
+	c, err := NewLRUCache(3, Options[string, int]{
+		OnEvict: func(key string, value int, why Reason) {
+			fmt.Fprintf(w, "Evicted %s=%d (%s)\n", key, value, why)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	fmt.Fprintln(w, "Keys, most recent first: ", c.Keys())
+
+	if v, ok := c.Get("a"); ok {
+		fmt.Fprintln(w, "Get a: ", v, "Keys: ", c.Keys())
+	}
+	c.Set("d", 4) // b is the least recently used
+	if _, ok := c.Get("b"); !ok {
+		fmt.Fprintln(w, "Get b: miss")
+	}
+
+	c.SetWithTTL("e", 5, 50*time.Millisecond)
+	time.Sleep(60 * time.Millisecond)
+	if _, ok := c.Get("e"); !ok {
+		fmt.Fprintln(w, "Get e after its TTL: miss")
+	}
+	c.Delete("a")
+	fmt.Fprintln(w, "Keys: ", c.Keys())
+
+	s := c.Stats()
+	fmt.Fprintf(w, "Stats: len %d/%d, %d hits, %d misses, %d evictions, %d expirations\n",
+		s.Len, s.Capacity, s.Hits, s.Misses, s.Evictions, s.Expirations)
+	return nil
+}
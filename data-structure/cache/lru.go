@@ -0,0 +1,265 @@
+package cache
+
+import (
+	"errors"
+	"examples/data-structure/linklist"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+LRUCache keeps at most capacity entries, once full a new key evicts the Least Recently Used one.
+
+A map gives the node of a key in a doubly linked list ordered by use, the most recent at the front:
+Get moves the node to the front and the eviction removes the back, both are O(1) via the node handle
+(see linklist.ListDouble), so is Set.
+
+An entry expires after its TTL, checked lazily: an expired entry is a miss and is removed by the Get
+(or Peek) reaching it or by RemoveExpired, till then it takes a slot and is the first evicted once
+it is the least recently used.
+OnEvict is called for every entry leaving the cache but the ones replaced by Set, with the reason,
+after the cache is unlocked, so it can use the cache.
+
+It is safe for concurrent use.
+*/
+
+type Reason string
+
+const (
+	REASON_CAPACITY Reason = "capacity" // least recently used, evicted to make room
+	REASON_EXPIRED  Reason = "expired"
+	REASON_DELETED  Reason = "deleted" // by Delete or Clear
+)
+
+var (
+	ErrCapacity = errors.New("Capacity should be at least 1!")
+	ErrTTL      = errors.New("TTL can not be negative!")
+)
+
+type Options[K comparable, V any] struct {
+	TTL     time.Duration                    // of the entries by default, 0 never expires
+	OnEvict func(key K, value V, why Reason) // optional
+}
+
+type Stats struct {
+	Len         int   `json:"len"`
+	Capacity    int   `json:"capacity"`
+	Hits        int64 `json:"hits"`
+	Misses      int64 `json:"misses"`
+	Evictions   int64 `json:"evictions"`   // REASON_CAPACITY
+	Expirations int64 `json:"expirations"` // REASON_EXPIRED
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time // zero never
+}
+
+type evicted[K comparable, V any] struct {
+	entry[K, V]
+	why Reason
+}
+
+type LRUCache[K comparable, V any] struct {
+	mulock   sync.Mutex
+	capacity int
+	opts     Options[K, V]
+	items    map[K]*linklist.NodeDouble[entry[K, V]]
+	order    *linklist.ListDouble[entry[K, V]] // most recently used at the front
+	stats    Stats
+	now      func() time.Time
+}
+
+func NewLRUCache[K comparable, V any](capacity int, opts Options[K, V]) (*LRUCache[K, V], error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrCapacity, capacity)
+	}
+	if opts.TTL < 0 {
+		return nil, fmt.Errorf("%w: %v", ErrTTL, opts.TTL)
+	}
+	return &LRUCache[K, V]{
+		capacity: capacity,
+		opts:     opts,
+		items:    make(map[K]*linklist.NodeDouble[entry[K, V]], capacity),
+		order:    linklist.InitListDouble[entry[K, V]](),
+		now:      time.Now,
+	}, nil
+}
+
+// Get the value of the key, marking it as the most recently used
+func (c *LRUCache[K, V]) Get(key K) (value V, ok bool) {
+	c.mulock.Lock()
+	node, gone := c.lookup(key)
+	if node == nil {
+		c.stats.Misses++
+	} else {
+		c.stats.Hits++
+		c.order.MoveToFront(node)
+		value, ok = node.Data.value, true
+	}
+	c.mulock.Unlock()
+	c.notify(gone)
+	return
+}
+
+// Peek gets the value of the key without marking it as used, nor counting a hit or a miss
+func (c *LRUCache[K, V]) Peek(key K) (value V, ok bool) {
+	c.mulock.Lock()
+	node, gone := c.lookup(key)
+	if node != nil {
+		value, ok = node.Data.value, true
+	}
+	c.mulock.Unlock()
+	c.notify(gone)
+	return
+}
+
+// Set the value of the key with the default TTL, evicting the least recently used entry if full
+func (c *LRUCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.opts.TTL)
+}
+
+// SetWithTTL sets the value of the key expiring after ttl, 0 never expires
+func (c *LRUCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) (err error) {
+	if ttl < 0 {
+		return fmt.Errorf("%w: %v", ErrTTL, ttl)
+	}
+	now := c.now()
+	e := entry[K, V]{key: key, value: value}
+	if ttl > 0 {
+		e.expires = now.Add(ttl)
+	}
+
+	var gone []evicted[K, V]
+	c.mulock.Lock()
+	if node, ok := c.items[key]; ok {
+		node.Data = e
+		c.order.MoveToFront(node)
+	} else {
+		if back := c.order.Back(); c.order.Len() >= c.capacity {
+			why := REASON_CAPACITY
+			if back.Data.expired(now) {
+				why = REASON_EXPIRED
+			}
+			gone = append(gone, c.remove(back, why))
+		}
+		c.items[key] = c.order.AddFront(e)
+	}
+	c.mulock.Unlock()
+	c.notify(gone)
+	return
+}
+
+// Delete the key, false if it was not cached
+func (c *LRUCache[K, V]) Delete(key K) (ok bool) {
+	var gone []evicted[K, V]
+	c.mulock.Lock()
+	if node, found := c.items[key]; found {
+		gone = append(gone, c.remove(node, REASON_DELETED))
+		ok = true
+	}
+	c.mulock.Unlock()
+	c.notify(gone)
+	return
+}
+
+// Clear deletes all the entries, the stats are kept
+func (c *LRUCache[K, V]) Clear() {
+	var gone []evicted[K, V]
+	c.mulock.Lock()
+	for node := c.order.Back(); node != nil; node = c.order.Back() {
+		gone = append(gone, c.remove(node, REASON_DELETED))
+	}
+	c.mulock.Unlock()
+	c.notify(gone)
+}
+
+// RemoveExpired removes all the expired entries, in O(n), and returns how many
+func (c *LRUCache[K, V]) RemoveExpired() int {
+	var gone []evicted[K, V]
+	c.mulock.Lock()
+	now := c.now()
+	for node := c.order.Front(); node != nil; {
+		next := node.Next()
+		if node.Data.expired(now) {
+			gone = append(gone, c.remove(node, REASON_EXPIRED))
+		}
+		node = next
+	}
+	c.mulock.Unlock()
+	c.notify(gone)
+	return len(gone)
+}
+
+// Keys from the most to the least recently used, without the expired ones
+func (c *LRUCache[K, V]) Keys() (keys []K) {
+	c.mulock.Lock()
+	defer c.mulock.Unlock()
+	keys = make([]K, 0, c.order.Len())
+	now := c.now()
+	for node := c.order.Front(); node != nil; node = node.Next() {
+		if !node.Data.expired(now) {
+			keys = append(keys, node.Data.key)
+		}
+	}
+	return
+}
+
+// Len of the cache, the expired entries not yet removed included
+func (c *LRUCache[K, V]) Len() int {
+	c.mulock.Lock()
+	defer c.mulock.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUCache[K, V]) Stats() (s Stats) {
+	c.mulock.Lock()
+	defer c.mulock.Unlock()
+	s = c.stats
+	s.Len, s.Capacity = c.order.Len(), c.capacity
+	return
+}
+
+/*
+lookup returns the node of the key, nil if missing or expired, an expired one is removed and
+returned in gone. The cache must be locked.
+*/
+func (c *LRUCache[K, V]) lookup(key K) (node *linklist.NodeDouble[entry[K, V]], gone []evicted[K, V]) {
+	node, ok := c.items[key]
+	if !ok {
+		return nil, nil
+	}
+	if node.Data.expired(c.now()) {
+		return nil, append(gone, c.remove(node, REASON_EXPIRED))
+	}
+	return node, nil
+}
+
+// remove the node of the cache, counting the eviction. The cache must be locked.
+func (c *LRUCache[K, V]) remove(node *linklist.NodeDouble[entry[K, V]], why Reason) evicted[K, V] {
+	c.order.Remove(node)
+	delete(c.items, node.Data.key)
+	switch why {
+	case REASON_CAPACITY:
+		c.stats.Evictions++
+	case REASON_EXPIRED:
+		c.stats.Expirations++
+	}
+	return evicted[K, V]{entry: node.Data, why: why}
+}
+
+// notify OnEvict of the removed entries, the cache must not be locked
+func (c *LRUCache[K, V]) notify(gone []evicted[K, V]) {
+	if c.opts.OnEvict == nil {
+		return
+	}
+	for _, e := range gone {
+		c.opts.OnEvict(e.key, e.value, e.why)
+	}
+}
+
+func (e entry[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	mulock sync.Mutex
+	now    time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mulock.Lock()
+	defer c.mulock.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mulock.Lock()
+	defer c.mulock.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type eviction struct {
+	key   string
+	value int
+	why   Reason
+}
+
+func testCache(t *testing.T, capacity int, ttl time.Duration) (c *LRUCache[string, int], clock *testClock, evictions *[]eviction) {
+	t.Helper()
+	evictions = new([]eviction)
+	c, err := NewLRUCache(capacity, Options[string, int]{TTL: ttl, OnEvict: func(key string, value int, why Reason) {
+		*evictions = append(*evictions, eviction{key, value, why})
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock = &testClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c.now = clock.Now
+	return
+}
+
+func TestLRUCache(t *testing.T) {
+	testCases := []struct {
+		name               string
+		ttl                time.Duration
+		ops                func(c *LRUCache[string, int], clock *testClock)
+		keys_expected      []string // most recent first
+		evictions_expected []eviction
+		stats_expected     Stats
+	}{
+		{name: "Evict-LRU-TC-1", ops: func(c *LRUCache[string, int], clock *testClock) {
+			c.Set("a", 1)
+			c.Set("b", 2)
+			c.Set("c", 3)
+			c.Get("a")
+			c.Set("d", 4)
+		}, keys_expected: []string{"d", "a", "c"}, evictions_expected: []eviction{{"b", 2, REASON_CAPACITY}},
+			stats_expected: Stats{Len: 3, Capacity: 3, Hits: 1, Evictions: 1}},
+		{name: "Replace-TC-2", ops: func(c *LRUCache[string, int], clock *testClock) {
+			c.Set("a", 1)
+			c.Set("b", 2)
+			c.Set("a", 10) // no eviction, a is the most recent
+			c.Get("z")
+		}, keys_expected: []string{"a", "b"}, stats_expected: Stats{Len: 2, Capacity: 3, Misses: 1}},
+		{name: "Peek-TC-3", ops: func(c *LRUCache[string, int], clock *testClock) {
+			c.Set("a", 1)
+			c.Set("b", 2)
+			c.Peek("a")
+		}, keys_expected: []string{"b", "a"}, stats_expected: Stats{Len: 2, Capacity: 3}},
+		{name: "TTL-TC-4", ttl: time.Minute, ops: func(c *LRUCache[string, int], clock *testClock) {
+			c.Set("a", 1)
+			c.SetWithTTL("b", 2, 0) // never expires
+			c.SetWithTTL("c", 3, 2*time.Minute)
+			clock.Add(time.Minute)
+			c.Get("a")
+		}, keys_expected: []string{"c", "b"}, evictions_expected: []eviction{{"a", 1, REASON_EXPIRED}},
+			stats_expected: Stats{Len: 2, Capacity: 3, Misses: 1, Expirations: 1}},
+		{name: "Expired-Back-TC-5", ops: func(c *LRUCache[string, int], clock *testClock) {
+			c.SetWithTTL("a", 1, time.Second)
+			c.Set("b", 2)
+			c.Set("c", 3)
+			clock.Add(time.Second)
+			c.Set("d", 4)
+		}, keys_expected: []string{"d", "c", "b"}, evictions_expected: []eviction{{"a", 1, REASON_EXPIRED}},
+			stats_expected: Stats{Len: 3, Capacity: 3, Expirations: 1}},
+		{name: "RemoveExpired-TC-6", ttl: time.Second, ops: func(c *LRUCache[string, int], clock *testClock) {
+			c.Set("a", 1)
+			c.Set("b", 2)
+			clock.Add(time.Second)
+			c.Set("c", 3)
+			if n := c.RemoveExpired(); n != 2 {
+				panic(fmt.Sprint("removed ", n))
+			}
+		}, keys_expected: []string{"c"}, evictions_expected: []eviction{{"b", 2, REASON_EXPIRED}, {"a", 1, REASON_EXPIRED}},
+			stats_expected: Stats{Len: 1, Capacity: 3, Expirations: 2}},
+		{name: "Delete-Clear-TC-7", ops: func(c *LRUCache[string, int], clock *testClock) {
+			c.Set("a", 1)
+			c.Set("b", 2)
+			c.Set("c", 3)
+			c.Delete("b")
+			c.Delete("b")
+			c.Clear()
+		}, keys_expected: []string{}, evictions_expected: []eviction{{"b", 2, REASON_DELETED}, {"a", 1, REASON_DELETED}, {"c", 3, REASON_DELETED}},
+			stats_expected: Stats{Len: 0, Capacity: 3}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, clock, evictions := testCache(t, 3, tc.ttl)
+			tc.ops(c, clock)
+			if keys := c.Keys(); !reflect.DeepEqual(keys, tc.keys_expected) {
+				t.Errorf("Expected keys %v, got: %v", tc.keys_expected, keys)
+			}
+			if !reflect.DeepEqual(*evictions, tc.evictions_expected) {
+				t.Errorf("Expected evictions %v, got: %v", tc.evictions_expected, *evictions)
+			}
+			if s := c.Stats(); s != tc.stats_expected {
+				t.Errorf("Expected stats %+v, got: %+v", tc.stats_expected, s)
+			}
+		})
+	}
+}
+
+func TestLRUCacheValues(t *testing.T) {
+	c, _, _ := testCache(t, 2, 0)
+	c.Set("a", 1)
+	c.Set("a", 2)
+	if v, ok := c.Get("a"); !ok || v != 2 {
+		t.Errorf("Expected 2, got: %d %t", v, ok)
+	}
+	if v, ok := c.Get("b"); ok || v != 0 {
+		t.Errorf("Expected a miss, got: %d %t", v, ok)
+	}
+}
+
+func TestNewLRUCache(t *testing.T) {
+	testCases := []struct {
+		name         string
+		capacity     int
+		ttl          time.Duration
+		err_expected error
+	}{
+		{name: "Valid-TC-1", capacity: 1},
+		{name: "Capacity-TC-2", capacity: 0, err_expected: ErrCapacity},
+		{name: "TTL-TC-3", capacity: 1, ttl: -time.Second, err_expected: ErrTTL},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewLRUCache(tc.capacity, Options[string, int]{TTL: tc.ttl}); !errors.Is(err, tc.err_expected) {
+				t.Errorf("Expected error %v, got: %v", tc.err_expected, err)
+			}
+		})
+	}
+	c, _, _ := testCache(t, 1, 0)
+	if err := c.SetWithTTL("a", 1, -time.Second); !errors.Is(err, ErrTTL) || c.Len() != 0 {
+		t.Errorf("Expected %v and nothing set, got: %v (len %d)", ErrTTL, err, c.Len())
+	}
+}
+
+// OnEvict runs once the cache is unlocked, so it can use the cache
+func TestOnEvictReentrant(t *testing.T) {
+	var c *LRUCache[string, int]
+	c, _ = NewLRUCache(1, Options[string, int]{OnEvict: func(key string, value int, why Reason) {
+		if key == "a" {
+			c.Set("evicted."+key, value)
+		}
+	}})
+	c.Set("a", 1)
+	c.Set("b", 2) // evicts a, which sets evicted.a, which evicts b in turn
+	if keys := c.Keys(); !reflect.DeepEqual(keys, []string{"evicted.a"}) {
+		t.Errorf("Expected [evicted.a], got: %v", keys)
+	}
+}
+
+func TestLRUCacheConcurrent(t *testing.T) {
+	c, _ := NewLRUCache(50, Options[int, int]{})
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := (g*31 + i) % 100
+				if _, ok := c.Get(key); !ok {
+					c.Set(key, i)
+				}
+				if i%10 == 0 {
+					c.Delete(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	s := c.Stats()
+	if s.Len > 50 || s.Len != len(c.Keys()) || s.Hits+s.Misses != 8000 {
+		t.Errorf("Expected at most 50 entries and 8000 lookups, got: %+v, %d keys", s, len(c.Keys()))
+	}
+}
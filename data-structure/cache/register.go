@@ -0,0 +1,20 @@
+package cache
+
+import (
+	"context"
+	"examples/registry"
+	"io"
+)
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "lru-cache",
+		Title:       "LRU Cache",
+		Category:    registry.CATEGORY_DATA_STRUCTURE,
+		Description: "Thread-safe LRU cache on a map and a doubly linked list: O(1) get/set, TTL, eviction callbacks and hit/miss stats",
+		Source:      "data-structure/cache/lru.go",
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return LRUExample(w)
+		},
+	})
+}
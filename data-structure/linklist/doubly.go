@@ -1,156 +1,224 @@
 package linklist
 
 import (
+	"errors"
+	"examples/iterator"
 	"fmt"
+	"io"
 )
 
-type NodeDouble struct {
-	Data interface{}
-	Next *NodeDouble
-	Prev *NodeDouble
+/*
+Doubly linked list: every node points to the next and the previous one, so a node can be
+inserted, removed or moved anywhere in O(1) given its node, without walking the list.
+
+The add and insert operations return the node of the data, a handle to the element: it stays
+valid while the node is in the list, whatever is added or removed around it. The handles of
+another list (or removed ones) are rejected with ErrNotInList. See cache.LRUCache built on it.
+
+The list does not print, see DoublyListExample. It is not safe for concurrent use.
+*/
+
+var ErrNotInList = errors.New("Node is not in the list!")
+
+type NodeDouble[T any] struct {
+	Data T
+	next *NodeDouble[T]
+	prev *NodeDouble[T]
+	list *ListDouble[T] // nil once removed
 }
 
-type ListDouble struct {
-	Head *NodeDouble
-	Tail *NodeDouble
-	Len  int
+// Next node, nil at the tail
+func (n *NodeDouble[T]) Next() *NodeDouble[T] {
+	return n.next
 }
 
-func InitListDouble() (ld *ListDouble) {
-	return &ListDouble{}
+// Prev node, nil at the head
+func (n *NodeDouble[T]) Prev() *NodeDouble[T] {
+	return n.prev
 }
 
-func (ld *ListDouble) Size() (l int) {
-	return ld.Len
+type ListDouble[T any] struct {
+	head *NodeDouble[T]
+	tail *NodeDouble[T]
+	len  int
 }
 
-func (ld *ListDouble) AddFront(data interface{}) {
-	fmt.Println("Add Front Node: ", data)
-	node := &NodeDouble{Data: data}
-	if ld.Head == nil { // || ld.Len == 0
-		ld.Head = node
-		ld.Tail = node
-	} else {
-		node.Next = ld.Head
-		ld.Head.Prev = node
-		ld.Head = node
-	}
-	ld.Len++
+func InitListDouble[T any]() (ld *ListDouble[T]) {
+	return &ListDouble[T]{}
 }
 
-/*
- */
-func (ld *ListDouble) AddBack(data interface{}) {
-	fmt.Println("Add Back Node: ", data)
-	node := &NodeDouble{Data: data}
-	if ld.Len == 0 { // || ld.Tail == nil
-		ld.Head = node
-		ld.Tail = node
-	} else {
-		node.Prev = ld.Tail
-		ld.Tail.Next = node
-		ld.Tail = node
-	}
-	ld.Len++
+func (ld *ListDouble[T]) Len() int {
+	return ld.len
+}
+
+// Front is the head node, nil when the list is empty
+func (ld *ListDouble[T]) Front() *NodeDouble[T] {
+	return ld.head
 }
 
-func (ld *ListDouble) DeleteFront() {
-	fmt.Println("Delete Front Node")
-	if ld.Len == 0 {
-		fmt.Printf("List is Empty !!")
+// Back is the tail node, nil when the list is empty
+func (ld *ListDouble[T]) Back() *NodeDouble[T] {
+	return ld.tail
+}
+
+func (ld *ListDouble[T]) AddFront(data T) *NodeDouble[T] {
+	return ld.link(&NodeDouble[T]{Data: data}, nil, ld.head)
+}
+
+func (ld *ListDouble[T]) AddBack(data T) *NodeDouble[T] {
+	return ld.link(&NodeDouble[T]{Data: data}, ld.tail, nil)
+}
+
+// InsertBefore adds data just before the mark node
+func (ld *ListDouble[T]) InsertBefore(data T, mark *NodeDouble[T]) (node *NodeDouble[T], err error) {
+	if err = ld.check(mark); err != nil {
 		return
 	}
+	return ld.link(&NodeDouble[T]{Data: data}, mark.prev, mark), nil
+}
 
-	if ld.Len == 1 {
-		ld.Head = nil
-		ld.Tail = nil
-	} else {
-		firstNode := ld.Head
-		ld.Head = firstNode.Next // point head to second node
-		firstNode.Next = nil     // remove first node
-		ld.Head.Prev = nil       // new first node
+// InsertAfter adds data just after the mark node
+func (ld *ListDouble[T]) InsertAfter(data T, mark *NodeDouble[T]) (node *NodeDouble[T], err error) {
+	if err = ld.check(mark); err != nil {
+		return
 	}
-	ld.Len--
+	return ld.link(&NodeDouble[T]{Data: data}, mark, mark.next), nil
 }
 
-func (ld *ListDouble) DeleteBack() {
-	fmt.Println("Delete Back Node")
-	if ld.Len == 0 {
-		fmt.Printf("List is Empty !!")
+func (ld *ListDouble[T]) DeleteFront() (data T, err error) {
+	if ld.head == nil {
+		err = ErrEmpty
 		return
 	}
-	if ld.Len == 1 {
-		ld.Head = nil
-		ld.Tail = nil
-	} else {
-		lastNode := ld.Tail
-		ld.Tail = lastNode.Prev
-		lastNode.Prev = nil
-		ld.Tail.Next = nil
-		lastNode = nil
-	}
-	ld.Len--
+	return ld.Remove(ld.head)
 }
 
-func (ld *ListDouble) TraverseForward() {
-	fmt.Print("Traverse Forward: ")
+func (ld *ListDouble[T]) DeleteBack() (data T, err error) {
+	if ld.tail == nil {
+		err = ErrEmpty
+		return
+	}
+	return ld.Remove(ld.tail)
+}
 
-	if ld.Len == 0 { //|| ld.Head == nil {
-		fmt.Printf("List is Empty !!")
+// Remove the node from the list and return its data, the node can not be used with the list anymore
+func (ld *ListDouble[T]) Remove(node *NodeDouble[T]) (data T, err error) {
+	if err = ld.check(node); err != nil {
 		return
 	}
+	ld.unlink(node)
+	node.list = nil
+	return node.Data, nil
+}
 
-	current := ld.Head
-	for current != nil {
-		fmt.Printf("<->%v", current.Data)
-		current = current.Next
+// MoveToFront moves the node to the head of the list
+func (ld *ListDouble[T]) MoveToFront(node *NodeDouble[T]) (err error) {
+	if err = ld.check(node); err != nil || node == ld.head {
+		return
 	}
+	ld.unlink(node)
+	ld.link(node, nil, ld.head)
+	return
+}
 
+// MoveToBack moves the node to the tail of the list
+func (ld *ListDouble[T]) MoveToBack(node *NodeDouble[T]) (err error) {
+	if err = ld.check(node); err != nil || node == ld.tail {
+		return
+	}
+	ld.unlink(node)
+	ld.link(node, ld.tail, nil)
+	return
 }
 
-func (ld *ListDouble) TraverseReverse() {
-	fmt.Print("\nTraverse Reverse: ")
+func (ld *ListDouble[T]) check(node *NodeDouble[T]) error {
+	if node == nil || node.list != ld {
+		return ErrNotInList
+	}
+	return nil
+}
 
-	if ld.Len == 0 { //|| ld.Tail == nil {
-		fmt.Printf("List is Empty !!")
-		return
+// link the node between prev and next, either is nil at the ends
+func (ld *ListDouble[T]) link(node, prev, next *NodeDouble[T]) *NodeDouble[T] {
+	node.prev, node.next, node.list = prev, next, ld
+	if prev == nil {
+		ld.head = node
+	} else {
+		prev.next = node
 	}
+	if next == nil {
+		ld.tail = node
+	} else {
+		next.prev = node
+	}
+	ld.len++
+	return node
+}
 
-	current := ld.Tail
-	for current != nil {
-		fmt.Printf("<->%v", current.Data)
-		current = current.Prev
+func (ld *ListDouble[T]) unlink(node *NodeDouble[T]) {
+	if node.prev == nil {
+		ld.head = node.next
+	} else {
+		node.prev.next = node.next
 	}
+	if node.next == nil {
+		ld.tail = node.prev
+	} else {
+		node.next.prev = node.prev
+	}
+	node.prev, node.next = nil, nil
+	ld.len--
+}
 
+// Iter from head to tail
+func (ld *ListDouble[T]) Iter() iterator.Iterator[T] {
+	return iterDouble(ld.head, func(n *NodeDouble[T]) *NodeDouble[T] { return n.next })
 }
 
-func DoublyListExample() {
-	ld := InitListDouble()
+// IterReverse from tail to head
+func (ld *ListDouble[T]) IterReverse() iterator.Iterator[T] {
+	return iterDouble(ld.tail, func(n *NodeDouble[T]) *NodeDouble[T] { return n.prev })
+}
 
-	list := []int{1, 2, 3, 4, 5}
-	//list := []string{"Father", "Mother", "Son", "Daughter"}
+func iterDouble[T any](current *NodeDouble[T], next func(*NodeDouble[T]) *NodeDouble[T]) iterator.Iterator[T] {
+	return iterator.Func[T](func() (data T, ok bool) {
+		if current == nil {
+			return
+		}
+		data = current.Data
+		current = next(current)
+		return data, true
+	})
+}
 
-	fmt.Println("List Length: ", ld.Size())
-	ld.TraverseForward()
-	ld.TraverseReverse()
-	fmt.Println()
+func DoublyListExample(w io.Writer) {
+	ld := InitListDouble[int]()
+	if _, err := ld.DeleteFront(); err != nil {
+		fmt.Fprintln(w, "Delete Front: ", err)
+	}
 
-	for _, v := range list {
-		//ld.AddFront(v)
-		ld.AddBack(v)
+	nodes := map[int]*NodeDouble[int]{}
+	for _, v := range []int{1, 2, 3, 4, 5} {
+		nodes[v] = ld.AddBack(v)
 	}
+	fmt.Fprintln(w, "Forward: ", iterator.Collect(ld.Iter()), "Reverse: ", iterator.Collect(ld.IterReverse()), "Len: ", ld.Len())
 
-	fmt.Println("List Length: ", ld.Size())
-	ld.TraverseForward()
-	ld.TraverseReverse()
+	ld.InsertBefore(0, nodes[1])
+	ld.InsertAfter(35, nodes[3])
+	fmt.Fprintln(w, "Insert 0 before 1 and 35 after 3: ", iterator.Collect(ld.Iter()))
 
-	fmt.Println()
+	ld.MoveToFront(nodes[4])
+	ld.MoveToBack(nodes[1])
+	fmt.Fprintln(w, "Move 4 to the front and 1 to the back: ", iterator.Collect(ld.Iter()))
+
+	removed, _ := ld.Remove(nodes[3])
+	fmt.Fprintln(w, "Remove ", removed, ": ", iterator.Collect(ld.Iter()))
+	if _, err := ld.Remove(nodes[3]); err != nil {
+		fmt.Fprintln(w, "Remove again: ", err)
+	}
 
-	ld.DeleteBack()
-	fmt.Println("List Length: ", ld.Size())
-	ld.TraverseForward()
-	ld.DeleteFront()
-	fmt.Println("List Length: ", ld.Size())
-	ld.TraverseForward()
-	ld.TraverseReverse()
+	back, _ := ld.DeleteBack()
+	front, _ := ld.DeleteFront()
+	fmt.Fprintln(w, "Delete Back: ", back, "Delete Front: ", front, "Forward: ", iterator.Collect(ld.Iter()),
+		"Reverse: ", iterator.Collect(ld.IterReverse()))
 }
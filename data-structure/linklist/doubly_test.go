@@ -0,0 +1,119 @@
+package linklist
+
+import (
+	"errors"
+	"examples/iterator"
+	"reflect"
+	"testing"
+)
+
+// checkDouble checks the data both ways, so the next and prev links agree
+func checkDouble(t *testing.T, ld *ListDouble[int], expected []int) {
+	t.Helper()
+	reversed := make([]int, len(expected))
+	for i, v := range expected {
+		reversed[len(expected)-1-i] = v
+	}
+	forward, backward := iterator.Collect(ld.Iter()), iterator.Collect(ld.IterReverse())
+	if !reflect.DeepEqual(forward, expected) || !reflect.DeepEqual(backward, reversed) || ld.Len() != len(expected) {
+		t.Errorf("Expected %v, got: %v reverse %v (len %d)", expected, forward, backward, ld.Len())
+	}
+}
+
+func TestListDouble(t *testing.T) {
+	testCases := []struct {
+		name            string
+		ops             func(ld *ListDouble[int], nodes []*NodeDouble[int]) error // nodes of 1, 2, 3
+		output_expected []int
+		err_expected    error
+	}{
+		{name: "InsertBefore-Head-TC-1", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			_, err := ld.InsertBefore(0, nodes[0])
+			return err
+		}, output_expected: []int{0, 1, 2, 3}},
+		{name: "InsertAfter-Tail-TC-2", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			_, err := ld.InsertAfter(4, nodes[2])
+			return err
+		}, output_expected: []int{1, 2, 3, 4}},
+		{name: "Insert-Middle-TC-3", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			ld.InsertAfter(15, nodes[0])
+			_, err := ld.InsertBefore(25, nodes[2])
+			return err
+		}, output_expected: []int{1, 15, 2, 25, 3}},
+		{name: "Remove-Middle-TC-4", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			_, err := ld.Remove(nodes[1])
+			return err
+		}, output_expected: []int{1, 3}},
+		{name: "Remove-All-TC-5", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			for _, n := range nodes {
+				ld.Remove(n)
+			}
+			return nil
+		}, output_expected: []int{}},
+		{name: "Remove-Twice-TC-6", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			ld.Remove(nodes[0])
+			_, err := ld.Remove(nodes[0])
+			return err
+		}, output_expected: []int{2, 3}, err_expected: ErrNotInList},
+		{name: "Foreign-Node-TC-7", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			other := InitListDouble[int]()
+			_, err := ld.InsertAfter(9, other.AddBack(9))
+			return err
+		}, output_expected: []int{1, 2, 3}, err_expected: ErrNotInList},
+		{name: "MoveToFront-TC-8", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			ld.MoveToFront(nodes[1])
+			return ld.MoveToFront(nodes[2])
+		}, output_expected: []int{3, 2, 1}},
+		{name: "MoveToFront-Head-TC-9", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			return ld.MoveToFront(nodes[0])
+		}, output_expected: []int{1, 2, 3}},
+		{name: "MoveToBack-TC-10", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			return ld.MoveToBack(nodes[0])
+		}, output_expected: []int{2, 3, 1}},
+		{name: "Delete-Ends-TC-11", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			ld.DeleteFront()
+			_, err := ld.DeleteBack()
+			return err
+		}, output_expected: []int{2}},
+		{name: "Delete-Empty-TC-12", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			ld.DeleteFront()
+			ld.DeleteFront()
+			ld.DeleteBack()
+			_, err := ld.DeleteBack()
+			return err
+		}, output_expected: []int{}, err_expected: ErrEmpty},
+		{name: "Nil-Node-TC-13", ops: func(ld *ListDouble[int], nodes []*NodeDouble[int]) error {
+			return ld.MoveToBack(nil)
+		}, output_expected: []int{1, 2, 3}, err_expected: ErrNotInList},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ld := InitListDouble[int]()
+			nodes := []*NodeDouble[int]{ld.AddBack(2)}
+			nodes = append([]*NodeDouble[int]{ld.AddFront(1)}, nodes...)
+			nodes = append(nodes, ld.AddBack(3))
+
+			if err := tc.ops(ld, nodes); !errors.Is(err, tc.err_expected) {
+				t.Fatalf("Expected error %v, got: %v", tc.err_expected, err)
+			}
+			checkDouble(t, ld, tc.output_expected)
+		})
+	}
+}
+
+func TestNodeDoubleHandles(t *testing.T) {
+	ld := InitListDouble[int]()
+	two := ld.AddBack(2)
+	ld.AddFront(1)
+	ld.AddBack(3)
+	if two.Prev().Data != 1 || two.Next().Data != 3 || ld.Front().Prev() != nil || ld.Back().Next() != nil {
+		t.Errorf("Expected 1 <-> 2 <-> 3, got: %v <-> 2 <-> %v", two.Prev().Data, two.Next().Data)
+	}
+	ld.Remove(two)
+	if two.Prev() != nil || two.Next() != nil {
+		t.Errorf("Expected a removed node to be unlinked, got: %v %v", two.Prev(), two.Next())
+	}
+	if ld.Front().Next() != ld.Back() || ld.Back().Prev() != ld.Front() {
+		t.Errorf("Expected 1 <-> 3 after removing 2")
+	}
+}
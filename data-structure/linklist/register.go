@@ -0,0 +1,22 @@
+package linklist
+
+import "examples/registry"
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "linklist/single",
+		Title:       "Linklist Single",
+		Category:    registry.CATEGORY_DATA_STRUCTURE,
+		Description: "Generic singly linked list: positional insert/remove, reverse, middle and k-th from the end, merge sort and cycle detection",
+		Source:      "data-structure/linklist/single.go",
+		Run:         registry.Simple(LinklistExample),
+	})
+	registry.Register(registry.Example{
+		Name:        "linklist/double",
+		Title:       "Linklist Double",
+		Category:    registry.CATEGORY_DATA_STRUCTURE,
+		Description: "Generic doubly linked list: O(1) insert before/after, remove and move to front/back via node handles, traverse both ways",
+		Source:      "data-structure/linklist/doubly.go",
+		Run:         registry.Simple(DoublyListExample),
+	})
+}
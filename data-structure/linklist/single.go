@@ -1,244 +1,383 @@
 package linklist
 
 import (
+	"errors"
+	"examples/iterator"
 	"fmt"
+	"io"
 )
 
-type Node struct {
-	Data interface{}
-	Next *Node
+/*
+Singly linked list: every node points to the next one, the list keeps the head and the tail,
+so adding at both ends is O(1). Deleting at the back is O(n), the node before the tail has to be
+found from the head. The positions (index) start at 0.
+
+The list does not print, the caller does with what the operations return, see LinklistExample.
+It is not safe for concurrent use.
+*/
+
+var (
+	ErrEmpty = errors.New("List is Empty!")
+	ErrIndex = errors.New("Index out of range!")
+)
+
+type Node[T any] struct {
+	Data T
+	Next *Node[T]
 }
 
-type LinkList struct {
-	Head *Node
-	Len  int
+type List[T any] struct {
+	head *Node[T]
+	tail *Node[T]
+	len  int
 }
 
-func InitList() (ll *LinkList) {
-	ll = &LinkList{}
-	return
+func InitList[T any]() (ll *List[T]) {
+	return &List[T]{}
 }
 
-func (ll *LinkList) AddFront(data interface{}) {
-	fmt.Println("\nAdd Front - ", data)
-	node := &Node{Data: data}
-	if ll.Head == nil {
-		ll.Head = node
+// Head is the first node, nil when the list is empty
+func (ll *List[T]) Head() *Node[T] {
+	return ll.head
+}
+
+func (ll *List[T]) Len() int {
+	return ll.len
+}
+
+func (ll *List[T]) IsEmpty() bool {
+	return ll.len == 0
+}
+
+func (ll *List[T]) AddFront(data T) {
+	ll.head = &Node[T]{Data: data, Next: ll.head}
+	if ll.tail == nil {
+		ll.tail = ll.head
+	}
+	ll.len++
+}
+
+func (ll *List[T]) AddBack(data T) {
+	node := &Node[T]{Data: data}
+	if ll.tail == nil { // if list is empty
+		ll.head = node
 	} else {
-		node.Next = ll.Head
-		ll.Head = node
-	}
-	ll.Len++
-}
-func (ll *LinkList) AddBack(data interface{}) {
-	fmt.Println("\nAdd Back - ", data)
-	node := &Node{Data: data}
-	ll.Len++
-	if ll.Head == nil { // if list is empty
-		ll.Head = node
-	} else { // travese to end of list
-		current := ll.Head
-		for {
-			if current.Next != nil {
-				current = current.Next
-			} else {
-				current.Next = node
-				return
-			}
-		}
+		ll.tail.Next = node
 	}
+	ll.tail = node
+	ll.len++
 }
-func (ll *LinkList) DeleteFront() (err error) {
-	fmt.Println("Delete Front")
-	if ll.Head == nil {
-		err = fmt.Errorf("List is empty !!")
+
+func (ll *List[T]) DeleteFront() (data T, err error) {
+	if ll.head == nil {
+		err = ErrEmpty
 		return
 	}
-	ll.Head = ll.Head.Next
-	ll.Len--
-	return
+	node := ll.head
+	ll.head = node.Next
+	if ll.head == nil {
+		ll.tail = nil
+	}
+	node.Next = nil
+	ll.len--
+	return node.Data, nil
 }
 
-func (ll *LinkList) DeleteBack() (err error) {
-	fmt.Println("Delete Back")
-	if ll.Head == nil {
-		err = fmt.Errorf("List is empty !!")
+func (ll *List[T]) DeleteBack() (data T, err error) {
+	if ll.head == nil {
+		err = ErrEmpty
 		return
 	}
-	current := ll.Head
-	//prev := ll.Head
-	for current.Next.Next != nil {
-		//prev = current
-		current = current.Next
+	return ll.RemoveAt(ll.len - 1)
+}
+
+/*
+InsertAt adds data at the index, the nodes from the index on move one position.
+The index can be 0 to Len, Len adds at the back.
+*/
+func (ll *List[T]) InsertAt(index int, data T) (err error) {
+	if index < 0 || index > ll.len {
+		return fmt.Errorf("%w: %d of %d", ErrIndex, index, ll.len)
+	}
+	switch index {
+	case 0:
+		ll.AddFront(data)
+	case ll.len:
+		ll.AddBack(data)
+	default:
+		prev := ll.nodeAt(index - 1)
+		prev.Next = &Node[T]{Data: data, Next: prev.Next}
+		ll.len++
 	}
-	current.Next = nil
-	ll.Len--
 	return
 }
-func (ll *LinkList) Traverse() (err error) {
-	if ll.Head == nil {
-		err = fmt.Errorf("List is empty !!")
+
+// RemoveAt deletes the node at the index, 0 to Len-1, and returns its data
+func (ll *List[T]) RemoveAt(index int) (data T, err error) {
+	if index < 0 || index >= ll.len {
+		err = fmt.Errorf("%w: %d of %d", ErrIndex, index, ll.len)
 		return
 	}
-	current := ll.Head
-	fmt.Println()
-	for current != nil {
-		fmt.Printf("->%v", current.Data)
+	if index == 0 {
+		return ll.DeleteFront()
+	}
+	prev := ll.nodeAt(index - 1)
+	node := prev.Next
+	prev.Next = node.Next
+	if node == ll.tail {
+		ll.tail = prev
+	}
+	node.Next = nil
+	ll.len--
+	return node.Data, nil
+}
+
+// nodeAt returns the node at a valid index
+func (ll *List[T]) nodeAt(index int) (current *Node[T]) {
+	current = ll.head
+	for i := 0; i < index; i++ {
 		current = current.Next
 	}
 	return
 }
 
-func (ll *LinkList) Reverse() (err error) {
-	fmt.Println("Reverse List")
-	if ll.Head == nil {
-		err = fmt.Errorf("List is empty !!")
-		return
+// Find returns the data and the index of the first node matching, index is -1 when none does
+func (ll *List[T]) Find(match func(T) bool) (data T, index int) {
+	i := 0
+	for current := ll.head; current != nil; current = current.Next {
+		if match(current.Data) {
+			return current.Data, i
+		}
+		i++
 	}
+	return data, -1
+}
 
-	prev := ll.Head
-	current := ll.Head.Next
-	next := current.Next
-	for current.Next != nil {
+// IndexOf returns the index of the first node equal to data, -1 when none is
+func IndexOf[T comparable](ll *List[T], data T) (index int) {
+	_, index = ll.Find(func(d T) bool { return d == data })
+	return
+}
+
+// Reverse the list in place, by turning the next pointer of every node to the previous one
+func (ll *List[T]) Reverse() {
+	var prev *Node[T]
+	current := ll.head
+	for current != nil {
+		next := current.Next
 		current.Next = prev
-		prev = current
-		current = next
-		next = next.Next
+		prev, current = current, next
 	}
-	current.Next = prev
-	ll.Head.Next = nil
-	ll.Head = current
+	ll.head, ll.tail = ll.tail, ll.head
+}
 
-	return
+/*
+ReverseRecursive does the same as Reverse: the rest of the list after the head is reversed first,
+then the head is added after its last node (the old next of the head). It uses O(n) stack.
+*/
+func (ll *List[T]) ReverseRecursive() {
+	ll.head, ll.tail = reverseNodes(ll.head), ll.head
 }
 
-func LinklistExample() {
-	ll := InitList()
-	if err := ll.Traverse(); err != nil {
-		fmt.Println(err)
+func reverseNodes[T any](head *Node[T]) (newHead *Node[T]) {
+	if head == nil || head.Next == nil {
+		return head
 	}
-	ll.AddFront("a")
-	ll.AddBack("b")
-	ll.AddBack("c")
-	ll.AddBack("d")
+	newHead = reverseNodes(head.Next)
+	head.Next.Next = head
+	head.Next = nil
+	return
+}
 
-	if err := ll.Traverse(); err != nil {
-		fmt.Println(err)
-	}
-	ll.DeleteBack()
+/*
+HasCycle tells whether the nodes from head loop back, with Floyd's tortoise and hare:
+slow moves one node at a time, fast two, they meet only if there is a cycle.
+The list itself never has one, but its nodes can be linked by hand via Next.
+*/
+func HasCycle[T any](head *Node[T]) bool {
+	_, ok := CycleStart(head)
+	return ok
+}
 
-	if err := ll.Traverse(); err != nil {
-		fmt.Println(err)
+/*
+CycleStart returns the first node of the cycle: once slow and fast met, a pointer from head and
+one from the meeting node, moving one node at a time, meet at the start of the cycle.
+*/
+func CycleStart[T any](head *Node[T]) (start *Node[T], ok bool) {
+	slow, fast := head, head
+	for fast != nil && fast.Next != nil {
+		slow, fast = slow.Next, fast.Next.Next
+		if slow == fast {
+			for start = head; start != slow; start, slow = start.Next, slow.Next {
+			}
+			return start, true
+		}
 	}
-	ll.DeleteFront()
+	return nil, false
+}
 
-	if err := ll.Traverse(); err != nil {
-		fmt.Println(err)
+/*
+Middle returns the data of the middle node, the second of the two middle ones for an even length,
+via a fast pointer moving two nodes while the slow one moves one.
+*/
+func (ll *List[T]) Middle() (data T, err error) {
+	if ll.head == nil {
+		err = ErrEmpty
+		return
 	}
-
-	ll.AddFront("a")
-	ll.AddBack("d")
-
-	ll.Reverse()
-	if err := ll.Traverse(); err != nil {
-		fmt.Println(err)
+	slow, fast := ll.head, ll.head
+	for fast != nil && fast.Next != nil {
+		slow, fast = slow.Next, fast.Next.Next
 	}
+	return slow.Data, nil
+}
 
-	ll.Reverse()
-	if err := ll.Traverse(); err != nil {
-		fmt.Println(err)
+/*
+KthFromEnd returns the data of the k-th node from the end, k = 1 is the tail. A lead pointer
+goes k nodes ahead, then both move until it reaches the end, in a single pass.
+*/
+func (ll *List[T]) KthFromEnd(k int) (data T, err error) {
+	if k < 1 || k > ll.len {
+		err = fmt.Errorf("%w: %d from the end of %d", ErrIndex, k, ll.len)
+		return
+	}
+	lead, current := ll.head, ll.head
+	for i := 0; i < k; i++ {
+		lead = lead.Next
 	}
+	for lead != nil {
+		lead, current = lead.Next, current.Next
+	}
+	return current.Data, nil
 }
 
 /*
-func Create(data interface{}) (ptr *Node) {
-	fmt.Println("Create Linklist: ", data)
-	ptr = &Node{Data: data}
+MergeSorted merges two lists sorted by less into a new sorted one, a and b are left empty as
+their nodes are moved, not copied. On equal data the one of a comes first (stable).
+Merging a list with itself moves its nodes as they are, rather than linking them into a cycle.
+*/
+func MergeSorted[T any](a, b *List[T], less func(x, y T) bool) (merged *List[T]) {
+	if a == b {
+		merged = &List[T]{}
+		*merged, *a = *a, List[T]{}
+		return
+	}
+	merged = &List[T]{len: a.len + b.len}
+	merged.head, merged.tail = mergeNodes(a.head, b.head, less)
+	*a, *b = List[T]{}, List[T]{}
 	return
 }
-*/
-/*
-Add at 3 places
-- Start
-- End
-- Anywhere
-*/
-/*
-func Add(data interface{}, position int, head *Node) (newHead *Node, err error) {
-	fmt.Println("\nAdd Node :", data)
-
-	current := head
-	currPos := 1
-	for {
-		switch position {
-		case 0: // first position
-			newNode := &Node{Data: data, Ptr: current}
-			newHead = newNode
-			return
-		case 99: // last position
-			if current.Ptr != nil {
-				current = current.Ptr
-			} else {
-				newNode := &Node{Data: data}
-				current.Ptr = newNode
-				newHead = head
-				return
-			}
-		default: // any other position
-			if currPos == position {
-				newNode := &Node{Data: data, Ptr: current.Ptr}
-				current.Ptr = newNode
-				newHead = head
-				return
-			} else {
-				// move to next Node
-				if current.Ptr == nil {
-					err = fmt.Errorf("Position not valid!!")
-					return
-				}
-				current = current.Ptr
-			}
 
-			currPos++
+// mergeNodes returns the head and the tail of the merged nodes
+func mergeNodes[T any](a, b *Node[T], less func(x, y T) bool) (head, tail *Node[T]) {
+	dummy := &Node[T]{}
+	tail = dummy
+	for a != nil && b != nil {
+		if less(b.Data, a.Data) {
+			tail.Next, b = b, b.Next
+		} else {
+			tail.Next, a = a, a.Next
 		}
+		tail = tail.Next
+	}
+	if a == nil {
+		a = b
+	}
+	tail.Next = a
+	for tail.Next != nil {
+		tail = tail.Next
+	}
+	if tail == dummy {
+		return nil, nil
+	}
+	return dummy.Next, tail
+}
 
+// Sort the list by less with a merge sort, O(n log n) and stable, the nodes are relinked in place
+func (ll *List[T]) Sort(less func(x, y T) bool) {
+	ll.head, ll.tail = sortNodes(ll.head, less)
+}
+
+func sortNodes[T any](head *Node[T], less func(x, y T) bool) (*Node[T], *Node[T]) {
+	if head == nil || head.Next == nil {
+		return head, head
+	}
+	// split after the first middle node
+	slow, fast := head, head.Next
+	for fast != nil && fast.Next != nil {
+		slow, fast = slow.Next, fast.Next.Next
 	}
+	second := slow.Next
+	slow.Next = nil
 
+	a, _ := sortNodes(head, less)
+	b, _ := sortNodes(second, less)
+	return mergeNodes(a, b, less)
 }
-*/
-/*
-func Traverse(head *Node) {
-	fmt.Println("Traverse Linklist")
 
-	current := head
-	for {
-		if current.Ptr != nil {
-			fmt.Printf("->%v", current.Data)
-		} else {
-			fmt.Printf("->%v", current.Data)
-			break
+// Iter from the head to the end of the list
+func (ll *List[T]) Iter() iterator.Iterator[T] {
+	current := ll.head
+	return iterator.Func[T](func() (data T, ok bool) {
+		if current == nil {
+			return
 		}
-		current = current.Ptr
+		data = current.Data
+		current = current.Next
+		return data, true
+	})
+}
+
+func LinklistExample(w io.Writer) {
+	ll := InitList[string]()
+	if _, err := ll.DeleteBack(); err != nil {
+		fmt.Fprintln(w, "Delete Back: ", err)
+	}
+	ll.AddFront("a")
+	ll.AddBack("b")
+	ll.AddBack("d")
+	ll.InsertAt(2, "c")
+	fmt.Fprintln(w, "List: ", iterator.Collect(ll.Iter()), "Len: ", ll.Len())
+	if err := ll.InsertAt(9, "z"); err != nil {
+		fmt.Fprintln(w, "Insert At: ", err)
 	}
 
-}
-*/
-/*
-func LinklistExample() {
-	head := Create(1) // head(Node-1) -> [1,nil]
-	Traverse(head)
-	for i := 2; i <= 5; i++ {
-		head, _ = Add(i, 99, head) // append at the end of the list
-		//Traverse(head)
+	back, _ := ll.DeleteBack()
+	front, _ := ll.DeleteFront()
+	fmt.Fprintln(w, "Delete Back: ", back, "Delete Front: ", front, "List: ", iterator.Collect(ll.Iter()))
+
+	ll.AddFront("a")
+	ll.AddBack("d")
+	ll.Reverse()
+	fmt.Fprintln(w, "Reverse: ", iterator.Collect(ll.Iter()))
+	ll.ReverseRecursive()
+	fmt.Fprintln(w, "Reverse Recursive: ", iterator.Collect(ll.Iter()))
+
+	removed, _ := ll.RemoveAt(1)
+	fmt.Fprintln(w, "Remove At 1: ", removed, "Index Of d: ", IndexOf(ll, "d"), "List: ", iterator.Collect(ll.Iter()))
+
+	nums := InitList[int]()
+	for _, v := range []int{5, 3, 8, 1, 9, 2} {
+		nums.AddBack(v)
+	}
+	middle, _ := nums.Middle()
+	second, _ := nums.KthFromEnd(2)
+	fmt.Fprintln(w, "Numbers: ", iterator.Collect(nums.Iter()), "Middle: ", middle, "2nd from the end: ", second)
+	if v, i := nums.Find(func(v int) bool { return v > 5 }); i >= 0 {
+		fmt.Fprintln(w, "First > 5: ", v, "at ", i)
 	}
-	head, _ = Add(6, 0, head) // add at the start of the list
-	Traverse(head)
 
-	for i := 7; i <= 9; i++ {
-		head, _ = Add(i, i-2, head) // append at different positions of the list
-		//Traverse(head)
+	less := func(x, y int) bool { return x < y }
+	nums.Sort(less)
+	odds := InitList[int]()
+	for _, v := range []int{1, 3, 7} {
+		odds.AddBack(v)
 	}
-	Traverse(head)
+	fmt.Fprintln(w, "Sorted: ", iterator.Collect(nums.Iter()), "Merged with ", iterator.Collect(odds.Iter()), ": ",
+		iterator.Collect(MergeSorted(nums, odds, less).Iter()))
 
+	fmt.Fprintln(w, "Has Cycle: ", HasCycle(ll.Head()))
+	ll.tail.Next = ll.head // link by hand, the list methods would loop forever now
+	start, _ := CycleStart(ll.Head())
+	fmt.Fprintln(w, "Tail linked to the head, Has Cycle: ", HasCycle(ll.Head()), "starting at ", start.Data)
 }
-*/
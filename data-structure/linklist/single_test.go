@@ -0,0 +1,226 @@
+package linklist
+
+import (
+	"errors"
+	"examples/iterator"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func listOf(values ...int) *List[int] {
+	ll := InitList[int]()
+	for _, v := range values {
+		ll.AddBack(v)
+	}
+	return ll
+}
+
+// checkList checks the data from the head, the length and the tail
+func checkList(t *testing.T, ll *List[int], expected []int) {
+	t.Helper()
+	if got := iterator.Collect(ll.Iter()); !reflect.DeepEqual(got, expected) || ll.Len() != len(expected) {
+		t.Errorf("Expected %v, got: %v (len %d)", expected, got, ll.Len())
+	}
+	switch {
+	case len(expected) == 0 && (ll.head != nil || ll.tail != nil):
+		t.Errorf("Expected nil head and tail, got: %v %v", ll.head, ll.tail)
+	case len(expected) > 0 && (ll.tail == nil || ll.tail.Data != expected[len(expected)-1] || ll.tail.Next != nil):
+		t.Errorf("Expected tail %d, got: %v", expected[len(expected)-1], ll.tail)
+	}
+}
+
+func TestList(t *testing.T) {
+	testCases := []struct {
+		name            string
+		input           []int
+		ops             func(ll *List[int]) (int, error)
+		output_expected []int
+		data_expected   int
+		err_expected    error
+	}{
+		{name: "DeleteBack-Single-TC-1", input: []int{1}, ops: func(ll *List[int]) (int, error) { return ll.DeleteBack() },
+			output_expected: []int{}, data_expected: 1},
+		{name: "DeleteBack-TC-2", input: []int{1, 2, 3}, ops: func(ll *List[int]) (int, error) { return ll.DeleteBack() },
+			output_expected: []int{1, 2}, data_expected: 3},
+		{name: "DeleteBack-Empty-TC-3", ops: func(ll *List[int]) (int, error) { return ll.DeleteBack() },
+			output_expected: []int{}, err_expected: ErrEmpty},
+		{name: "DeleteFront-Single-TC-4", input: []int{1}, ops: func(ll *List[int]) (int, error) { return ll.DeleteFront() },
+			output_expected: []int{}, data_expected: 1},
+		{name: "AddBack-After-Delete-TC-5", input: []int{1}, ops: func(ll *List[int]) (int, error) {
+			ll.DeleteBack()
+			ll.AddBack(2)
+			ll.AddFront(1)
+			ll.AddBack(3)
+			return 0, nil
+		}, output_expected: []int{1, 2, 3}},
+		{name: "InsertAt-TC-6", input: []int{1, 3}, ops: func(ll *List[int]) (int, error) {
+			ll.InsertAt(0, 0)
+			ll.InsertAt(2, 2)
+			return 0, ll.InsertAt(4, 4)
+		}, output_expected: []int{0, 1, 2, 3, 4}},
+		{name: "InsertAt-Index-TC-7", input: []int{1}, ops: func(ll *List[int]) (int, error) { return 0, ll.InsertAt(2, 2) },
+			output_expected: []int{1}, err_expected: ErrIndex},
+		{name: "RemoveAt-Tail-TC-8", input: []int{1, 2, 3}, ops: func(ll *List[int]) (int, error) { return ll.RemoveAt(2) },
+			output_expected: []int{1, 2}, data_expected: 3},
+		{name: "RemoveAt-Middle-TC-9", input: []int{1, 2, 3}, ops: func(ll *List[int]) (int, error) { return ll.RemoveAt(1) },
+			output_expected: []int{1, 3}, data_expected: 2},
+		{name: "RemoveAt-Index-TC-10", input: []int{1}, ops: func(ll *List[int]) (int, error) { return ll.RemoveAt(-1) },
+			output_expected: []int{1}, err_expected: ErrIndex},
+		{name: "Reverse-Single-TC-11", input: []int{1}, ops: func(ll *List[int]) (int, error) { ll.Reverse(); return 0, nil },
+			output_expected: []int{1}},
+		{name: "Reverse-TC-12", input: []int{1, 2, 3}, ops: func(ll *List[int]) (int, error) { ll.Reverse(); return 0, nil },
+			output_expected: []int{3, 2, 1}},
+		{name: "Reverse-Empty-TC-13", ops: func(ll *List[int]) (int, error) { ll.Reverse(); ll.ReverseRecursive(); return 0, nil },
+			output_expected: []int{}},
+		{name: "ReverseRecursive-Single-TC-14", input: []int{1}, ops: func(ll *List[int]) (int, error) { ll.ReverseRecursive(); return 0, nil },
+			output_expected: []int{1}},
+		{name: "ReverseRecursive-TC-15", input: []int{1, 2, 3, 4}, ops: func(ll *List[int]) (int, error) { ll.ReverseRecursive(); return 0, nil },
+			output_expected: []int{4, 3, 2, 1}},
+		{name: "Middle-Odd-TC-16", input: []int{1, 2, 3}, ops: func(ll *List[int]) (int, error) { return ll.Middle() },
+			output_expected: []int{1, 2, 3}, data_expected: 2},
+		{name: "Middle-Even-TC-17", input: []int{1, 2, 3, 4}, ops: func(ll *List[int]) (int, error) { return ll.Middle() },
+			output_expected: []int{1, 2, 3, 4}, data_expected: 3},
+		{name: "Middle-Empty-TC-18", ops: func(ll *List[int]) (int, error) { return ll.Middle() },
+			output_expected: []int{}, err_expected: ErrEmpty},
+		{name: "KthFromEnd-TC-19", input: []int{1, 2, 3, 4}, ops: func(ll *List[int]) (int, error) { return ll.KthFromEnd(4) },
+			output_expected: []int{1, 2, 3, 4}, data_expected: 1},
+		{name: "KthFromEnd-Index-TC-20", input: []int{1, 2}, ops: func(ll *List[int]) (int, error) { return ll.KthFromEnd(3) },
+			output_expected: []int{1, 2}, err_expected: ErrIndex},
+		{name: "IndexOf-TC-21", input: []int{5, 6, 6}, ops: func(ll *List[int]) (int, error) { return IndexOf(ll, 6), nil },
+			output_expected: []int{5, 6, 6}, data_expected: 1},
+		{name: "IndexOf-Missing-TC-22", input: []int{5}, ops: func(ll *List[int]) (int, error) { return IndexOf(ll, 7), nil },
+			output_expected: []int{5}, data_expected: -1},
+		{name: "Sort-TC-23", input: []int{4, 1, 3, 1, 5, 2}, ops: func(ll *List[int]) (int, error) {
+			ll.Sort(func(x, y int) bool { return x < y })
+			return 0, nil
+		}, output_expected: []int{1, 1, 2, 3, 4, 5}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ll := listOf(tc.input...)
+			data, err := tc.ops(ll)
+			if !errors.Is(err, tc.err_expected) {
+				t.Fatalf("Expected error %v, got: %v", tc.err_expected, err)
+			}
+			if data != tc.data_expected {
+				t.Errorf("Expected %d, got: %d", tc.data_expected, data)
+			}
+			checkList(t, ll, tc.output_expected)
+		})
+	}
+}
+
+func TestAddBackIsConstant(t *testing.T) {
+	ll := InitList[int]()
+	for i := 0; i < 1000; i++ {
+		ll.AddBack(i)
+	}
+	// AddBack links the tail, it never walks the list from the head
+	head := ll.head
+	ll.head = nil
+	ll.AddBack(1000)
+	ll.head = head
+	if ll.tail.Data != 1000 || ll.Len() != 1001 {
+		t.Errorf("Expected tail 1000 and len 1001, got: %d %d", ll.tail.Data, ll.Len())
+	}
+	if v, _ := ll.KthFromEnd(2); v != 999 {
+		t.Errorf("Expected 999 before the tail, got: %d", v)
+	}
+}
+
+func TestFind(t *testing.T) {
+	ll := InitList[string]()
+	for _, v := range []string{"go", "rust", "zig"} {
+		ll.AddBack(v)
+	}
+	if v, i := ll.Find(func(s string) bool { return len(s) == 3 }); v != "zig" || i != 2 {
+		t.Errorf("Expected zig at 2, got: %q at %d", v, i)
+	}
+	if v, i := ll.Find(func(s string) bool { return s == "c" }); v != "" || i != -1 {
+		t.Errorf("Expected not found, got: %q at %d", v, i)
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	less := func(x, y int) bool { return x < y }
+	testCases := []struct {
+		name            string
+		a, b            []int
+		output_expected []int
+	}{
+		{name: "Merge-TC-1", a: []int{1, 3, 5}, b: []int{2, 4, 6, 8}, output_expected: []int{1, 2, 3, 4, 5, 6, 8}},
+		{name: "Merge-Empty-TC-2", a: []int{1, 2}, output_expected: []int{1, 2}},
+		{name: "Merge-Both-Empty-TC-3", output_expected: []int{}},
+		{name: "Merge-Equal-TC-4", a: []int{1, 1}, b: []int{1}, output_expected: []int{1, 1, 1}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := listOf(tc.a...), listOf(tc.b...)
+			merged := MergeSorted(a, b, less)
+			checkList(t, merged, tc.output_expected)
+			checkList(t, a, []int{})
+			checkList(t, b, []int{})
+		})
+	}
+}
+
+func TestMergeSortedSameList(t *testing.T) {
+	l := listOf(1, 2, 3)
+	done := make(chan *List[int])
+	go func() { done <- MergeSorted(l, l, func(x, y int) bool { return x < y }) }()
+	select {
+	case merged := <-done:
+		checkList(t, merged, []int{1, 2, 3})
+		checkList(t, l, []int{})
+	case <-time.After(time.Second):
+		t.Fatal("Expected the merge of a list with itself to return")
+	}
+}
+
+func TestMergeSortedIsStable(t *testing.T) {
+	type pair struct{ key, from int }
+	less := func(x, y pair) bool { return x.key < y.key }
+	a, b := InitList[pair](), InitList[pair]()
+	for _, p := range []pair{{1, 0}, {2, 0}, {2, 1}} {
+		a.AddBack(p)
+	}
+	b.AddBack(pair{2, 2})
+	merged := MergeSorted(a, b, less)
+	merged.AddBack(pair{0, 3})
+	merged.Sort(less)
+	expected := []pair{{0, 3}, {1, 0}, {2, 0}, {2, 1}, {2, 2}}
+	if got := iterator.Collect(merged.Iter()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got: %v", expected, got)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []int
+		link           int // index of the node the tail links to, -1 for none
+		start_expected int
+	}{
+		{name: "NoCycle-TC-1", input: []int{1, 2, 3}, link: -1},
+		{name: "NoCycle-Empty-TC-2", link: -1},
+		{name: "Cycle-Self-TC-3", input: []int{1}, link: 0, start_expected: 1},
+		{name: "Cycle-Head-TC-4", input: []int{1, 2, 3, 4}, link: 0, start_expected: 1},
+		{name: "Cycle-Middle-TC-5", input: []int{1, 2, 3, 4, 5}, link: 2, start_expected: 3},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ll := listOf(tc.input...)
+			if tc.link >= 0 {
+				ll.tail.Next = ll.nodeAt(tc.link)
+			}
+			start, ok := CycleStart(ll.Head())
+			if ok != (tc.link >= 0) || ok != HasCycle(ll.Head()) {
+				t.Fatalf("Expected cycle %t, got: %t", tc.link >= 0, ok)
+			}
+			if ok && start.Data != tc.start_expected {
+				t.Errorf("Expected cycle start %d, got: %d", tc.start_expected, start.Data)
+			}
+		})
+	}
+}
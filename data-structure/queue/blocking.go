@@ -0,0 +1,105 @@
+package queue
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+Blocking is a bounded FIFO queue safe for concurrent use, ie between producers and consumers:
+Put waits while the queue is full and Take while it is empty, both give up once their context
+is done. It is a buffered channel, along with Close which a channel can not do safely with many
+senders: once closed Put fails with ErrClosed, while Take still returns what is queued.
+*/
+type Blocking[T any] struct {
+	ch        chan T
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func NewBlocking[T any](capacity int) *Blocking[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Blocking[T]{ch: make(chan T, capacity), closed: make(chan struct{})}
+}
+
+/*
+Put queues the element, waiting for room till ctx is done. A Put racing Close might still
+queue its element, which is then taken as the others.
+*/
+func (q *Blocking[T]) Put(ctx context.Context, ele T) error {
+	select {
+	case <-q.closed:
+		return ErrClosed
+	default:
+	}
+	select {
+	case q.ch <- ele:
+		return nil
+	case <-q.closed:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TryPut queues the element only if there is room right away, else returns ErrFull
+func (q *Blocking[T]) TryPut(ele T) error {
+	select {
+	case <-q.closed:
+		return ErrClosed
+	default:
+	}
+	select {
+	case q.ch <- ele:
+		return nil
+	default:
+		return ErrFull
+	}
+}
+
+/*
+Take returns the front element, waiting for one till ctx is done. Once the queue is closed and
+drained it returns ErrClosed.
+*/
+func (q *Blocking[T]) Take(ctx context.Context) (ele T, err error) {
+	select {
+	case ele = <-q.ch:
+		return
+	case <-ctx.Done():
+		err = ctx.Err()
+		return
+	case <-q.closed:
+		return q.TryTake()
+	}
+}
+
+// TryTake returns the front element only if there is one right away, else ErrEmpty (or ErrClosed)
+func (q *Blocking[T]) TryTake() (ele T, err error) {
+	select {
+	case ele = <-q.ch:
+		return
+	default:
+	}
+	select {
+	case <-q.closed:
+		err = ErrClosed
+	default:
+		err = ErrEmpty
+	}
+	return
+}
+
+// Close stops the Puts, the queued elements can still be taken
+func (q *Blocking[T]) Close() {
+	q.closeOnce.Do(func() { close(q.closed) })
+}
+
+func (q *Blocking[T]) Len() int {
+	return len(q.ch)
+}
+
+func (q *Blocking[T]) Cap() int {
+	return cap(q.ch)
+}
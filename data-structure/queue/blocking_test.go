@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlocking(t *testing.T) {
+	q := NewBlocking[int](2)
+	ctx := context.Background()
+	q.Put(ctx, 1)
+	q.Put(ctx, 2)
+	if err := q.TryPut(3); !errors.Is(err, ErrFull) {
+		t.Errorf("Expected %v, got: %v", ErrFull, err)
+	}
+
+	// a full queue blocks Put till ctx is done
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := q.Put(timeout, 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %v, got: %v", context.DeadlineExceeded, err)
+	}
+
+	// till a Take makes room
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Take(ctx)
+	}()
+	if err := q.Put(ctx, 3); err != nil || q.Len() != 2 {
+		t.Errorf("Expected Put once there is room, got: %v (len %d)", err, q.Len())
+	}
+
+	q.Close()
+	if err := q.Put(ctx, 4); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected %v, got: %v", ErrClosed, err)
+	}
+	// the queued elements are still taken, in order
+	for _, expected := range []int{2, 3} {
+		if v, err := q.Take(ctx); v != expected || err != nil {
+			t.Errorf("Expected %v, got: %v %v", expected, v, err)
+		}
+	}
+	if _, err := q.Take(ctx); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected %v, got: %v", ErrClosed, err)
+	}
+}
+
+func TestBlockingTake(t *testing.T) {
+	q := NewBlocking[int](1)
+	if _, err := q.TryTake(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got: %v", ErrEmpty, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %v, got: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestBlockingConcurrent(t *testing.T) {
+	const producers, puts = 4, 250
+	q := NewBlocking[int](8)
+	ctx := context.Background()
+
+	wg := sync.WaitGroup{}
+	for p := 0; p < producers; p++ {
+		p := p
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < puts; i++ {
+				q.Put(ctx, p*puts+i)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		q.Close()
+	}()
+
+	mulock := sync.Mutex{}
+	seen := map[int]bool{}
+	consumers := sync.WaitGroup{}
+	for c := 0; c < 3; c++ {
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			for v, err := q.Take(ctx); err == nil; v, err = q.Take(ctx) {
+				mulock.Lock()
+				seen[v] = true
+				mulock.Unlock()
+			}
+		}()
+	}
+	consumers.Wait()
+	if len(seen) != producers*puts {
+		t.Errorf("Expected %d elements taken, got: %d", producers*puts, len(seen))
+	}
+}
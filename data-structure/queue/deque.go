@@ -0,0 +1,152 @@
+package queue
+
+import (
+	"errors"
+	"examples/iterator"
+)
+
+/*
+FIFO : First In First Out, and a deque (double ended queue) adds and removes at both the ends.
+
+Deque is a growable ring buffer: the elements wrap around the end of the slice, so PopFront
+does not re-slice (q = q[1:]) which keeps the popped elements and the backing array alive as
+the queue moves along. It doubles when full and halves once a quarter used, hence all the
+operations are O(1) amortised. It is not safe for concurrent use, see Blocking.
+*/
+
+var (
+	ErrEmpty  = errors.New("Queue is Empty!")
+	ErrFull   = errors.New("Queue is Full!")
+	ErrClosed = errors.New("Queue is Closed!")
+)
+
+const MIN_DEQUE_SIZE = 8
+
+type Deque[T any] struct {
+	buf  []T
+	head int // index of the front
+	len  int
+}
+
+// InitDeque with room for size elements before growing
+func InitDeque[T any](size int) *Deque[T] {
+	if size < MIN_DEQUE_SIZE {
+		size = MIN_DEQUE_SIZE
+	}
+	return &Deque[T]{buf: make([]T, size)}
+}
+
+func (q *Deque[T]) PushBack(ele T) {
+	q.grow()
+	q.buf[q.index(q.len)] = ele
+	q.len++
+}
+
+func (q *Deque[T]) PushFront(ele T) {
+	q.grow()
+	q.head = q.index(len(q.buf) - 1)
+	q.buf[q.head] = ele
+	q.len++
+}
+
+func (q *Deque[T]) PopFront() (ele T, err error) {
+	if q.len == 0 {
+		err = ErrEmpty
+		return
+	}
+	var zero T
+	ele, q.buf[q.head] = q.buf[q.head], zero // so the buffer does not keep it alive
+	q.head = q.index(1)
+	q.len--
+	q.shrink()
+	return
+}
+
+func (q *Deque[T]) PopBack() (ele T, err error) {
+	if q.len == 0 {
+		err = ErrEmpty
+		return
+	}
+	var zero T
+	tail := q.index(q.len - 1)
+	ele, q.buf[tail] = q.buf[tail], zero
+	q.len--
+	q.shrink()
+	return
+}
+
+func (q *Deque[T]) Front() (ele T, err error) {
+	if q.len == 0 {
+		err = ErrEmpty
+		return
+	}
+	return q.buf[q.head], nil
+}
+
+func (q *Deque[T]) Back() (ele T, err error) {
+	if q.len == 0 {
+		err = ErrEmpty
+		return
+	}
+	return q.buf[q.index(q.len-1)], nil
+}
+
+// At returns the i-th element from the front, ok false when out of range
+func (q *Deque[T]) At(i int) (ele T, ok bool) {
+	if i < 0 || i >= q.len {
+		return
+	}
+	return q.buf[q.index(i)], true
+}
+
+func (q *Deque[T]) Len() int {
+	return q.len
+}
+
+// Clear removes all the elements, releasing them
+func (q *Deque[T]) Clear() {
+	q.buf, q.head, q.len = make([]T, MIN_DEQUE_SIZE), 0, 0
+}
+
+// Iter from the front to the back, in the order the elements would be popped by PopFront
+func (q *Deque[T]) Iter() iterator.Iterator[T] {
+	i := 0
+	return iterator.Func[T](func() (ele T, ok bool) {
+		if ele, ok = q.At(i); ok {
+			i++
+		}
+		return
+	})
+}
+
+// index in the buffer of the i-th element from the front
+func (q *Deque[T]) index(i int) int {
+	return (q.head + i) % len(q.buf)
+}
+
+func (q *Deque[T]) grow() {
+	if q.buf == nil {
+		q.buf = make([]T, MIN_DEQUE_SIZE)
+	}
+	if q.len == len(q.buf) {
+		q.resize(2 * len(q.buf))
+	}
+}
+
+func (q *Deque[T]) shrink() {
+	if len(q.buf) > MIN_DEQUE_SIZE && q.len <= len(q.buf)/4 {
+		q.resize(len(q.buf) / 2)
+	}
+}
+
+// resize copies the elements to a new buffer, unwrapped from index 0
+func (q *Deque[T]) resize(size int) {
+	buf := make([]T, size)
+	if q.head+q.len <= len(q.buf) {
+		copy(buf, q.buf[q.head:q.head+q.len])
+	} else {
+		n := copy(buf, q.buf[q.head:])
+		copy(buf[n:], q.buf[:q.len-n])
+	}
+	q.buf, q.head = buf, 0
+}
@@ -0,0 +1,93 @@
+package queue
+
+import (
+	"errors"
+	"examples/iterator"
+	"reflect"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	testCases := []struct {
+		name            string
+		ops             func(q *Deque[int]) []int // returns the popped elements
+		output_expected []int                     // front to back, after the ops
+		popped_expected []int
+	}{
+		{name: "FIFO-TC-1", ops: func(q *Deque[int]) []int {
+			for i := 1; i <= 3; i++ {
+				q.PushBack(i)
+			}
+			v, _ := q.PopFront()
+			return []int{v}
+		}, output_expected: []int{2, 3}, popped_expected: []int{1}},
+		{name: "BothEnds-TC-2", ops: func(q *Deque[int]) []int {
+			q.PushBack(2)
+			q.PushFront(1)
+			q.PushBack(3)
+			q.PushFront(0)
+			b, _ := q.PopBack()
+			return []int{b}
+		}, output_expected: []int{0, 1, 2}, popped_expected: []int{3}},
+		{name: "Wrap-TC-3", ops: func(q *Deque[int]) (popped []int) {
+			// moves the head along the buffer, so the elements wrap around its end
+			for i := 0; i < 20; i++ {
+				q.PushBack(i)
+				if i%2 == 1 {
+					v, _ := q.PopFront()
+					popped = append(popped, v)
+				}
+			}
+			return
+		}, output_expected: []int{10, 11, 12, 13, 14, 15, 16, 17, 18, 19}, popped_expected: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{name: "Grow-TC-4", ops: func(q *Deque[int]) []int {
+			for i := 9; i >= 0; i-- {
+				q.PushFront(i)
+			}
+			return nil
+		}, output_expected: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := InitDeque[int](0)
+			popped := tc.ops(q)
+			if got := iterator.Collect(q.Iter()); !reflect.DeepEqual(got, tc.output_expected) || q.Len() != len(tc.output_expected) {
+				t.Errorf("Expected %v, got: %v (len %d)", tc.output_expected, got, q.Len())
+			}
+			if !reflect.DeepEqual(popped, tc.popped_expected) {
+				t.Errorf("Expected popped %v, got: %v", tc.popped_expected, popped)
+			}
+			front, _ := q.Front()
+			back, _ := q.Back()
+			if front != tc.output_expected[0] || back != tc.output_expected[len(tc.output_expected)-1] {
+				t.Errorf("Expected front %v back %v, got: %v %v", tc.output_expected[0], tc.output_expected[len(tc.output_expected)-1], front, back)
+			}
+		})
+	}
+}
+
+func TestDequeEmptyAndShrink(t *testing.T) {
+	var q Deque[string] // the zero value is usable
+	if _, err := q.PopFront(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got: %v", ErrEmpty, err)
+	}
+	if _, err := q.Back(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got: %v", ErrEmpty, err)
+	}
+	for i := 0; i < 1000; i++ {
+		q.PushBack("x")
+	}
+	for i := 0; i < 998; i++ {
+		q.PopFront()
+	}
+	if len(q.buf) > 16 || q.Len() != 2 {
+		t.Errorf("Expected the buffer shrunk, got: %d for %d elements", len(q.buf), q.Len())
+	}
+	if _, ok := q.At(2); ok {
+		t.Error("Expected At out of range")
+	}
+	q.Clear()
+	if q.Len() != 0 {
+		t.Errorf("Expected empty once cleared, got len: %v", q.Len())
+	}
+}
@@ -0,0 +1,75 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"examples/iterator"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+/*
+QueueExample uses the three queues: a deque as a sliding window, a blocking queue between
+producers and a consumer, and a priority queue of jobs whose priority changes while queued.
+*/
+func QueueExample(ctx context.Context, w io.Writer) error {
+	// deque: keep the last 3 readings, dropping from the front as they arrive at the back
+	window := InitDeque[int](0)
+	for _, reading := range []int{7, 3, 9, 4, 8} {
+		window.PushBack(reading)
+		if window.Len() > 3 {
+			window.PopFront()
+		}
+		fmt.Fprintln(w, "Deque window: ", iterator.Collect(window.Iter()))
+	}
+
+	// blocking: 2 producers fill a queue of 2, the consumer is slower hence they wait on Put
+	q := NewBlocking[string](2)
+	wg := sync.WaitGroup{}
+	for p := 1; p <= 2; p++ {
+		p := p
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; i <= 3; i++ {
+				if err := q.Put(ctx, fmt.Sprintf("P%d-%d", p, i)); err != nil {
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		q.Close()
+	}()
+	taken := []string{}
+	for {
+		v, err := q.Take(ctx)
+		if errors.Is(err, ErrClosed) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		taken = append(taken, v)
+		time.Sleep(5 * time.Millisecond)
+	}
+	fmt.Fprintln(w, "Blocking queue taken: ", taken)
+
+	// priority: the highest priority first, the backup is escalated while queued
+	type job struct {
+		name     string
+		priority int
+	}
+	pq := NewPriorityQueue(func(a, b job) bool { return a.priority > b.priority })
+	pq.Push(job{"deploy", 3})
+	backup := pq.Push(job{"backup", 1})
+	pq.Push(job{"report", 2})
+	pq.Update(backup, job{"backup", 5})
+	for j, err := pq.Pop(); err == nil; j, err = pq.Pop() {
+		fmt.Fprintf(w, "Priority queue: %s (%d)\n", j.name, j.priority)
+	}
+	return nil
+}
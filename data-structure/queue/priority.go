@@ -0,0 +1,107 @@
+package queue
+
+import (
+	"container/heap"
+	"errors"
+)
+
+/*
+PriorityQueue pops the element with the highest priority first, as decided by the comparator:
+less(a, b) true pops a before b, ie a min-heap for less = a < b. It is a binary heap over
+container/heap, Push and Pop are O(log n).
+
+Push returns the Item of the element, a handle to Update its value (ie its priority) or to
+Remove it later, at O(log n) as well. It is not safe for concurrent use.
+*/
+
+var ErrNotQueued = errors.New("Item is not in the queue!")
+
+type Item[T any] struct {
+	Value T
+	index int // in the heap, -1 once popped or removed
+}
+
+type PriorityQueue[T any] struct {
+	h *itemHeap[T]
+}
+
+// itemHeap implements heap.Interface
+type itemHeap[T any] struct {
+	items []*Item[T]
+	less  func(a, b T) bool
+}
+
+func (h itemHeap[T]) Len() int           { return len(h.items) }
+func (h itemHeap[T]) Less(i, j int) bool { return h.less(h.items[i].Value, h.items[j].Value) }
+func (h itemHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+func (h *itemHeap[T]) Push(x interface{}) {
+	item := x.(*Item[T])
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+func (h *itemHeap[T]) Pop() interface{} {
+	last := len(h.items) - 1
+	item := h.items[last]
+	h.items[last] = nil // so the slice does not keep it alive
+	h.items = h.items[:last]
+	item.index = -1
+	return item
+}
+
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{h: &itemHeap[T]{less: less}}
+}
+
+func (pq *PriorityQueue[T]) Push(ele T) *Item[T] {
+	item := &Item[T]{Value: ele}
+	heap.Push(pq.h, item)
+	return item
+}
+
+func (pq *PriorityQueue[T]) Pop() (ele T, err error) {
+	if pq.h.Len() == 0 {
+		err = ErrEmpty
+		return
+	}
+	return heap.Pop(pq.h).(*Item[T]).Value, nil
+}
+
+// Peek returns the element Pop would, without removing it
+func (pq *PriorityQueue[T]) Peek() (ele T, err error) {
+	if pq.h.Len() == 0 {
+		err = ErrEmpty
+		return
+	}
+	return pq.h.items[0].Value, nil
+}
+
+// Update sets the value of the item and moves it to its place, ie once its priority changed
+func (pq *PriorityQueue[T]) Update(item *Item[T], ele T) error {
+	if !pq.contains(item) {
+		return ErrNotQueued
+	}
+	item.Value = ele
+	heap.Fix(pq.h, item.index)
+	return nil
+}
+
+func (pq *PriorityQueue[T]) Remove(item *Item[T]) error {
+	if !pq.contains(item) {
+		return ErrNotQueued
+	}
+	heap.Remove(pq.h, item.index)
+	return nil
+}
+
+func (pq *PriorityQueue[T]) Len() int {
+	return pq.h.Len()
+}
+
+// contains checks the item is in this queue, rather than popped or of another queue
+func (pq *PriorityQueue[T]) contains(item *Item[T]) bool {
+	return item != nil && item.index >= 0 && item.index < len(pq.h.items) && pq.h.items[item.index] == item
+}
@@ -0,0 +1,82 @@
+package queue
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type task struct {
+	name     string
+	priority int
+}
+
+func TestPriorityQueue(t *testing.T) {
+	testCases := []struct {
+		name            string
+		less            func(a, b int) bool
+		push            []int
+		output_expected []int
+	}{
+		{name: "MinHeap-TC-1", less: func(a, b int) bool { return a < b }, push: []int{5, 1, 4, 2, 3, 1}, output_expected: []int{1, 1, 2, 3, 4, 5}},
+		{name: "MaxHeap-TC-2", less: func(a, b int) bool { return a > b }, push: []int{5, 1, 4, 2, 3}, output_expected: []int{5, 4, 3, 2, 1}},
+		{name: "Empty-TC-3", less: func(a, b int) bool { return a < b }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pq := NewPriorityQueue(tc.less)
+			for _, v := range tc.push {
+				pq.Push(v)
+			}
+			got := []int{}
+			for v, err := pq.Pop(); err == nil; v, err = pq.Pop() {
+				got = append(got, v)
+			}
+			if len(got) != len(tc.output_expected) || len(got) > 0 && !reflect.DeepEqual(got, tc.output_expected) {
+				t.Errorf("Expected %v, got: %v", tc.output_expected, got)
+			}
+			if _, err := pq.Peek(); !errors.Is(err, ErrEmpty) {
+				t.Errorf("Expected %v, got: %v", ErrEmpty, err)
+			}
+		})
+	}
+}
+
+func TestPriorityQueueUpdate(t *testing.T) {
+	pq := NewPriorityQueue(func(a, b task) bool { return a.priority > b.priority })
+	pq.Push(task{"deploy", 3})
+	backup := pq.Push(task{"backup", 1})
+	report := pq.Push(task{"report", 2})
+	pq.Push(task{"lint", 0})
+
+	if err := pq.Update(backup, task{"backup", 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pq.Remove(report); err != nil {
+		t.Fatal(err)
+	}
+	if top, _ := pq.Peek(); top.name != "backup" {
+		t.Errorf("Expected backup first once updated, got: %v", top)
+	}
+
+	got := []string{}
+	for v, err := pq.Pop(); err == nil; v, err = pq.Pop() {
+		got = append(got, v.name)
+	}
+	if expected := []string{"backup", "deploy", "lint"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got: %v", expected, got)
+	}
+	// the handles of the popped or removed items are stale
+	if err := pq.Update(backup, task{"backup", 9}); !errors.Is(err, ErrNotQueued) {
+		t.Errorf("Expected %v, got: %v", ErrNotQueued, err)
+	}
+	if err := pq.Remove(report); !errors.Is(err, ErrNotQueued) {
+		t.Errorf("Expected %v, got: %v", ErrNotQueued, err)
+	}
+	other := NewPriorityQueue(func(a, b task) bool { return a.priority > b.priority })
+	item := other.Push(task{"other", 1})
+	pq.Push(task{"mine", 1})
+	if err := pq.Update(item, task{"other", 2}); !errors.Is(err, ErrNotQueued) {
+		t.Errorf("Expected %v for an item of another queue, got: %v", ErrNotQueued, err)
+	}
+}
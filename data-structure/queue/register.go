@@ -0,0 +1,20 @@
+package queue
+
+import (
+	"context"
+	"examples/registry"
+	"io"
+)
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "queue",
+		Title:       "Queue, Deque and Priority Queue",
+		Category:    registry.CATEGORY_DATA_STRUCTURE,
+		Description: "Ring buffer deque, bounded blocking queue between producers and a consumer, and a priority queue with priority updates",
+		Source:      "data-structure/queue/example.go",
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return QueueExample(ctx, w)
+		},
+	})
+}
@@ -2,6 +2,7 @@ package sort
 
 import (
 	"fmt"
+	"io"
 	"sort"
 )
 
@@ -34,7 +35,7 @@ func (d Department) Swap(i, j int) {
 	d[j] = temp
 }
 
-func ExampleSort() {
+func ExampleSort(w io.Writer) {
 	dp := Department{
 		{name: "Harry", age: 65, salary: 1000},
 		{name: "Shaun", age: 25, salary: 5000},
@@ -42,7 +43,7 @@ func ExampleSort() {
 		{name: "Glassman", age: 65, salary: 9000},
 		{name: "Lea", age: 25, salary: 5000},
 	}
-	fmt.Println("Pre-Sort", dp)
+	fmt.Fprintln(w, "Pre-Sort", dp)
 	sort.Sort(dp)
-	fmt.Println("Post-Sort", dp)
+	fmt.Fprintln(w, "Post-Sort", dp)
 }
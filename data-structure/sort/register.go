@@ -0,0 +1,14 @@
+package sort
+
+import "examples/registry"
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "sort",
+		Title:       "Sort",
+		Category:    registry.CATEGORY_DATA_STRUCTURE,
+		Description: "Sort employees by implementing sort.Interface (Len, Less, Swap)",
+		Source:      "data-structure/sort/package.sort.go",
+		Run:         registry.Simple(ExampleSort),
+	})
+}
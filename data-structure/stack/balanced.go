@@ -0,0 +1,183 @@
+package stack
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*
+Balance checker: every opening bracket (or HTML tag) is pushed on a stack, every closing one
+must match the top of the stack, which is popped. The input is balanced when nothing is left.
+
+With html, the tags are checked as well: <p> ... </p>. Void (<br>) and self-closing (<br/>)
+tags, comments (<!-- -->), doctype and processing instructions (<!...>, <?...?>) open nothing.
+The tag names are case insensitive, the brackets in the text of the page are checked along
+with the tags, the ones inside a tag (ie its attributes) are not. A '<' in the text has to be
+escaped (&lt;), as in valid HTML.
+*/
+
+var (
+	ErrUnexpectedClose = errors.New("Unexpected closing bracket!")
+	ErrMismatch        = errors.New("Mismatched closing bracket!")
+	ErrUnclosed        = errors.New("Unclosed bracket!")
+	ErrMalformedTag    = errors.New("Malformed tag!")
+)
+
+var brackets = map[string]string{")": "(", "]": "[", "}": "{"}
+
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+/*
+BalanceError is the position (line and column from 1) of the first error, it matches its
+sentinel error (ie ErrMismatch) via errors.Is. Expected is the closing one due, if any.
+*/
+type BalanceError struct {
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Found    string `json:"found,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Err      error  `json:"-"`
+}
+
+func (e *BalanceError) Error() string {
+	msg := fmt.Sprintf("%v %q at line %d column %d", e.Err, e.Found, e.Line, e.Column)
+	if e.Expected != "" {
+		msg += fmt.Sprintf(", expected %q", e.Expected)
+	}
+	return msg
+}
+
+func (e *BalanceError) Unwrap() error {
+	return e.Err
+}
+
+// opened bracket or tag, with its position
+type opening struct {
+	text         string // "(" or "<p>"
+	closing      string // ")" or "</p>"
+	line, column int
+}
+
+/*
+CheckBalanced returns nil when the brackets (and the tags, with html) of the input are balanced,
+else a BalanceError of the first error found.
+*/
+func CheckBalanced(input string, html bool) error {
+	open := InitStack[opening](0)
+	runes := []rune(input)
+	line, column := 1, 0
+	advance := func(r rune) {
+		if column++; r == '\n' {
+			line, column = line+1, 0
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		advance(r)
+		switch s := string(r); {
+		case s == "(" || s == "[" || s == "{":
+			open.Push(opening{text: s, closing: closingOf(s), line: line, column: column})
+
+		case brackets[s] != "":
+			if err := closeOpening(open, s, line, column); err != nil {
+				return err
+			}
+
+		case html && r == '<':
+			tagLine, tagColumn := line, column
+			end := i + 1
+			for end < len(runes) && runes[end] != '>' {
+				end++
+			}
+			if end == len(runes) {
+				return &BalanceError{Line: tagLine, Column: tagColumn, Found: "<", Err: ErrMalformedTag}
+			}
+			tag := string(runes[i : end+1])
+			for _, tr := range runes[i+1 : end+1] {
+				advance(tr)
+			}
+			i = end
+
+			name, closing, ok := parseTag(tag)
+			switch {
+			case !ok:
+				return &BalanceError{Line: tagLine, Column: tagColumn, Found: tag, Err: ErrMalformedTag}
+			case name == "":
+				// comment, doctype, self-closing or void tag
+			case closing:
+				if err := closeOpening(open, "</"+name+">", tagLine, tagColumn); err != nil {
+					return err
+				}
+			default:
+				open.Push(opening{text: "<" + name + ">", closing: "</" + name + ">", line: tagLine, column: tagColumn})
+			}
+		}
+	}
+
+	if o, err := open.Pop(); err == nil {
+		return &BalanceError{Line: o.line, Column: o.column, Found: o.text, Expected: o.closing, Err: ErrUnclosed}
+	}
+	return nil
+}
+
+func closingOf(opening string) string {
+	for closing, o := range brackets {
+		if o == opening {
+			return closing
+		}
+	}
+	return ""
+}
+
+// closeOpening pops the top of the stack, which must be closed by closing
+func closeOpening(open *Stack[opening], closing string, line, column int) error {
+	top, err := open.Pop()
+	if err != nil {
+		return &BalanceError{Line: line, Column: column, Found: closing, Err: ErrUnexpectedClose}
+	}
+	if top.closing != closing {
+		return &BalanceError{Line: line, Column: column, Found: closing, Expected: top.closing, Err: ErrMismatch}
+	}
+	return nil
+}
+
+/*
+parseTag returns the lower case name of the tag and whether it closes, the name is empty for
+the tags which open nothing. A tag without a name (ie "< p>" or "<>") is not ok.
+*/
+func parseTag(tag string) (name string, closing bool, ok bool) {
+	body := tag[1 : len(tag)-1]
+	if strings.HasPrefix(body, "!") || strings.HasPrefix(body, "?") {
+		return "", false, true
+	}
+	if strings.HasPrefix(body, "/") {
+		closing, body = true, body[1:]
+	}
+	selfClosing := strings.HasSuffix(body, "/")
+	if fields := strings.Fields(strings.TrimSuffix(body, "/")); len(fields) > 0 && !strings.HasPrefix(body, " ") {
+		name = strings.ToLower(fields[0])
+	}
+	if name == "" || closing && selfClosing {
+		return "", false, false
+	}
+	if selfClosing || voidTags[name] {
+		// a closing void tag ie </br> is ignored, as the browsers do
+		return "", false, true
+	}
+	return name, closing, true
+}
+
+func BalancedExample(w io.Writer, input string, html bool) {
+	fmt.Fprintf(w, "Input: %q\n", input)
+	if err := CheckBalanced(input, html); err != nil {
+		fmt.Fprintln(w, "Not balanced: ", err)
+		return
+	}
+	fmt.Fprintln(w, "Balanced")
+}
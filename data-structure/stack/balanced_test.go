@@ -0,0 +1,45 @@
+package stack
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckBalanced(t *testing.T) {
+	testCases := []struct {
+		name            string
+		input           string
+		html            bool
+		err_expected    error
+		line_expected   int
+		column_expected int
+	}{
+		{name: "Empty-TC-1", input: ""},
+		{name: "Brackets-TC-2", input: "func() { a[i] = (b + c) }"},
+		{name: "Mismatch-TC-3", input: "{ a[i) }", err_expected: ErrMismatch, line_expected: 1, column_expected: 6},
+		{name: "UnexpectedClose-TC-4", input: "a)", err_expected: ErrUnexpectedClose, line_expected: 1, column_expected: 2},
+		{name: "Unclosed-TC-5", input: "(\n[\n]", err_expected: ErrUnclosed, line_expected: 1, column_expected: 1},
+		{name: "TagsIgnored-TC-6", input: "<p>(</b>)"},
+		{name: "HTML-TC-7", input: "<!DOCTYPE html>\n<HTML><body class=\"a(\">\n<p>Hi<br> (<b>there</b>)<img src=x /></p>\n<!-- note --></body></html>", html: true},
+		{name: "HTMLMismatch-TC-8", input: "<div>\n  <p>text</div>", html: true, err_expected: ErrMismatch, line_expected: 2, column_expected: 10},
+		{name: "HTMLBracketInTag-TC-9", input: "<p>(</p>)", html: true, err_expected: ErrMismatch, line_expected: 1, column_expected: 5},
+		{name: "HTMLUnclosed-TC-10", input: "<ul><li>a</li>", html: true, err_expected: ErrUnclosed, line_expected: 1, column_expected: 1},
+		{name: "HTMLMalformed-TC-11", input: "<p>a < b</p>", html: true, err_expected: ErrMalformedTag, line_expected: 1, column_expected: 6},
+		{name: "HTMLUnterminated-TC-12", input: "<p", html: true, err_expected: ErrMalformedTag, line_expected: 1, column_expected: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckBalanced(tc.input, tc.html)
+			if tc.err_expected == nil {
+				if err != nil {
+					t.Errorf("Expected balanced, got: %v", err)
+				}
+				return
+			}
+			var balanceErr *BalanceError
+			if !errors.Is(err, tc.err_expected) || !errors.As(err, &balanceErr) || balanceErr.Line != tc.line_expected || balanceErr.Column != tc.column_expected {
+				t.Errorf("Expected %v at %d:%d, got: %v", tc.err_expected, tc.line_expected, tc.column_expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package stack
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+/*
+LockFree is a Treiber stack, safe for concurrent use without a lock: the top is an atomic pointer
+and Push/Pop retry a compare-and-swap of it till no other goroutine changed it meanwhile.
+
+	Push: new.next = top; CAS(top, new.next, new)
+	Pop:  top := top; CAS(top, top, top.next)
+
+The ABA problem (the top popped and pushed again between the load and the CAS) can not happen,
+as a node is never reused: the garbage collector frees it only once no goroutine holds it.
+Len is a separate counter, hence approximate while Push/Pop are running.
+*/
+type LockFree[T any] struct {
+	top atomic.Pointer[node[T]]
+	len int64
+}
+
+type node[T any] struct {
+	value T
+	next  *node[T]
+}
+
+func NewLockFree[T any]() *LockFree[T] {
+	return &LockFree[T]{}
+}
+
+func (s *LockFree[T]) Push(ele T) {
+	n := &node[T]{value: ele}
+	for {
+		n.next = s.top.Load()
+		if s.top.CompareAndSwap(n.next, n) {
+			atomic.AddInt64(&s.len, 1)
+			return
+		}
+	}
+}
+
+func (s *LockFree[T]) Pop() (ele T, err error) {
+	for {
+		top := s.top.Load()
+		if top == nil {
+			err = ErrEmpty
+			return
+		}
+		if s.top.CompareAndSwap(top, top.next) {
+			atomic.AddInt64(&s.len, -1)
+			return top.value, nil
+		}
+	}
+}
+
+func (s *LockFree[T]) Peek() (ele T, err error) {
+	top := s.top.Load()
+	if top == nil {
+		err = ErrEmpty
+		return
+	}
+	return top.value, nil
+}
+
+func (s *LockFree[T]) Len() int {
+	return int(atomic.LoadInt64(&s.len))
+}
+
+/*
+Synced is a Stack guarded by a mutex, the simple alternative to LockFree, see the benchmarks.
+*/
+type Synced[T any] struct {
+	mulock sync.Mutex
+	stack  Stack[T]
+}
+
+func NewSynced[T any]() *Synced[T] {
+	return &Synced[T]{}
+}
+
+func (s *Synced[T]) Push(ele T) {
+	s.mulock.Lock()
+	defer s.mulock.Unlock()
+	s.stack.Push(ele) // unbounded, never fails
+}
+
+func (s *Synced[T]) Pop() (ele T, err error) {
+	s.mulock.Lock()
+	defer s.mulock.Unlock()
+	return s.stack.Pop()
+}
+
+func (s *Synced[T]) Peek() (ele T, err error) {
+	s.mulock.Lock()
+	defer s.mulock.Unlock()
+	return s.stack.Peek()
+}
+
+func (s *Synced[T]) Len() int {
+	s.mulock.Lock()
+	defer s.mulock.Unlock()
+	return s.stack.Len()
+}
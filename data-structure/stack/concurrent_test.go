@@ -0,0 +1,103 @@
+package stack
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// concurrent stack, to run the same tests and benchmarks on both the implementations
+type concurrent interface {
+	Push(int)
+	Pop() (int, error)
+	Len() int
+}
+
+var implementations = []struct {
+	name string
+	new  func() concurrent
+}{
+	{name: "LockFree", new: func() concurrent { return NewLockFree[int]() }},
+	{name: "Synced", new: func() concurrent { return NewSynced[int]() }},
+}
+
+func TestConcurrent(t *testing.T) {
+	const goroutines, pushes = 8, 1000
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			s := impl.new()
+			if _, err := s.Pop(); !errors.Is(err, ErrEmpty) {
+				t.Errorf("Expected %v, got: %v", ErrEmpty, err)
+			}
+
+			wg := sync.WaitGroup{}
+			popped := make([][]int, goroutines)
+			for g := 0; g < goroutines; g++ {
+				g := g
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < pushes; i++ {
+						s.Push(g*pushes + i)
+						// pop every other push, so pushes and pops race
+						if i%2 == 1 {
+							if v, err := s.Pop(); err == nil {
+								popped[g] = append(popped[g], v)
+							}
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			// every value pushed is either popped once or still on the stack
+			seen := map[int]bool{}
+			for _, values := range popped {
+				for _, v := range values {
+					seen[v] = true
+				}
+			}
+			left := s.Len()
+			for v, err := s.Pop(); err == nil; v, err = s.Pop() {
+				if seen[v] {
+					t.Fatalf("Value %d popped twice", v)
+				}
+				seen[v] = true
+			}
+			if len(seen) != goroutines*pushes || left != goroutines*pushes/2 {
+				t.Errorf("Expected %d values, %d left on the stack, got: %d, %d", goroutines*pushes, goroutines*pushes/2, len(seen), left)
+			}
+		})
+	}
+}
+
+func TestLockFreePeek(t *testing.T) {
+	s := NewLockFree[string]()
+	s.Push("a")
+	s.Push("b")
+	if top, err := s.Peek(); top != "b" || err != nil || s.Len() != 2 {
+		t.Errorf("Expected b without removing it, got: %v %v (len %d)", top, err, s.Len())
+	}
+}
+
+/*
+BenchmarkConcurrent pushes and pops on all the Ps, ie:
+
+	go test ./data-structure/stack -bench Concurrent -cpu 1,4,8
+*/
+func BenchmarkConcurrent(b *testing.B) {
+	for _, impl := range implementations {
+		b.Run(impl.name, func(b *testing.B) {
+			s := impl.new()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					if i%2 == 0 {
+						s.Push(i)
+					} else {
+						s.Pop()
+					}
+				}
+			})
+		})
+	}
+}
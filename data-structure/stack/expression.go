@@ -0,0 +1,430 @@
+package stack
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/*
+Expression evaluator, the classic use of stacks:
+
+ 1. Parse converts the infix expression to RPN (Reverse Polish Notation) via the shunting-yard
+    algorithm, the operators wait on a stack till an operator of lower precedence (or a closing
+    bracket) pops them to the output: 2 + 3 * 4 -> 2 3 4 * +
+ 2. Evaluate runs the RPN on a stack of values: an operand is pushed, an operator pops its
+    operands and pushes its result. The value left on the stack is the result.
+
+Supported: integers and floats, variables, + - * / % ^ (power, right associative), unary minus
+and the functions max, min (any number of arguments), abs and sqrt.
+Errors are ExprError, with the column (from 1) of the offending token.
+*/
+
+var (
+	ErrSyntax          = errors.New("Syntax error!")
+	ErrUnknownVariable = errors.New("Unknown variable!")
+	ErrUnknownFunction = errors.New("Unknown function!")
+	ErrArgCount        = errors.New("Wrong number of arguments!")
+	ErrDivisionByZero  = errors.New("Division by zero!")
+	ErrNotFinite       = errors.New("Result is not a finite number!")
+)
+
+/*
+ExprError is the position of an error in the expression, it matches its sentinel error
+(ie ErrSyntax) via errors.Is.
+*/
+type ExprError struct {
+	Column int
+	Err    error
+	Detail string
+}
+
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("%v %s at column %d", e.Err, e.Detail, e.Column)
+}
+
+func (e *ExprError) Unwrap() error {
+	return e.Err
+}
+
+type tokenKind int
+
+const (
+	TOKEN_NUMBER tokenKind = iota
+	TOKEN_VARIABLE
+	TOKEN_OPERATOR
+	TOKEN_FUNCTION
+	TOKEN_LEFT_PAREN
+	TOKEN_RIGHT_PAREN
+	TOKEN_COMMA
+)
+
+// NEGATE is the unary minus in the RPN, to tell it from the binary one
+const NEGATE = "neg"
+
+type token struct {
+	kind   tokenKind
+	text   string
+	column int
+	value  float64 // of a number
+	argc   int     // of a function, once its call is parsed
+}
+
+type operator struct {
+	precedence int
+	rightAssoc bool
+}
+
+var operators = map[string]operator{
+	"+":    {precedence: 1},
+	"-":    {precedence: 1},
+	"*":    {precedence: 2},
+	"/":    {precedence: 2},
+	"%":    {precedence: 2},
+	NEGATE: {precedence: 3, rightAssoc: true}, // below ^, so -2^2 is -(2^2)
+	"^":    {precedence: 4, rightAssoc: true},
+}
+
+type function struct {
+	minArgs, maxArgs int // maxArgs < 0 is any number
+	call             func(args []float64) float64
+}
+
+var functions = map[string]function{
+	"max":  {minArgs: 1, maxArgs: -1, call: func(args []float64) float64 { return fold(args, math.Max) }},
+	"min":  {minArgs: 1, maxArgs: -1, call: func(args []float64) float64 { return fold(args, math.Min) }},
+	"abs":  {minArgs: 1, maxArgs: 1, call: func(args []float64) float64 { return math.Abs(args[0]) }},
+	"sqrt": {minArgs: 1, maxArgs: 1, call: func(args []float64) float64 { return math.Sqrt(args[0]) }},
+}
+
+func fold(args []float64, fn func(a, b float64) float64) float64 {
+	res := args[0]
+	for _, a := range args[1:] {
+		res = fn(res, a)
+	}
+	return res
+}
+
+// Expression parsed to RPN, to be evaluated with different variables
+type Expression struct {
+	rpn []token
+}
+
+/*
+tokenize splits the expression in numbers, names, operators, brackets and commas.
+A name followed by "(" is a function.
+*/
+func tokenize(expr string) (tokens []token, err error) {
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r, column := runes[i], i+1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			value, perr := strconv.ParseFloat(text, 64)
+			if perr != nil {
+				return nil, &ExprError{Column: column, Err: ErrSyntax, Detail: fmt.Sprintf("invalid number %q", text)}
+			}
+			tokens = append(tokens, token{kind: TOKEN_NUMBER, text: text, column: column, value: value})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			kind := TOKEN_VARIABLE
+			// the call might be spaced, ie max (1, 2)
+			next := i
+			for next < len(runes) && unicode.IsSpace(runes[next]) {
+				next++
+			}
+			if next < len(runes) && runes[next] == '(' {
+				kind = TOKEN_FUNCTION
+			}
+			tokens = append(tokens, token{kind: kind, text: string(runes[start:i]), column: column})
+
+		default:
+			kind := TOKEN_OPERATOR
+			switch r {
+			case '(':
+				kind = TOKEN_LEFT_PAREN
+			case ')':
+				kind = TOKEN_RIGHT_PAREN
+			case ',':
+				kind = TOKEN_COMMA
+			default:
+				if _, ok := operators[string(r)]; !ok {
+					return nil, &ExprError{Column: column, Err: ErrSyntax, Detail: fmt.Sprintf("unexpected %q", r)}
+				}
+			}
+			tokens = append(tokens, token{kind: kind, text: string(r), column: column})
+			i++
+		}
+	}
+	return
+}
+
+// call of a function or a plain bracket, open on the stack of Parse
+type frame struct {
+	fn   *token // nil for a plain bracket
+	argc int
+}
+
+/*
+Parse the infix expression to RPN via shunting-yard. expectOperand tracks whether an operand
+(or a prefix: unary minus, bracket, function) is due, which is how a unary minus is told from
+a binary one and how the missing operands are reported.
+*/
+func Parse(expr string) (e *Expression, err error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return
+	}
+
+	output := []token{}
+	ops := InitStack[token](0)
+	frames := InitStack[*frame](0)
+	expectOperand := true
+	syntaxErr := func(t token, detail string) error {
+		return &ExprError{Column: t.column, Err: ErrSyntax, Detail: detail}
+	}
+	// popOperators moves the operators to the output, till a bracket or one binding less than o
+	popOperators := func(o *operator) {
+		for top, err := ops.Peek(); err == nil && top.kind == TOKEN_OPERATOR; top, err = ops.Peek() {
+			prev := operators[top.text]
+			if o != nil && (prev.precedence < o.precedence || prev.precedence == o.precedence && o.rightAssoc) {
+				return
+			}
+			ops.Pop()
+			output = append(output, top)
+		}
+	}
+
+	for i, t := range tokens {
+		switch t.kind {
+		case TOKEN_NUMBER, TOKEN_VARIABLE:
+			if !expectOperand {
+				return nil, syntaxErr(t, fmt.Sprintf("unexpected operand %q", t.text))
+			}
+			output = append(output, t)
+			expectOperand = false
+
+		case TOKEN_FUNCTION:
+			if !expectOperand {
+				return nil, syntaxErr(t, fmt.Sprintf("unexpected function %q", t.text))
+			}
+			if _, ok := functions[t.text]; !ok {
+				return nil, &ExprError{Column: t.column, Err: ErrUnknownFunction, Detail: t.text}
+			}
+			ops.Push(t)
+
+		case TOKEN_LEFT_PAREN:
+			if !expectOperand {
+				return nil, syntaxErr(t, "unexpected '('")
+			}
+			f := &frame{}
+			if top, err := ops.Peek(); err == nil && top.kind == TOKEN_FUNCTION && i > 0 && tokens[i-1].kind == TOKEN_FUNCTION {
+				f.fn = &top
+				ops.Pop()
+			}
+			frames.Push(f)
+			ops.Push(t)
+
+		case TOKEN_COMMA:
+			f, ferr := frames.Peek()
+			if ferr != nil || f.fn == nil {
+				return nil, syntaxErr(t, "',' outside of a function call")
+			}
+			if expectOperand {
+				return nil, syntaxErr(t, "missing argument")
+			}
+			popOperators(nil)
+			f.argc++
+			expectOperand = true
+
+		case TOKEN_RIGHT_PAREN:
+			f, ferr := frames.Pop()
+			if ferr != nil {
+				return nil, syntaxErr(t, "unexpected ')'")
+			}
+			empty := i > 0 && tokens[i-1].kind == TOKEN_LEFT_PAREN
+			if expectOperand && !(empty && f.fn != nil) {
+				return nil, syntaxErr(t, "missing operand")
+			}
+			popOperators(nil)
+			ops.Pop() // the '('
+			if f.fn != nil {
+				fn := *f.fn
+				if fn.argc = f.argc; !empty {
+					fn.argc++
+				}
+				def := functions[fn.text]
+				if fn.argc < def.minArgs || def.maxArgs >= 0 && fn.argc > def.maxArgs {
+					return nil, &ExprError{Column: fn.column, Err: ErrArgCount, Detail: fmt.Sprintf("%s got %d", fn.text, fn.argc)}
+				}
+				output = append(output, fn)
+			}
+			expectOperand = false
+
+		case TOKEN_OPERATOR:
+			if expectOperand {
+				switch t.text {
+				case "-":
+					t.text = NEGATE
+					// a prefix operator pops nothing, its operand is not parsed yet
+					ops.Push(t)
+					continue
+				case "+":
+					continue
+				}
+				return nil, syntaxErr(t, fmt.Sprintf("missing operand before %q", t.text))
+			}
+			o := operators[t.text]
+			popOperators(&o)
+			ops.Push(t)
+			expectOperand = true
+		}
+	}
+
+	if expectOperand {
+		return nil, &ExprError{Column: len([]rune(expr)) + 1, Err: ErrSyntax, Detail: "unexpected end of expression"}
+	}
+	for t, err := ops.Pop(); err == nil; t, err = ops.Pop() {
+		if t.kind == TOKEN_LEFT_PAREN {
+			return nil, syntaxErr(t, "unclosed '('")
+		}
+		output = append(output, t)
+	}
+	return &Expression{rpn: output}, nil
+}
+
+// RPN of the expression, the functions with their number of arguments ie max/3
+func (e *Expression) RPN() []string {
+	rpn := make([]string, len(e.rpn))
+	for i, t := range e.rpn {
+		rpn[i] = t.text
+		if t.kind == TOKEN_FUNCTION {
+			rpn[i] = fmt.Sprintf("%s/%d", t.text, t.argc)
+		}
+	}
+	return rpn
+}
+
+// String of the RPN, ie 2 3 4 * +
+func (e *Expression) String() string {
+	return strings.Join(e.RPN(), " ")
+}
+
+/*
+Evaluate the RPN on a stack of values, with the variables given. The stack can not underflow,
+Parse has checked that every operator has its operands.
+*/
+func (e *Expression) Evaluate(vars map[string]float64) (value float64, err error) {
+	values := InitStack[float64](0)
+	for _, t := range e.rpn {
+		switch t.kind {
+		case TOKEN_NUMBER:
+			values.Push(t.value)
+
+		case TOKEN_VARIABLE:
+			v, ok := vars[t.text]
+			if !ok {
+				return 0, &ExprError{Column: t.column, Err: ErrUnknownVariable, Detail: t.text}
+			}
+			values.Push(v)
+
+		case TOKEN_FUNCTION:
+			args := make([]float64, t.argc)
+			for i := t.argc - 1; i >= 0; i-- {
+				args[i], _ = values.Pop()
+			}
+			if value = functions[t.text].call(args); math.IsNaN(value) || math.IsInf(value, 0) {
+				return 0, &ExprError{Column: t.column, Err: ErrNotFinite, Detail: t.text}
+			}
+			values.Push(value)
+
+		case TOKEN_OPERATOR:
+			b, _ := values.Pop()
+			if t.text == NEGATE {
+				values.Push(-b)
+				continue
+			}
+			a, _ := values.Pop()
+			switch t.text {
+			case "+":
+				value = a + b
+			case "-":
+				value = a - b
+			case "*":
+				value = a * b
+			case "/", "%":
+				if b == 0 {
+					return 0, &ExprError{Column: t.column, Err: ErrDivisionByZero}
+				}
+				value = a / b
+				if t.text == "%" {
+					value = math.Mod(a, b)
+				}
+			case "^":
+				value = math.Pow(a, b)
+			}
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return 0, &ExprError{Column: t.column, Err: ErrNotFinite, Detail: t.text}
+			}
+			values.Push(value)
+		}
+	}
+	return values.Pop()
+}
+
+// Eval parses and evaluates the expression at once
+func Eval(expr string, vars map[string]float64) (value float64, err error) {
+	e, err := Parse(expr)
+	if err != nil {
+		return
+	}
+	return e.Evaluate(vars)
+}
+
+/*
+EvalExample evaluates the expression with the variables given as name=value pairs, ie "x=3,y=2"
+*/
+func EvalExample(w io.Writer, expression, variables string) error {
+	vars := map[string]float64{}
+	for _, pair := range strings.Split(variables, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil {
+			return fmt.Errorf("%w: variable %q should be name=number", ErrSyntax, pair)
+		}
+		vars[strings.TrimSpace(name)] = v
+	}
+
+	e, err := Parse(expression)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "Expression: ", expression)
+	fmt.Fprintln(w, "RPN: ", e)
+	value, err := e.Evaluate(vars)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "Variables: ", vars)
+	fmt.Fprintln(w, "Value: ", value)
+	return nil
+}
@@ -0,0 +1,78 @@
+package stack
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]float64{"x": 3, "rate_2": 0.5}
+	testCases := []struct {
+		name            string
+		expr            string
+		rpn_expected    string
+		output_expected float64
+	}{
+		{name: "Precedence-TC-1", expr: "2 + 3 * 4", rpn_expected: "2 3 4 * +", output_expected: 14},
+		{name: "Brackets-TC-2", expr: "(2 + 3) * 4", rpn_expected: "2 3 + 4 *", output_expected: 20},
+		{name: "LeftAssoc-TC-3", expr: "10 - 4 - 3", rpn_expected: "10 4 - 3 -", output_expected: 3},
+		{name: "Power-TC-4", expr: "2 ^ 3 ^ 2", rpn_expected: "2 3 2 ^ ^", output_expected: 512},
+		{name: "Unary-TC-5", expr: "-2 ^ 2", rpn_expected: "2 2 ^ neg", output_expected: -4},
+		{name: "Unary-TC-6", expr: "2 * -(x + 1)", rpn_expected: "2 x 1 + neg *", output_expected: -8},
+		{name: "Unary-TC-7", expr: "--x + +1", rpn_expected: "x neg neg 1 +", output_expected: 4},
+		{name: "Float-TC-8", expr: "1.5 * rate_2 % 0.5", rpn_expected: "1.5 rate_2 * 0.5 %", output_expected: 0.25},
+		{name: "Function-TC-9", expr: "max(1, x * 2, 4) + min (7, 2)", rpn_expected: "1 x 2 * 4 max/3 7 2 min/2 +", output_expected: 8},
+		{name: "Function-TC-10", expr: "-sqrt(abs(-16)) / 2", rpn_expected: "16 neg abs/1 sqrt/1 neg 2 /", output_expected: -2},
+		{name: "Nested-TC-11", expr: "max(min(1, 2), (3))", rpn_expected: "1 2 min/2 3 max/2", output_expected: 3},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := Parse(tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.String() != tc.rpn_expected {
+				t.Errorf("Expected RPN %q, got: %q", tc.rpn_expected, e.String())
+			}
+			if v, err := e.Evaluate(vars); err != nil || math.Abs(v-tc.output_expected) > 1e-9 {
+				t.Errorf("Expected %v, got: %v %v", tc.output_expected, v, err)
+			}
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	testCases := []struct {
+		name            string
+		expr            string
+		err_expected    error
+		column_expected int
+	}{
+		{name: "Empty-TC-1", expr: "", err_expected: ErrSyntax, column_expected: 1},
+		{name: "Character-TC-2", expr: "2 $ 3", err_expected: ErrSyntax, column_expected: 3},
+		{name: "Number-TC-3", expr: "1.2.3 + 1", err_expected: ErrSyntax, column_expected: 1},
+		{name: "MissingOperand-TC-4", expr: "2 + * 3", err_expected: ErrSyntax, column_expected: 5},
+		{name: "TrailingOperator-TC-5", expr: "2 +", err_expected: ErrSyntax, column_expected: 4},
+		{name: "Operands-TC-6", expr: "2 3", err_expected: ErrSyntax, column_expected: 3},
+		{name: "Unclosed-TC-7", expr: "(2 + 3", err_expected: ErrSyntax, column_expected: 1},
+		{name: "Unexpected-TC-8", expr: "2 + 3)", err_expected: ErrSyntax, column_expected: 6},
+		{name: "Comma-TC-9", expr: "(1, 2)", err_expected: ErrSyntax, column_expected: 3},
+		{name: "EmptyArgument-TC-10", expr: "max(1,)", err_expected: ErrSyntax, column_expected: 7},
+		{name: "UnknownFunction-TC-11", expr: "1 + avg(1)", err_expected: ErrUnknownFunction, column_expected: 5},
+		{name: "ArgCount-TC-12", expr: "abs(1, 2)", err_expected: ErrArgCount, column_expected: 1},
+		{name: "ArgCount-TC-13", expr: "max()", err_expected: ErrArgCount, column_expected: 1},
+		{name: "UnknownVariable-TC-14", expr: "x + y", err_expected: ErrUnknownVariable, column_expected: 5},
+		{name: "DivisionByZero-TC-15", expr: "1 / (x - 3)", err_expected: ErrDivisionByZero, column_expected: 3},
+		{name: "NotFinite-TC-16", expr: "sqrt(-x)", err_expected: ErrNotFinite, column_expected: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Eval(tc.expr, map[string]float64{"x": 3})
+			var exprErr *ExprError
+			if !errors.Is(err, tc.err_expected) || !errors.As(err, &exprErr) || exprErr.Column != tc.column_expected {
+				t.Errorf("Expected %v at column %d, got: %v", tc.err_expected, tc.column_expected, err)
+			}
+		})
+	}
+}
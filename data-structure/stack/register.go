@@ -0,0 +1,48 @@
+package stack
+
+import (
+	"context"
+	"examples/registry"
+	"io"
+)
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "stack",
+		Title:       "Stack Array",
+		Category:    registry.CATEGORY_DATA_STRUCTURE,
+		Description: "Generic LIFO stack backed by a slice: push, pop, peek, bounded capacity, clear and clone",
+		Source:      "data-structure/stack/stack.go",
+		Run:         registry.Simple(StackExample),
+	})
+	registry.Register(registry.Example{
+		Name:        "stack/eval",
+		Title:       "Stack Expression Evaluator",
+		Category:    registry.CATEGORY_DATA_STRUCTURE,
+		Description: "Infix expression to RPN via shunting-yard, evaluated on a stack: floats, variables, unary minus, ^ and max/min/abs/sqrt",
+		Source:      "data-structure/stack/expression.go",
+		Params: []registry.Param{
+			{Name: "expression", Type: registry.PARAM_STRING, Default: "max(2, x) * -(3 + 4.5) ^ 2", Usage: "infix expression"},
+			{Name: "vars", Type: registry.PARAM_STRING, Default: "x=3", Usage: "variables, ie x=3,y=2"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			// syntax and evaluation errors alike come from the expression
+			return registry.InvalidArgs(EvalExample(w, args.String("expression"), args.String("vars")))
+		},
+	})
+	registry.Register(registry.Example{
+		Name:        "stack/balanced",
+		Title:       "Stack Balance Checker",
+		Category:    registry.CATEGORY_DATA_STRUCTURE,
+		Description: "Check the brackets, and optionally the HTML tags, are balanced via a stack, reporting the line and column of the first error",
+		Source:      "data-structure/stack/balanced.go",
+		Params: []registry.Param{
+			{Name: "input", Type: registry.PARAM_STRING, Default: "<div><p>(a [b])</div>", Usage: "text to check"},
+			{Name: "html", Type: registry.PARAM_INT, Default: "1", Usage: "1 to check the HTML tags as well"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			BalancedExample(w, args.String("input"), args.Int("html") == 1)
+			return nil
+		},
+	})
+}
@@ -1,7 +1,10 @@
 package stack
 
 import (
+	"errors"
+	"examples/iterator"
 	"fmt"
+	"io"
 )
 
 /*
@@ -9,50 +12,125 @@ LIFO : Last In First Out
 Operations:
 Push - Append to the end of slice
 Pop - Remove from end of the slice
+Peek - Read the end of the slice, without removing it
 Len - length of slice
+
+The stack does not print, the caller does with what Push/Pop return, see StackExample.
+It is not safe for concurrent use, see LockFree and Synced.
 */
 
-type Stack struct {
-	stackSlice []interface{}
-	isEmpty    bool
+var (
+	ErrEmpty = errors.New("Stack is Empty!")
+	ErrFull  = errors.New("Stack is Full!")
+)
+
+type Stack[T any] struct {
+	stackSlice []T
+	capacity   int // 0 is unbounded
+}
+
+/*
+InitStack of at most capacity elements, Push fails with ErrFull beyond it. A capacity < 1 is unbounded.
+*/
+func InitStack[T any](capacity int) (s *Stack[T]) {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &Stack[T]{capacity: capacity}
 }
 
-func (s *Stack) Pop() (ele interface{}, err error) {
+func (s *Stack[T]) Pop() (ele T, err error) {
 	if len(s.stackSlice) == 0 {
-		err = fmt.Errorf("Stack is Empty!!")
+		err = ErrEmpty
 		return
 	}
 
 	ele = s.stackSlice[len(s.stackSlice)-1]
+	// clear the popped slot, so the slice does not keep the element alive
+	var zero T
+	s.stackSlice[len(s.stackSlice)-1] = zero
 	s.stackSlice = s.stackSlice[0 : len(s.stackSlice)-1]
-	fmt.Println("Pop: ", ele)
 	return
 }
 
-func (s *Stack) Push(ele interface{}) (err error) {
-	fmt.Println("Push: ", ele)
+func (s *Stack[T]) Push(ele T) (err error) {
+	if s.capacity > 0 && len(s.stackSlice) >= s.capacity {
+		return fmt.Errorf("%w: capacity %d", ErrFull, s.capacity)
+	}
 	s.stackSlice = append(s.stackSlice, ele)
 	return
 }
 
-func (s *Stack) Print() (err error) {
-	fmt.Println("Print Stack:")
-	for ele := len(s.stackSlice) - 1; ele >= 0; ele-- {
-		fmt.Println(s.stackSlice[ele])
+// Peek returns the top of the stack, without removing it
+func (s *Stack[T]) Peek() (ele T, err error) {
+	if len(s.stackSlice) == 0 {
+		err = ErrEmpty
+		return
 	}
-	return
+	return s.stackSlice[len(s.stackSlice)-1], nil
+}
+
+func (s *Stack[T]) Len() int {
+	return len(s.stackSlice)
 }
 
-func StackExample() {
-	stack := Stack{}
+// Cap is the capacity limit, 0 when unbounded
+func (s *Stack[T]) Cap() int {
+	return s.capacity
+}
+
+func (s *Stack[T]) IsEmpty() bool {
+	return len(s.stackSlice) == 0
+}
+
+// Clear removes all the elements, releasing them
+func (s *Stack[T]) Clear() {
+	s.stackSlice = nil
+}
+
+// Clone returns a copy with the same elements and capacity, the elements themselves are copied shallow
+func (s *Stack[T]) Clone() *Stack[T] {
+	c := &Stack[T]{capacity: s.capacity}
+	if len(s.stackSlice) > 0 {
+		c.stackSlice = make([]T, len(s.stackSlice))
+		copy(c.stackSlice, s.stackSlice)
+	}
+	return c
+}
+
+// Iter from the top to the bottom of the stack, in the order the elements would be popped
+func (s *Stack[T]) Iter() iterator.Iterator[T] {
+	i := len(s.stackSlice)
+	return iterator.Func[T](func() (ele T, ok bool) {
+		if i == 0 {
+			return
+		}
+		i--
+		return s.stackSlice[i], true
+	})
+}
+
+func StackExample(w io.Writer) {
+	stack := InitStack[int](6)
 	if _, er := stack.Pop(); er != nil {
-		fmt.Println(er)
+		fmt.Fprintln(w, er)
 	}
-	for i := 0; i < 6; i++ {
-		stack.Push(i)
+	for i := 0; i < 7; i++ {
+		if err := stack.Push(i); err != nil {
+			fmt.Fprintln(w, "Push: ", i, err)
+			continue
+		}
+		fmt.Fprintln(w, "Push: ", i)
 	}
-	stack.Print()
-	stack.Pop()
+	fmt.Fprintln(w, "Print Stack: ", iterator.Collect(stack.Iter()))
+
+	ele, _ := stack.Pop()
+	fmt.Fprintln(w, "Pop: ", ele)
 	stack.Push(8)
-	stack.Print()
+	top, _ := stack.Peek()
+	fmt.Fprintln(w, "Peek: ", top, "Len: ", stack.Len(), "Cap: ", stack.Cap())
+
+	clone := stack.Clone()
+	stack.Clear()
+	fmt.Fprintln(w, "Cleared, Len: ", stack.Len(), "Clone: ", iterator.Collect(clone.Iter()))
 }
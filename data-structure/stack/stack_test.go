@@ -0,0 +1,81 @@
+package stack
+
+import (
+	"errors"
+	"examples/iterator"
+	"reflect"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	testCases := []struct {
+		name            string
+		capacity        int
+		push            []int
+		pops            int
+		output_expected []int // top first, after the pops
+		err_expected    error
+	}{
+		{name: "Empty-TC-1", pops: 1, output_expected: []int{}, err_expected: ErrEmpty},
+		{name: "PushPop-TC-2", push: []int{1, 2, 3}, pops: 1, output_expected: []int{2, 1}},
+		{name: "Unbounded-TC-3", capacity: -1, push: []int{1, 2, 3, 4}, output_expected: []int{4, 3, 2, 1}},
+		{name: "Full-TC-4", capacity: 2, push: []int{1, 2, 3}, output_expected: []int{2, 1}, err_expected: ErrFull},
+		{name: "PopAll-TC-5", push: []int{1, 2}, pops: 3, output_expected: []int{}, err_expected: ErrEmpty},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := InitStack[int](tc.capacity)
+			var err error
+			for _, v := range tc.push {
+				if e := s.Push(v); e != nil {
+					err = e
+				}
+			}
+			for i := 0; i < tc.pops; i++ {
+				if _, e := s.Pop(); e != nil {
+					err = e
+				}
+			}
+			if tc.err_expected != nil && !errors.Is(err, tc.err_expected) || tc.err_expected == nil && err != nil {
+				t.Errorf("Expected error %v, got: %v", tc.err_expected, err)
+			}
+			got := iterator.Collect(s.Iter())
+			if got == nil {
+				got = []int{}
+			}
+			if !reflect.DeepEqual(got, tc.output_expected) || s.Len() != len(tc.output_expected) {
+				t.Errorf("Expected %v, got: %v (len %d)", tc.output_expected, got, s.Len())
+			}
+		})
+	}
+}
+
+func TestStackPeekClearClone(t *testing.T) {
+	s := InitStack[string](3)
+	if _, err := s.Peek(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected %v, got: %v", ErrEmpty, err)
+	}
+	s.Push("a")
+	s.Push("b")
+	if top, err := s.Peek(); top != "b" || err != nil || s.Len() != 2 {
+		t.Errorf("Expected b without removing it, got: %v %v (len %d)", top, err, s.Len())
+	}
+
+	c := s.Clone()
+	c.Push("c")
+	s.Pop()
+	if got := iterator.Collect(c.Iter()); !reflect.DeepEqual(got, []string{"c", "b", "a"}) || c.Cap() != 3 {
+		t.Errorf("Expected the clone independent of the stack, got: %v", got)
+	}
+	if err := c.Push("d"); !errors.Is(err, ErrFull) {
+		t.Errorf("Expected the capacity cloned, got: %v", err)
+	}
+
+	s.Clear()
+	if !s.IsEmpty() || s.Len() != 0 {
+		t.Errorf("Expected empty once cleared, got len: %v", s.Len())
+	}
+	if s.Push("e"); s.Len() != 1 {
+		t.Errorf("Expected the cleared stack usable, got len: %v", s.Len())
+	}
+}
@@ -1,6 +1,9 @@
 package tree
 
-import "fmt"
+import (
+	"examples/data-structure/queue"
+	"fmt"
+)
 
 /*
 Reference: https://faun.pub/2-different-ways-to-implement-bfs-in-golang-8399f5d2452d
@@ -46,26 +49,25 @@ func BreadthFirstSearchViaQueue(root *BstNode) (visitedNode []interface{}, err e
 	// create visited nodes slice
 	visitedNode = make([]interface{}, 1)
 
-	// Add first node to Queue
-	q := []QueueNode{
-		{Node: root, Level: 0},
-	}
+	// Add first node to Queue, a ring buffer: dequeuing by re-slicing (q = q[1:]) would keep
+	// the dequeued nodes and the backing array alive as the queue moves along
+	q := queue.InitDeque[QueueNode](0)
+	q.PushBack(QueueNode{Node: root, Level: 0})
 
 	// Iterate tree
-	for len(q) > 0 {
-		currNode, currLevel := q[0].Node, q[0].Level
+	for q.Len() > 0 {
+		// dequeue traversed node
+		curr, _ := q.PopFront()
+		currNode, currLevel := curr.Node, curr.Level
 		visitedNode = append(visitedNode, currNode.Data)
 
-		// dequeuete traversed node
-		q = q[1:]
-
 		// Enqueue left node of current node
 		if currNode.Left != nil {
-			q = append(q, QueueNode{Node: currNode.Left, Level: currLevel + 1})
+			q.PushBack(QueueNode{Node: currNode.Left, Level: currLevel + 1})
 		}
 		// Enqueue right node of current node
 		if currNode.Right != nil {
-			q = append(q, QueueNode{Node: currNode.Right, Level: currLevel + 1})
+			q.PushBack(QueueNode{Node: currNode.Right, Level: currLevel + 1})
 		}
 	}
 	return
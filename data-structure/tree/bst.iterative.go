@@ -1,6 +1,9 @@
 package tree
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /*
 				5
@@ -28,6 +31,7 @@ type Bst struct {
 	Root        *BstNode
 	NodeCount   int
 	visitedNode []int
+	out         io.Writer
 }
 
 func (t *Bst) CreateNode(data int) *BstNode {
@@ -69,20 +73,20 @@ func (t *Bst) Find(data int) (found bool) {
 	current := t.Root
 	for {
 		if current.Data == data {
-			fmt.Printf("\nNode found : %v", data)
+			fmt.Fprintf(t.out, "\nNode found : %v", data)
 			found = true
 			return
 		}
 		if current.Data > data {
 			if current.Left == nil {
-				fmt.Printf("\nNode not found : %v", data)
+				fmt.Fprintf(t.out, "\nNode not found : %v", data)
 				return
 			}
 			current = current.Left
 		}
 		if current.Data < data {
 			if current.Right == nil {
-				fmt.Printf("\nNode not found : %v", data)
+				fmt.Fprintf(t.out, "\nNode not found : %v", data)
 				return
 			}
 			current = current.Right
@@ -92,27 +96,27 @@ func (t *Bst) Find(data int) (found bool) {
 }
 
 func (t *Bst) Traversal(order string) {
-	fmt.Println("\nTree Traversal : ")
+	fmt.Fprintln(t.out, "\nTree Traversal : ")
 	var visitedNode []*BstNode
 
 	switch order {
 	case InOrder:
-		fmt.Println("InOrder - Using Stack")
+		fmt.Fprintln(t.out, "InOrder - Using Stack")
 		visitedNode = t.inOrder(t.Root)
 		for _, node := range visitedNode {
-			fmt.Printf("-->%v", node.Data)
+			fmt.Fprintf(t.out, "-->%v", node.Data)
 		}
 
 	case PreOrder:
-		fmt.Println("PreOrder")
+		fmt.Fprintln(t.out, "PreOrder")
 		//visitedNode = t.preOrder(t.root)
-		fmt.Println(visitedNode)
+		fmt.Fprintln(t.out, visitedNode)
 	case PostOrder:
-		fmt.Println("PostOrder")
+		fmt.Fprintln(t.out, "PostOrder")
 		//visitedNode = t.postOrder(t.root)
-		fmt.Println(visitedNode)
+		fmt.Fprintln(t.out, visitedNode)
 	default:
-		fmt.Println("Order not implemeted yet : ", order)
+		fmt.Fprintln(t.out, "Order not implemeted yet : ", order)
 	}
 }
 
@@ -144,13 +148,13 @@ func (t *Bst) inOrder(root *BstNode) (visitedNode []*BstNode) {
 	return
 }
 
-func TreeBstIterativeExample() {
+func TreeBstIterativeExample(w io.Writer) {
 	data := []int{5, 3, 2, 4, 1, 7, 6, 8, 9}
-	tree := &Bst{}
+	tree := &Bst{out: w}
 	for _, v := range data {
 		tree.InsertNode(v)
 	}
-	fmt.Println("Total Nodes : ", tree.NodeCount)
+	fmt.Fprintln(w, "Total Nodes : ", tree.NodeCount)
 
 	tree.Find(1)
 	tree.Find(9)
@@ -158,23 +162,23 @@ func TreeBstIterativeExample() {
 
 	//tree.Traversal(InOrder)
 
-	fmt.Println("\nBreadth First Search: Using Queue - (aka: Level Order Traversal)")
+	fmt.Fprintln(w, "\nBreadth First Search: Using Queue - (aka: Level Order Traversal)")
 	visitedNode, err := BreadthFirstSearchViaQueue(tree.Root)
 	if err != nil {
-		fmt.Println(err.Error())
+		fmt.Fprintln(w, err.Error())
 	} else {
-		fmt.Println("LevelOrder Traversal : ", visitedNode)
+		fmt.Fprintln(w, "LevelOrder Traversal : ", visitedNode)
 
 	}
 
-	fmt.Println("\nDepth First Search: Using Stack - (InOrder Traversal)")
+	fmt.Fprintln(w, "\nDepth First Search: Using Stack - (InOrder Traversal)")
 	visitedNode, err = DepthFirstSearchViaStack(tree.Root)
 	if err != nil {
-		fmt.Println(err.Error())
+		fmt.Fprintln(w, err.Error())
 	} else {
-		fmt.Println("InOrder Traversal : ")
+		fmt.Fprintln(w, "InOrder Traversal : ")
 		for _, node := range visitedNode {
-			fmt.Printf("->%v", node.(*BstNode).Data)
+			fmt.Fprintf(w, "->%v", node.(*BstNode).Data)
 		}
 
 	}
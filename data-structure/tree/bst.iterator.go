@@ -0,0 +1,146 @@
+package tree
+
+import (
+	"examples/data-structure/queue"
+	"examples/iterator"
+)
+
+/*
+Lazy iterators over the binary search trees (Bst and bst), the nodes are visited as Next is called,
+using an explicit stack (depth first) or queue (breadth first) instead of recursion.
+
+				5
+		|--------------|
+		3				7
+	|--------|		|-------|
+	2		4		6		8
+|-------|				|-------|
+1								9
+
+InOrder :    1 -> 2 -> 3 -> 4 -> 5 -> 6 -> 7 -> 8 -> 9
+PreOrder :   5 -> 3 -> 2 -> 1 -> 4 -> 7 -> 6 -> 8 -> 9
+PostOrder :  1 -> 2 -> 4 -> 3 -> 6 -> 9 -> 8 -> 7 -> 5
+LevelOrder : 5 -> 3 -> 7 -> 2 -> 4 -> 6 -> 8 -> 1 -> 9
+*/
+
+// walker works with both node types, N is a node pointer and its zero value is nil
+type walker[N comparable] struct {
+	left  func(N) N
+	right func(N) N
+	data  func(N) int
+}
+
+var (
+	bstWalker = walker[*BstNode]{
+		left:  func(n *BstNode) *BstNode { return n.Left },
+		right: func(n *BstNode) *BstNode { return n.Right },
+		data:  func(n *BstNode) int { return n.Data },
+	}
+	bstRecursiveWalker = walker[*bstNode]{
+		left:  func(n *bstNode) *bstNode { return n.left },
+		right: func(n *bstNode) *bstNode { return n.right },
+		data:  func(n *bstNode) int { return n.data },
+	}
+)
+
+func (wk walker[N]) inOrder(root N) iterator.Iterator[int] {
+	var null N
+	stack := []N{}
+	current := root
+	return iterator.Func[int](func() (data int, ok bool) {
+		// push the left spine, the top of the stack is the smallest node not visited yet
+		for current != null {
+			stack = append(stack, current)
+			current = wk.left(current)
+		}
+		if len(stack) == 0 {
+			return
+		}
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		current = wk.right(node)
+		return wk.data(node), true
+	})
+}
+
+func (wk walker[N]) preOrder(root N) iterator.Iterator[int] {
+	var null N
+	stack := []N{}
+	if root != null {
+		stack = append(stack, root)
+	}
+	return iterator.Func[int](func() (data int, ok bool) {
+		if len(stack) == 0 {
+			return
+		}
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		// push right first, so that left is popped first
+		if right := wk.right(node); right != null {
+			stack = append(stack, right)
+		}
+		if left := wk.left(node); left != null {
+			stack = append(stack, left)
+		}
+		return wk.data(node), true
+	})
+}
+
+func (wk walker[N]) postOrder(root N) iterator.Iterator[int] {
+	var null, lastVisited N
+	stack := []N{}
+	current := root
+	return iterator.Func[int](func() (data int, ok bool) {
+		for current != null || len(stack) > 0 {
+			if current != null {
+				stack = append(stack, current)
+				current = wk.left(current)
+				continue
+			}
+			top := stack[len(stack)-1]
+			// visit the right subtree before the node itself
+			if right := wk.right(top); right != null && right != lastVisited {
+				current = right
+				continue
+			}
+			stack = stack[:len(stack)-1]
+			lastVisited = top
+			return wk.data(top), true
+		}
+		return
+	})
+}
+
+func (wk walker[N]) levelOrder(root N) iterator.Iterator[int] {
+	var null N
+	q := queue.InitDeque[N](0)
+	if root != null {
+		q.PushBack(root)
+	}
+	return iterator.Func[int](func() (data int, ok bool) {
+		node, err := q.PopFront()
+		if err != nil {
+			return
+		}
+		if left := wk.left(node); left != null {
+			q.PushBack(left)
+		}
+		if right := wk.right(node); right != null {
+			q.PushBack(right)
+		}
+		return wk.data(node), true
+	})
+}
+
+// Iter in order, ie the sorted data
+func (t *Bst) Iter() iterator.Iterator[int]       { return t.InOrder() }
+func (t *Bst) InOrder() iterator.Iterator[int]    { return bstWalker.inOrder(t.Root) }
+func (t *Bst) PreOrder() iterator.Iterator[int]   { return bstWalker.preOrder(t.Root) }
+func (t *Bst) PostOrder() iterator.Iterator[int]  { return bstWalker.postOrder(t.Root) }
+func (t *Bst) LevelOrder() iterator.Iterator[int] { return bstWalker.levelOrder(t.Root) }
+
+func (t *bst) Iter() iterator.Iterator[int]       { return t.InOrder() }
+func (t *bst) InOrder() iterator.Iterator[int]    { return bstRecursiveWalker.inOrder(t.root) }
+func (t *bst) PreOrder() iterator.Iterator[int]   { return bstRecursiveWalker.preOrder(t.root) }
+func (t *bst) PostOrder() iterator.Iterator[int]  { return bstRecursiveWalker.postOrder(t.root) }
+func (t *bst) LevelOrder() iterator.Iterator[int] { return bstRecursiveWalker.levelOrder(t.root) }
@@ -0,0 +1,69 @@
+package tree
+
+import (
+	"examples/iterator"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestBstIterators(t *testing.T) {
+	testCases := []struct {
+		name       string
+		data       []int
+		inOrder    []int
+		preOrder   []int
+		postOrder  []int
+		levelOrder []int
+	}{
+		{
+			name:       "Tree-TC-1",
+			data:       []int{5, 3, 2, 4, 1, 7, 6, 8, 9},
+			inOrder:    []int{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			preOrder:   []int{5, 3, 2, 1, 4, 7, 6, 8, 9},
+			postOrder:  []int{1, 2, 4, 3, 6, 9, 8, 7, 5},
+			levelOrder: []int{5, 3, 7, 2, 4, 6, 8, 1, 9},
+		},
+		{
+			name: "Empty-TC-2", data: []int{},
+			inOrder: []int{}, preOrder: []int{}, postOrder: []int{}, levelOrder: []int{},
+		},
+		{
+			name: "Single-TC-3", data: []int{1},
+			inOrder: []int{1}, preOrder: []int{1}, postOrder: []int{1}, levelOrder: []int{1},
+		},
+		{
+			name:    "RightSkewed-TC-4",
+			data:    []int{1, 2, 3},
+			inOrder: []int{1, 2, 3}, preOrder: []int{1, 2, 3}, postOrder: []int{3, 2, 1}, levelOrder: []int{1, 2, 3},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			iterative := &Bst{out: io.Discard}
+			recursive := &bst{out: io.Discard}
+			for _, v := range tc.data {
+				iterative.InsertNode(v)
+				recursive.insert(v)
+			}
+
+			orders := []struct {
+				name     string
+				its      []iterator.Iterator[int]
+				expected []int
+			}{
+				{"InOrder", []iterator.Iterator[int]{iterative.InOrder(), recursive.InOrder(), iterative.Iter()}, tc.inOrder},
+				{"PreOrder", []iterator.Iterator[int]{iterative.PreOrder(), recursive.PreOrder()}, tc.preOrder},
+				{"PostOrder", []iterator.Iterator[int]{iterative.PostOrder(), recursive.PostOrder()}, tc.postOrder},
+				{"LevelOrder", []iterator.Iterator[int]{iterative.LevelOrder(), recursive.LevelOrder()}, tc.levelOrder},
+			}
+			for _, o := range orders {
+				for _, it := range o.its {
+					if out := iterator.Collect(it); !reflect.DeepEqual(out, o.expected) {
+						t.Errorf("%v: Actual output: %v, Expected output: %v", o.name, out, o.expected)
+					}
+				}
+			}
+		})
+	}
+}
@@ -43,7 +43,10 @@ package tree
 	3. Deletion     		O(n)					O(n)			O(n)		O(log n)+
 */
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 const (
 	PreOrder  = "PreOrder"
@@ -60,10 +63,11 @@ type bstNode struct {
 type bst struct {
 	root        *bstNode
 	visitedNode []int //optional: to store traversed nodes
+	out         io.Writer
 }
 
 func (t *bst) insert(data int) {
-	fmt.Println("Insert node: ", data)
+	fmt.Fprintln(t.out, "Insert node: ", data)
 	t.insertRec(t.root, data)
 }
 
@@ -92,12 +96,12 @@ func (t *bst) insertRec(node *bstNode, data int) *bstNode {
 }
 
 func (t *bst) find(data int) {
-	fmt.Println("\nFind data : ", data)
+	fmt.Fprintln(t.out, "\nFind data : ", data)
 	node := t.findRecursive(t.root, data)
 	if node == nil {
-		fmt.Println("Node not found : ", data)
+		fmt.Fprintln(t.out, "Node not found : ", data)
 	} else {
-		fmt.Println("Node found : ", node.data)
+		fmt.Fprintln(t.out, "Node found : ", node.data)
 	}
 	return
 }
@@ -123,22 +127,22 @@ func (t *bst) findRecursive(node *bstNode, data int) *bstNode {
 }
 
 func (t *bst) traverse(order string) {
-	fmt.Println("Tree Traversal : ")
+	fmt.Fprintln(t.out, "Tree Traversal : ")
 
 	t.visitedNode = []int{} //make([]int, 0)
 
 	switch order {
 	case InOrder:
-		fmt.Println("InOrder")
+		fmt.Fprintln(t.out, "InOrder")
 		t.inOrder(t.root)
 	case PreOrder:
-		fmt.Println("PreOrder")
+		fmt.Fprintln(t.out, "PreOrder")
 		t.preOrder(t.root)
 	case PostOrder:
-		fmt.Println("PostOrder")
+		fmt.Fprintln(t.out, "PostOrder")
 		t.postOrder(t.root)
 	default:
-		fmt.Println("Order not implemeted yet : ", order)
+		fmt.Fprintln(t.out, "Order not implemeted yet : ", order)
 	}
 
 }
@@ -184,29 +188,29 @@ Inorder :   1 -> 2 -> 3 -> 4 -> 5 -> 6 -> 7 -> 8 -> 9
 Preorder :  5 -> 3 -> 2 -> 1 -> 4 -> 7 -> 6 -> 8 -> 9
 Postorder : 1 -> 2 -> 4 -> 3 -> 6 -> 9 -> 8 -> 7 -> 5
 */
-func TreeBstExample() {
+func TreeBstExample(w io.Writer) {
 	data := []int{5, 3, 2, 4, 1, 7, 6, 8, 9}
-	tree := &bst{}
+	tree := &bst{out: w}
 	for _, v := range data {
 		tree.insert(v)
 	}
 	tree.traverse(InOrder)
-	fmt.Println(tree.visitedNode)
+	fmt.Fprintln(w, tree.visitedNode)
 	tree.traverse(PreOrder)
-	fmt.Println(tree.visitedNode)
+	fmt.Fprintln(w, tree.visitedNode)
 	tree.traverse(PostOrder)
-	fmt.Println(tree.visitedNode)
+	fmt.Fprintln(w, tree.visitedNode)
 
 	tree.find(1)
 	tree.find(9)
 	tree.find(0)
 
-	fmt.Println("\nBreadth First Search: Using Map - (aka: Level Order Traversal)")
+	fmt.Fprintln(w, "\nBreadth First Search: Using Map - (aka: Level Order Traversal)")
 	visitedNode, err := BreadthFirstSearchViaMap(tree.root)
 	if err != nil {
-		fmt.Println(err.Error())
+		fmt.Fprintln(w, err.Error())
 	} else {
-		fmt.Println("LevelOrder Traversal : ", visitedNode)
+		fmt.Fprintln(w, "LevelOrder Traversal : ", visitedNode)
 
 	}
 
@@ -0,0 +1,30 @@
+package tree
+
+import "examples/registry"
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "tree/bst/recursive",
+		Title:       "Tree BST Recursive",
+		Category:    registry.CATEGORY_DATA_STRUCTURE,
+		Description: "Binary search tree with recursive insert, find and in/pre/post order traversal",
+		Source:      "data-structure/tree/bst.recursive.go",
+		Run:         registry.Simple(TreeBstExample),
+	})
+	registry.Register(registry.Example{
+		Name:        "tree/bst/iterative",
+		Title:       "Tree BST Iterative",
+		Category:    registry.CATEGORY_DATA_STRUCTURE,
+		Description: "Binary search tree with iterative insert and find, BFS via a ring buffer queue and DFS via stack",
+		Source:      "data-structure/tree/bst.iterative.go",
+		Run:         registry.Simple(TreeBstIterativeExample),
+	})
+	registry.Register(registry.Example{
+		Name:        "tree/bst/array",
+		Title:       "Tree Array Representation",
+		Category:    registry.CATEGORY_DATA_STRUCTURE,
+		Description: "Represent a binary tree as an array from its level order traversal",
+		Source:      "data-structure/tree/tree.in.array.go",
+		Run:         registry.Simple(TreeViaArrayExample),
+	})
+}
@@ -1,6 +1,9 @@
 package tree
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /*
 A Binary tree can be respresented by an array.
@@ -13,29 +16,29 @@ A Level-Order Traversal of a binary tree will result in an array follwoing the
 
 */
 
-func TreeViaArrayExample() {
+func TreeViaArrayExample(w io.Writer) {
 	data := []int{5, 3, 2, 4, 1, 7, 6, 8, 9}
-	tree := &Bst{}
+	tree := &Bst{out: w}
 	for _, v := range data {
 		tree.InsertNode(v)
 	}
-	fmt.Println("Total Nodes : ", tree.NodeCount)
+	fmt.Fprintln(w, "Total Nodes : ", tree.NodeCount)
 
-	fmt.Println("\nBreadth First Search: Using Queue - (aka: Level Order Traversal)")
+	fmt.Fprintln(w, "\nBreadth First Search: Using Queue - (aka: Level Order Traversal)")
 	visitedNode, err := BreadthFirstSearchViaQueue(tree.Root)
 	if err != nil {
-		fmt.Println(err.Error())
+		fmt.Fprintln(w, err.Error())
 	} else {
-		fmt.Println("LevelOrder Traversal : ", visitedNode)
+		fmt.Fprintln(w, "LevelOrder Traversal : ", visitedNode)
 	}
 
-	fmt.Println("Array representation of Tree")
+	fmt.Fprintln(w, "Array representation of Tree")
 	for i, val := range visitedNode {
 		parentIndex := i
 		leftChildIndex := 2 * i
 		rightChildIndex := 2*i + 1
 		if len(visitedNode) > leftChildIndex {
-			fmt.Printf("\nParent(%v): %v -> Left-Right : %v-%v",
+			fmt.Fprintf(w, "\nParent(%v): %v -> Left-Right : %v-%v",
 				val,
 				visitedNode[parentIndex],
 				visitedNode[leftChildIndex],
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"io"
 )
 
 /*
@@ -19,25 +20,25 @@ So an array variable name is not a pointer to the first element in fact it denot
 - An array variable is assigned to another array variable.
 - An array variable is passed as an argument to a function.
 */
-func ArrayDeclaration() {
-	fmt.Println("Array Declations!!")
+func ArrayDeclaration(w io.Writer) {
+	fmt.Fprintln(w, "Array Declations!!")
 	var a [3]int
 	a = [3]int{1, 2} // As length is 3, third element will get default value
-	fmt.Println()
-	fmt.Printf("a: %v, length: %v", a, len(a))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "a: %v, length: %v", a, len(a))
 
 	a1 := []bool{true, false, false} // length calculated by compiler
-	fmt.Println()
-	fmt.Printf("a: %v, length: %v", a1, len(a1))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "a: %v, length: %v", a1, len(a1))
 
 	a2 := [3]int{} // initialised with default value of data type
-	fmt.Println()
-	fmt.Printf("a: %v, length: %v", a2, len(a2))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "a: %v, length: %v", a2, len(a2))
 
 	a3 := [...]int{} // length calculated by compiler
-	fmt.Println()
-	fmt.Printf("a: %v, length: %v", a3, len(a3))
-	fmt.Println()
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "a: %v, length: %v", a3, len(a3))
+	fmt.Fprintln(w)
 }
 
 /*
@@ -46,32 +47,32 @@ An array can be iterated using:
 - Using for loop
 - Using for-range loop
 */
-func ArrayIterate() {
-	fmt.Println()
-	fmt.Println("Array Iterate!!")
-	fmt.Println()
+func ArrayIterate(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Array Iterate!!")
+	fmt.Fprintln(w)
 
 	a := [...]string{"a", "b", "c", "d"}
 	len := len(a)
-	fmt.Println("1. Using for loop")
+	fmt.Fprintln(w, "1. Using for loop")
 	for i := 0; i < len; i++ {
-		fmt.Printf("%v ", a[i])
+		fmt.Fprintf(w, "%v ", a[i])
 	}
 
-	fmt.Println()
-	fmt.Println()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w)
 
-	fmt.Println("2. Using for-range loop")
+	fmt.Fprintln(w, "2. Using for-range loop")
 	for i, v := range a {
-		fmt.Printf("a[%v]=%v ", i, v)
+		fmt.Fprintf(w, "a[%v]=%v ", i, v)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 }
 
-func ArrayMultiDimention() {
-	fmt.Println()
-	fmt.Println("Array Multi Dimension!!")
-	fmt.Println()
+func ArrayMultiDimention(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Array Multi Dimension!!")
+	fmt.Fprintln(w)
 	a := [3][3]int{
 		{1, 2, 3},
 		{4, 5, 6},
@@ -79,10 +80,10 @@ func ArrayMultiDimention() {
 	}
 
 	for i, row := range a {
-		fmt.Println("Row:", i+1)
+		fmt.Fprintln(w, "Row:", i+1)
 		for _, col := range row {
-			fmt.Printf("%v ", col)
+			fmt.Fprintf(w, "%v ", col)
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 }
@@ -2,6 +2,7 @@ package channel
 
 import (
 	"fmt"
+	"io"
 	"sync"
 	"time"
 )
@@ -104,8 +105,8 @@ Pointers:
 3. We can use WaitGroup struct of sync package as well
 */
 
-func Basic() {
-	BasicsGoroutine()
+func Basic(w io.Writer) {
+	BasicsGoroutine(w)
 
 	result := 0
 	ch := make(chan int)
@@ -114,7 +115,7 @@ func Basic() {
 		wg.Add(1)
 		go func(i int, ch chan int, wg *sync.WaitGroup) {
 			result += i * 2
-			fmt.Printf("\nResult: %v - %v", i, result)
+			fmt.Fprintf(w, "\nResult: %v - %v", i, result)
 			//ch <- result
 			wg.Done()
 		}(i, ch, wg)
@@ -133,26 +134,26 @@ func Basic() {
 		}
 	*/
 	wg.Wait()
-	fmt.Println()
-	fmt.Printf("Result: %v", result)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Result: %v", result)
 
-	checkClosedChan()
+	checkClosedChan(w)
 
-	closeChannelViaSender()
+	closeChannelViaSender(w)
 
 }
 
-func checkClosedChan() {
-	fmt.Println("\nCheck Closed Channel")
+func checkClosedChan(w io.Writer) {
+	fmt.Fprintln(w, "\nCheck Closed Channel")
 	ch := make(chan int, 1)
 	ch <- 2
 	val, open := <-ch
-	fmt.Printf("Val: %d Open: %t\n", val, open)
+	fmt.Fprintf(w, "Val: %d Open: %t\n", val, open)
 
 	close(ch)
 	val, open = <-ch
 	// Read : default data type value, AND false as channel is closed
-	fmt.Printf("Val: %d Close: %t\n", val, open)
+	fmt.Fprintf(w, "Val: %d Close: %t\n", val, open)
 
 	ch1 := make(chan int, 3)
 	ch1 <- 1
@@ -162,28 +163,28 @@ func checkClosedChan() {
 	// goroutine below will leak, it will wait on channel to receive value
 	close(ch1)
 	go func() {
-		fmt.Printf("Range loop a channel\n")
+		fmt.Fprintf(w, "Range loop a channel\n")
 		for val := range ch1 {
-			fmt.Printf("Received Value: %v\n", val)
+			fmt.Fprintf(w, "Received Value: %v\n", val)
 		}
-		fmt.Printf("Channel Closed\n")
+		fmt.Fprintf(w, "Channel Closed\n")
 	}()
 
 	time.Sleep(time.Second * 2)
 }
 
-func closeChannelViaSender() {
-	fmt.Println("Example: Close channel via sender only")
+func closeChannelViaSender(w io.Writer) {
+	fmt.Fprintln(w, "Example: Close channel via sender only")
 
 	ch := make(chan int)
 	go senderChannel(ch)
 	var sum int
 	for v := range ch {
-		fmt.Println("Response: ", v)
+		fmt.Fprintln(w, "Response: ", v)
 		sum += v
 	}
-	fmt.Println("Total Response: ", sum)
-	fmt.Println("Channel closed now")
+	fmt.Fprintln(w, "Total Response: ", sum)
+	fmt.Fprintln(w, "Channel closed now")
 }
 
 func senderChannel(ch chan<- int) {
@@ -0,0 +1,91 @@
+package channel
+
+import (
+	"context"
+	"examples/eventbus"
+	"examples/registry"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const MAX_BROADCAST_EVENTS = 1000
+
+var ErrBroadcastArgs = registry.InvalidArgs(fmt.Errorf("Events should be 1 to %d!", MAX_BROADCAST_EVENTS))
+
+/*
+Broadcast, unlike fan-out where every value is received by one of the workers, every subscriber
+of a topic receives every event, see eventbus.Bus.
+
+ExecuteBroadcast publishes events on the worker and pipeline topics to three subscribers:
+  - audit subscribes to every topic (#) with a buffer large enough to keep up
+  - dashboard subscribes to worker.* but reads slowly, it drops the oldest events to show the latest ones
+  - billing subscribes to pipeline.# and must not miss any, the publisher blocks for it up to a timeout
+
+The subscriptions end with the context, which closes their channels.
+*/
+func ExecuteBroadcast(ctx context.Context, w io.Writer, events int) error {
+	if events < 1 || events > MAX_BROADCAST_EVENTS {
+		return ErrBroadcastArgs
+	}
+	bus := eventbus.New()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	subscribers := []struct {
+		name    string
+		pattern string
+		opts    eventbus.Options
+		delay   time.Duration // to read an event
+	}{
+		{name: "audit", pattern: "#", opts: eventbus.Options{Buffer: 2 * events, Policy: eventbus.POLICY_DROP_OLDEST}},
+		{name: "dashboard", pattern: "worker.*", opts: eventbus.Options{Buffer: 2, Policy: eventbus.POLICY_DROP_OLDEST}, delay: 20 * time.Millisecond},
+		{name: "billing", pattern: "pipeline.#", opts: eventbus.Options{Buffer: 1, Policy: eventbus.POLICY_BLOCK, BlockTimeout: time.Second}, delay: 5 * time.Millisecond},
+	}
+
+	mulock := sync.Mutex{}
+	received := map[string][]uint64{}
+	wg := sync.WaitGroup{}
+	subs := make([]*eventbus.Subscription, len(subscribers))
+	for i, s := range subscribers {
+		sub, err := bus.Subscribe(ctx, s.pattern, s.opts)
+		if err != nil {
+			return err
+		}
+		subs[i] = sub
+		name, delay := s.name, s.delay
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for e := range sub.C() {
+				time.Sleep(delay)
+				mulock.Lock()
+				received[name] = append(received[name], e.Seq)
+				mulock.Unlock()
+			}
+		}()
+	}
+
+	topics := []string{"worker.result", "pipeline.stage.add", "worker.metrics"}
+	for i := 0; i < events; i++ {
+		topic := topics[i%len(topics)]
+		n, err := bus.Publish(topic, i)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Published #%d on %s to %d subscribers\n", i+1, topic, n)
+	}
+
+	// let the subscribers read what is buffered, then unsubscribe them
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	for i, s := range subscribers {
+		fmt.Fprintf(w, "%s (%s, %s): received %v, dropped %d\n", s.name, s.pattern, s.opts.Policy, received[s.name], subs[i].Dropped())
+	}
+	m := bus.Metrics()
+	fmt.Fprintf(w, "Bus: %d published, %d delivered, %d dropped, %d subscribers left\n", m.Published, m.Delivered, m.Dropped, m.Subscribers)
+	return nil
+}
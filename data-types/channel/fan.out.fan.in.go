@@ -1,8 +1,14 @@
 package channel
 
 import (
+	"context"
+	"examples/data-structure/linklist"
+	"examples/pipeline"
+	"examples/registry"
+	"examples/semaphore"
 	"fmt"
-	"sync"
+	"io"
+	"time"
 )
 
 /*
@@ -17,83 +23,72 @@ The most intuitive solution will be to increase the number of workers where work
 This is where the Fan-out, Fan-in pattern comes into play.
 */
 
-func fanOutAdd(doneCh chan struct{}, inputCh chan int) []chan int {
-	numWorkers := 5
-	channels := make([]chan int, numWorkers)
+const MAX_FAN_OUT_WORKERS = 64
 
-	for i := 0; i < numWorkers; i++ {
-		// TODO: rather than spawning N worker goroutines all together, we can use Semaphore pattern
-		// to spawn in controlled way, so as not to choke the system/service/DB at the other end
-
-		addResultCh := add(doneCh, inputCh) // this function present in pipeline.pattern.go file
-		channels[i] = addResultCh
-	}
-
-	return channels
-}
+var ErrFanOutArgs = registry.InvalidArgs(fmt.Errorf("Workers should be 1 to %d, with a limit of at least 1!", MAX_FAN_OUT_WORKERS))
 
 /*
-1. The fanIn function takes in a slice of channels (The fanOut function produces them)
-2. For each channel, we spawn a separate Goroutine to fetch the data from the channel and feed it to the finalCh.
-3. At the end of the function, we spawn a separate Goroutine to wait for all Goroutines to finish and close the finalCh
-4. We then return the merged channel — finalCh back to the main function
-5. The chClosure acts as a closure to the ch in each iteration
+fanOutAdd spawns numWorkers add stages reading the same input, pipeline.FanOut then fans them in:
+1. For each worker, a separate Goroutine fetches the data from its channel and feeds it to the final channel (pipeline.Merge)
+2. Once all of them are done, the final channel is closed
+3. With ordered, the results are put back in the order of the input, else they come as they complete
+
+Rather than all the workers hitting the system/service/DB at the other end together, each one acquires
+a permit of the semaphore for its call, so at most the size of the semaphore calls run at a time.
 */
-func fanInAdd(doneCh chan struct{}, resultChs ...chan int) chan int {
-	finalCh := make(chan int)
-	var wg sync.WaitGroup
-
-	for _, ch := range resultChs {
-		wg.Add(1)
-		chClosure := ch
-
-		go func() {
-			defer wg.Done()
-
-			for data := range chClosure {
-				select {
-				case <-doneCh:
-					return
-				case finalCh <- data:
-				}
-			}
-		}()
+func fanOutAdd(p *pipeline.Pipeline, inputCh <-chan int, numWorkers int, ordered bool, sem *semaphore.Weighted) <-chan int {
+	var opts []pipeline.FanOutOption
+	if ordered {
+		opts = append(opts, pipeline.Ordered())
 	}
-
-	// This needs to be in another goroutiine because we need to return final channel first
-	// we cant keep on waiting for all add goroutines to finish as it will block finalCh<-data select case
-	go func() {
-		wg.Wait()
-		close(finalCh)
-	}()
-
-	return finalCh
+	return pipeline.FanOut(p, inputCh, numWorkers, func(ctx context.Context, data int) (int, error) {
+		if err := sem.Acquire(ctx, 1); err != nil {
+			return 0, err
+		}
+		defer sem.Release(1)
+		time.Sleep(10 * time.Millisecond) // the call to the DB
+		return addOne(ctx, data)          // addOne present in pipeline.pattern.go file
+	}, opts...)
 }
 
 /*
-1. We create a data stream inputCh using a generator
-2. We spawn ten workers for our add function using fanOut
-3. We merge all channels using the fanIn function
+1. We create a data stream inputCh using a Source stage
+2. We spawn the workers for our add function using fanOut
+3. The fanOut merges all the channels (fanIn)
 4. We then pass the addResultCh into the multiply stage for further processing
 With the Fan-out, Fan-in pattern, we can increase the number of workers for a single stage of our pipeline,
 thus increasing the throughput of our program.
 */
-func FanOutFanInPattern() {
-	input := []int{1, 2, 3, 4, 5, 6, 7, 8}
+func FanOutFanInPattern(ctx context.Context, w io.Writer, workers, limit int, ordered bool) (err error) {
+	if workers < 1 || workers > MAX_FAN_OUT_WORKERS || limit < 1 {
+		return fmt.Errorf("%w: %d workers, limit %d", ErrFanOutArgs, workers, limit)
+	}
+	input := linklist.InitList[int]()
+	for i := 1; i <= 8; i++ {
+		input.AddBack(i)
+	}
 
-	doneCh := make(chan struct{})
-	defer close(doneCh)
+	p := pipeline.New(ctx)
 
-	inputCh := generator1(doneCh, input) // this function present in pipeline.pattern.go file
+	// any collection can be the input, via its iterator
+	inputCh := pipeline.Source(p, input.Iter())
 
 	// As more goroutines are required to process add() task,
 	// we are using fanOut-fanIn pattern here
-	channels := fanOutAdd(doneCh, inputCh)
-	addResultCh := fanInAdd(doneCh, channels...)
+	// while bounding the calls of the workers to limit at a time
+	sem, err := semaphore.NewWeighted(int64(limit))
+	if err != nil {
+		return
+	}
+	addResultCh := fanOutAdd(p, inputCh, workers, ordered, sem)
 
-	resultCh := multiply(doneCh, addResultCh) // this function present in pipeline.pattern.go file
+	resultCh := multiply(p, addResultCh) // this function present in pipeline.pattern.go file
 
-	for res := range resultCh {
-		fmt.Println(res)
-	}
+	err = pipeline.ForEach(p, resultCh, func(res int) error {
+		_, err := fmt.Fprintln(w, res)
+		return err
+	})
+	m := sem.Metrics()
+	fmt.Fprintf(w, "%d workers, %d calls at a time: %d calls, %d waited for %v\n", workers, limit, m.Acquired, m.WaitCount, m.WaitDuration.Round(time.Millisecond))
+	return
 }
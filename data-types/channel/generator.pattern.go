@@ -2,7 +2,10 @@ package channel
 
 import (
 	"context"
+	"errors"
+	"examples/group"
 	"fmt"
+	"io"
 	"time"
 )
 
@@ -26,106 +29,103 @@ Again, the consumer closes the resultCh since it’s the sender of the channel.
 4. Note that the generator (the sender) is the one closing the inputCh
 */
 type Generator struct {
-	Input  []int
-	Result Response
+	Input []int
+	out   io.Writer
 }
 
-type Response struct {
-	Output interface{}
-	err    error
+const (
+	GENERATOR_DELAY   = 200 * time.Millisecond // after sending each input
+	GENERATOR_TIMEOUT = 700 * time.Millisecond // cancels the generator before all the inputs are sent
+)
+
+var ErrOddInput = errors.New("Error, input is Odd !!")
+
+type evenCheck struct {
+	Input  int
+	IsEven bool
 }
 
 /*
-sender will get some input and return a channel, over which input will be sent to consumer
+generate sends the inputs over inputChan, which it closes once done or cancelled
 */
-func (g *Generator) generate(ctx context.Context) (inputChan chan interface{}) {
-	inputChan = make(chan interface{})
-	/*
-		Important Point:
-		Input channel declared here will only be returned, if below code is executed separately in another goroutine
-		Else it will wait on sending data to inputChannel  (inputChan <- ip)
-	*/
-	go func(ctx context.Context) {
-		defer close(inputChan) // close input channel, when all input data sent
-
-		for ip := range g.Input {
-			select {
-			case inputChan <- ip:
-				time.Sleep(2 * time.Second) // wait for sometime after sending each input
-				fmt.Println("Generator goroutine: sent input - ", ip)
-			case <-ctx.Done():
-				fmt.Println("Generator goroutine: received cancel event - ", ctx.Err())
-				return
-			}
+func (g *Generator) generate(ctx context.Context, inputChan chan<- int) error {
+	defer close(inputChan) // close input channel, when all input data sent
 
+	for _, ip := range g.Input {
+		select {
+		case inputChan <- ip:
+			fmt.Fprintln(g.out, "Generator goroutine: sent input - ", ip)
+		case <-ctx.Done():
+			fmt.Fprintln(g.out, "Generator goroutine: received cancel event - ", ctx.Err())
+			return nil
 		}
-	}(ctx)
-
-	return
+		// wait for sometime after sending each input
+		select {
+		case <-time.After(GENERATOR_DELAY):
+		case <-ctx.Done():
+		}
+	}
+	return nil
 }
 
-func (g *Generator) consumer(ctx context.Context, inputChan, outputChan chan interface{}) {
+/*
+consumer checks every input, the error of the check travels with its input in the Result
+rather than stopping the consumer
+*/
+func (g *Generator) consumer(ctx context.Context, inputChan <-chan int, outputChan chan<- group.Result[evenCheck]) error {
 	// never forget to close channel, that too via sender
 	defer close(outputChan)
 
 	// anonynous function to check even number input
-	checkEven := func(val int) (isEven bool, err error) {
-		if val%2 == 0 {
-			isEven = true
-		} else {
-			err = fmt.Errorf("Error, input is Odd !!")
+	checkEven := func(val int) (res group.Result[evenCheck]) {
+		res.Value = evenCheck{Input: val, IsEven: val%2 == 0}
+		if !res.Value.IsEven {
+			res.Err = fmt.Errorf("%w: %d", ErrOddInput, val)
 		}
 		return
 	}
 
 	// iterate over input channel
 	for ip := range inputChan {
-		isEven, err := checkEven(ip.(int))
-		op := map[string]interface{}{
-			"input":  ip.(int),
-			"output": isEven,
-		}
+		op := checkEven(ip)
 		select {
-		case outputChan <- Response{Output: op, err: err}:
-			fmt.Println("Consumer goroutine: sends output - ", op)
+		case outputChan <- op:
+			fmt.Fprintln(g.out, "Consumer goroutine: sends output - ", op.Value)
 		case <-ctx.Done():
-			fmt.Println("Consumer goroutine: received cancel event - ", ctx.Err())
-			return
+			fmt.Fprintln(g.out, "Consumer goroutine: received cancel event - ", ctx.Err())
+			return nil
 		}
 	}
-	return
+	return nil
 }
 
-func GeneratorPattern() {
+/*
+GeneratorPattern runs the generator and the consumer in a group, it returns an error only when
+one of them fails (ie panics), the odd inputs are reported with their results.
+*/
+func GeneratorPattern(ctx context.Context, w io.Writer) error {
 	// static inout data
-	g := Generator{Input: []int{1, 2, 3, 4, 5}}
-	outputChan := make(chan interface{})
-
-	// initialise input channel, on which sender goroutine will push input data
-	// WithTimeout() -> will trigger cancel event after speppcific time(6 seconds in this case)
-	ctx, cancel := context.WithTimeout(context.Background(), 6*time.Second)
+	g := Generator{Input: []int{1, 2, 3, 4, 5}, out: w}
 
-	//ctx, cancel := context.WithCancel(context.Background())
-	// to trigger cancel event, before both goroutines send all input and output
-	// mock some error and trigger manually
+	// WithTimeout() -> will trigger cancel event after specific time, before all the inputs are sent
+	ctx, cancel := context.WithTimeout(ctx, GENERATOR_TIMEOUT)
 	defer cancel()
 
-	inputChan := g.generate(ctx)
-
-	// initialise output channel, on which consumer goroutine will  push output after processing
-	go g.consumer(ctx, inputChan, outputChan)
+	// inputChan: the generator pushes the input data, outputChan: the consumer pushes the output after processing
+	inputChan := make(chan int)
+	outputChan := make(chan group.Result[evenCheck])
+	grp, ctx := group.New(ctx)
+	grp.Go(func(ctx context.Context) error { return g.generate(ctx, inputChan) })
+	grp.Go(func(ctx context.Context) error { return g.consumer(ctx, inputChan, outputChan) })
 
 	// listen to output channel
 	// handle output & error accordingly
 	for op := range outputChan {
-		if opVal, ok := op.(Response); ok == true {
-			op := opVal.Output.(map[string]interface{})
-			if opVal.err != nil {
-				fmt.Printf("\nFor input - %v, Output is : %v", op["input"], opVal.err)
-			} else {
-				fmt.Printf("\nFor input - %v, Output is : %v", op["input"], op["output"])
-			}
+		if v, err := op.Get(); err != nil {
+			fmt.Fprintf(w, "For input - %v, Output is : %v\n", v.Input, err)
+		} else {
+			fmt.Fprintf(w, "For input - %v, Output is : %v\n", v.Input, v.IsEven)
 		}
-
 	}
+	return grp.Wait()
 }
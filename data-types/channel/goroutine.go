@@ -2,9 +2,10 @@ package channel
 
 import (
 	"fmt"
+	"io"
 	"runtime"
 )
 
-func BasicsGoroutine() {
-	fmt.Println("\nruntime.NumCPU(): ", runtime.NumCPU())
+func BasicsGoroutine(w io.Writer) {
+	fmt.Fprintln(w, "\nruntime.NumCPU(): ", runtime.NumCPU())
 }
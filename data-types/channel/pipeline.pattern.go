@@ -1,6 +1,12 @@
 package channel
 
-import "fmt"
+import (
+	"context"
+	"examples/iterator"
+	"examples/pipeline"
+	"fmt"
+	"io"
+)
 
 /*
 Reference: https://betterprogramming.pub/writing-better-code-with-go-concurrency-patterns-9bc5f9f73519
@@ -19,8 +25,8 @@ Leveraging the Goroutine and channel, stages can run and process data concurrent
 */
 
 /*
-1. We create a data stream using the generator function
-2. We create a doneCh and pass to all Goroutines for explicit cancellation
+1. We create a data stream using the Source stage
+2. We create a pipeline, its context is passed to all Goroutines for explicit cancellation
 3. We then chain the add and multiply stage together
 4. Whenever the add function has done processing an input. It will immediately pass the result to the multiply stage for further processing
 
@@ -40,74 +46,36 @@ Fan-out, in a nutshell, refers to spawning more Goroutines in a specific stage t
 in other words, DEMULTIPLEXING.
 */
 
-func PipelinePattern() {
+func PipelinePattern(ctx context.Context, w io.Writer) error {
 	input := []int{1, 2, 3, 4, 5, 6, 7, 8}
 
-	doneCh := make(chan struct{})
-	defer close(doneCh)
+	// the pipeline cancels all the stages, once the context is done or a stage fails
+	p := pipeline.New(ctx)
 
-	inputCh := generator1(doneCh, input)
+	inputCh := pipeline.Source(p, iterator.FromSlice(input))
 
-	resultCh := multiply(doneCh, add(doneCh, inputCh))
+	resultCh := multiply(p, add(p, inputCh))
 
-	for res := range resultCh {
-		fmt.Println(res)
-	}
+	return pipeline.ForEach(p, resultCh, func(res int) error {
+		_, err := fmt.Fprintln(w, res)
+		return err
+	})
 }
 
-func generator1(doneCh chan struct{}, input []int) chan int {
-	inputCh := make(chan int)
-
-	go func() {
-		defer close(inputCh)
-
-		for _, data := range input {
-			select {
-			case <-doneCh:
-				return
-			case inputCh <- data:
-			}
-		}
-	}()
-
-	return inputCh
+/*
+pipeline.Source feeds the pipeline from an iterator, so any collection (slice, map, linklist, stack, tree) can be
+the source of the pipeline, ie pipeline.Source(p, tree.InOrder())
+*/
+func add(p *pipeline.Pipeline, inputCh <-chan int) <-chan int {
+	return pipeline.Map(p, inputCh, addOne)
 }
-func add(doneCh chan struct{}, inputCh chan int) chan int {
-	addRes := make(chan int)
-
-	go func() {
-		defer close(addRes)
 
-		for data := range inputCh {
-			result := data + 1
-
-			select {
-			case <-doneCh:
-				return
-			case addRes <- result:
-			}
-		}
-	}()
-
-	return addRes
+func multiply(p *pipeline.Pipeline, inputCh <-chan int) <-chan int {
+	return pipeline.Map(p, inputCh, func(ctx context.Context, data int) (int, error) {
+		return data * 2, nil
+	})
 }
 
-func multiply(doneCh chan struct{}, inputCh chan int) chan int {
-	multiplyRes := make(chan int)
-
-	go func() {
-		defer close(multiplyRes)
-
-		for data := range inputCh {
-			result := data * 2
-
-			select {
-			case <-doneCh:
-				return
-			case multiplyRes <- result:
-			}
-		}
-	}()
-
-	return multiplyRes
+func addOne(ctx context.Context, data int) (int, error) {
+	return data + 1, nil
 }
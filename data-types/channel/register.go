@@ -0,0 +1,91 @@
+package channel
+
+import (
+	"context"
+	"examples/registry"
+	"io"
+)
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "channel/worker",
+		Title:       "Channel Worker",
+		Category:    registry.CATEGORY_CHANNEL,
+		Description: "Find prime numbers in a range on a generic worker pool with ordered results, metrics and a context timeout, fed and drained in an error group",
+		Source:      "data-types/channel/example.worker.go",
+		Params: []registry.Param{
+			{Name: "from", Type: registry.PARAM_INT, Default: "1", Usage: "start of the range"},
+			{Name: "to", Type: registry.PARAM_INT, Default: "50", Usage: "end of the range, included"},
+			{Name: "workers", Type: registry.PARAM_INT, Default: "4", Usage: "workers checking the numbers concurrently"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return ExecuteWorker(ctx, w, args.Int("from"), args.Int("to"), args.Int("workers"))
+		},
+	})
+	registry.Register(registry.Example{
+		Name:        "channel/basics",
+		Title:       "Channel Basics",
+		Category:    registry.CATEGORY_CHANNEL,
+		Description: "Fan-out/fan-in pipeline and a semaphore bounding the concurrent goroutines",
+		Source:      "data-types/channel/fan.out.fan.in.go",
+		Params: []registry.Param{
+			{Name: "workers", Type: registry.PARAM_INT, Default: "5", Usage: "workers of the fanned out add stage, at most 64"},
+			{Name: "limit", Type: registry.PARAM_INT, Default: "2", Usage: "calls of the workers running at a time, via a semaphore, at least 1"},
+			{Name: "ordered", Type: registry.PARAM_INT, Default: "0", Usage: "1 to keep the results in the order of the input"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			if err := FanOutFanInPattern(ctx, w, args.Int("workers"), args.Int("limit"), args.Int("ordered") == 1); err != nil {
+				return err
+			}
+			SemaphoreExample(w)
+			return nil
+		},
+	})
+	registry.Register(registry.Example{
+		Name:        "channel/pipeline",
+		Title:       "Channel Pipeline",
+		Category:    registry.CATEGORY_CHANNEL,
+		Description: "Add and multiply stages composed with the generic pipeline package",
+		Source:      "data-types/channel/pipeline.pattern.go",
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return PipelinePattern(ctx, w)
+		},
+	})
+	registry.Register(registry.Example{
+		Name:        "channel/generator",
+		Title:       "Channel Generator",
+		Category:    registry.CATEGORY_CHANNEL,
+		Description: "Generator and consumer goroutines in an error group, sending typed results with their errors, cancelled by a timeout",
+		Source:      "data-types/channel/generator.pattern.go",
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return GeneratorPattern(ctx, w)
+		},
+	})
+	registry.Register(registry.Example{
+		Name:        "channel/broadcast",
+		Title:       "Channel Broadcast",
+		Category:    registry.CATEGORY_CHANNEL,
+		Description: "Event bus broadcasting to topic subscribers with wildcards and slow consumer policies (drop oldest, drop newest, block)",
+		Source:      "data-types/channel/broadcast.go",
+		Params: []registry.Param{
+			{Name: "events", Type: registry.PARAM_INT, Default: "12", Usage: "events to publish"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return ExecuteBroadcast(ctx, w, args.Int("events"))
+		},
+	})
+	registry.Register(registry.Example{
+		Name:        "channel/resilience",
+		Title:       "Rate Limiter and Circuit Breaker",
+		Category:    registry.CATEGORY_CHANNEL,
+		Description: "Protect a flaky remote cache with a token bucket rate limiter and a circuit breaker",
+		Source:      "data-types/channel/resilience.go",
+		Params: []registry.Param{
+			{Name: "requests", Type: registry.PARAM_INT, Default: "20", Usage: "requests to the remote cache, at most 1000"},
+			{Name: "rate", Type: registry.PARAM_INT, Default: "50", Usage: "requests per second allowed by the rate limiter, at most 1000"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return ExecuteResilience(ctx, w, args.Int("requests"), args.Int("rate"))
+		},
+	})
+}
@@ -0,0 +1,84 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"examples/registry"
+	"examples/resilience"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+/*
+A semaphore bounds the concurrent requests to the remote cache, though it does not protect it from
+too many requests over time, nor stops hammering it while it is down.
+
+1. A rate limiter (token bucket or sliding window) bounds the requests per second
+2. A circuit breaker fails fast once the cache keeps failing, giving it time to recover:
+   closed -> open after N consecutive failures -> half-open after a timeout, letting a trial request
+   through -> closed once it succeeds, else open again
+*/
+
+const (
+	MAX_RESILIENCE_REQUESTS = 1000
+	MAX_RESILIENCE_RATE     = 1000 // requests per second
+)
+
+var (
+	errCacheDown      = errors.New("Remote cache is down!")
+	ErrResilienceArgs = registry.InvalidArgs(fmt.Errorf("Requests should be 1 to %d, at a rate of 1 to %d per second!", MAX_RESILIENCE_REQUESTS, MAX_RESILIENCE_RATE))
+)
+
+// remoteCache is down for the calls from downFrom till downTo
+type remoteCache struct {
+	calls            int64
+	downFrom, downTo int64
+}
+
+func (rc *remoteCache) Get(ctx context.Context) error {
+	if call := atomic.AddInt64(&rc.calls, 1); call >= rc.downFrom && call <= rc.downTo {
+		return errCacheDown
+	}
+	return nil
+}
+
+func ExecuteResilience(ctx context.Context, w io.Writer, requests, rate int) (err error) {
+	if requests < 1 || requests > MAX_RESILIENCE_REQUESTS || rate < 1 || rate > MAX_RESILIENCE_RATE {
+		return fmt.Errorf("%w: %d requests, rate %d", ErrResilienceArgs, requests, rate)
+	}
+	var changes []string
+	cache := &remoteCache{downFrom: 4, downTo: 7}
+	limiter := resilience.NewTokenBucket(float64(rate), 1, nil)
+	breaker := resilience.NewBreaker(resilience.BreakerConfig{
+		FailureThreshold: 3,
+		OpenTimeout:      100 * time.Millisecond,
+		OnStateChange: func(from, to resilience.State) {
+			changes = append(changes, fmt.Sprintf("  breaker: %s -> %s", from, to))
+		},
+	})
+
+	start := time.Now()
+	for i := 1; i <= requests; i++ {
+		if err = limiter.Wait(ctx); err != nil {
+			return
+		}
+		at := time.Since(start).Round(10 * time.Millisecond)
+		switch err := breaker.Execute(ctx, cache.Get); {
+		case err == nil:
+			fmt.Fprintf(w, "%v request %d: ok\n", at, i)
+		case errors.Is(err, resilience.ErrCircuitOpen):
+			fmt.Fprintf(w, "%v request %d: rejected, %v\n", at, i, err)
+		default:
+			fmt.Fprintf(w, "%v request %d: failed, %v\n", at, i, err)
+		}
+		for _, change := range changes {
+			fmt.Fprintln(w, change)
+		}
+		changes = changes[:0]
+	}
+	s := breaker.Stats()
+	fmt.Fprintf(w, "Cache called %d times for %d requests, %d rejected by the breaker\n", cache.calls, requests, s.Rejected)
+	return
+}
@@ -2,6 +2,7 @@ package channel
 
 import (
 	"fmt"
+	"io"
 	"sync"
 	"time"
 )
@@ -18,6 +19,9 @@ This is where the Semaphore comes in handy.
 Unlike mutex lock, which allows a single thread to access a resource at a time, Semaphore allows N threads to access a resource at a time.
 
 Using the concept of a buffered channel, we can design a semaphore easily.
+
+It can not be cancelled, takes one permit at a time and Release without Acquire blocks forever.
+See semaphore.Weighted for a weighted, FIFO and context aware one.
 */
 
 // Mutex can be used only for 1 Thread
@@ -72,7 +76,7 @@ Sample Response:
 
 13:50:27 Running worker 10
 */
-func SemaphoreExample() {
+func SemaphoreExample(w io.Writer) {
 	maxParallelReq := 3
 	s := NewSemaphore(maxParallelReq)
 
@@ -90,7 +94,7 @@ func SemaphoreExample() {
 				time.Now().Format("15:04:05"),
 				reqID,
 			)
-			fmt.Println(msg)
+			fmt.Fprintln(w, msg)
 			time.Sleep(time.Second * 1)
 
 		}(req)
@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"fmt"
+	"io"
 )
 
 /*
@@ -69,7 +70,7 @@ The underlying variable of an interface can be accessed in two ways
     }
 */
 type IPerson interface {
-	PrintPerson()
+	PrintPerson(w io.Writer)
 	SetPerson(name, gender string)
 }
 type SportsPerson struct {
@@ -81,12 +82,12 @@ func (sp *SportsPerson) SetPerson(name, gender string) {
 	sp.Name = name
 	sp.Gender = gender
 }
-func (sp *SportsPerson) PrintPerson() {
-	fmt.Printf("Name: %s | Gender: %s", sp.Name, sp.Gender)
+func (sp *SportsPerson) PrintPerson(w io.Writer) {
+	fmt.Fprintf(w, "Name: %s | Gender: %s", sp.Name, sp.Gender)
 }
 
 type ICricketer interface {
-	PrintCricketer(p IPerson)
+	PrintCricketer(w io.Writer, p IPerson)
 	SetCricketer(name, gender, team string)
 }
 type Cricketer struct {
@@ -94,16 +95,16 @@ type Cricketer struct {
 	Team    string
 }
 
-func (c Cricketer) PrintCricketer(p IPerson) {
-	p.PrintPerson()
+func (c Cricketer) PrintCricketer(w io.Writer, p IPerson) {
+	p.PrintPerson(w)
 	//c.IPerson.PrintPerson()
-	fmt.Printf("Team: %s\n", c.Team)
+	fmt.Fprintf(w, "Team: %s\n", c.Team)
 }
 
-func (c Cricketer) PrintCricketer1() {
+func (c Cricketer) PrintCricketer1(w io.Writer) {
 	//p.PrintPerson()
-	c.IPerson.PrintPerson()
-	fmt.Printf("Team: %s\n", c.Team)
+	c.IPerson.PrintPerson(w)
+	fmt.Fprintf(w, "Team: %s\n", c.Team)
 }
 
 func (c *Cricketer) SetCricketer(name, gender, team string) {
@@ -111,7 +112,7 @@ func (c *Cricketer) SetCricketer(name, gender, team string) {
 	c.Team = team
 }
 
-func ExampleInterfaceInStruct() {
+func ExampleInterfaceInStruct(w io.Writer) {
 
 	/*
 		create an object of SportsPerson type
@@ -141,8 +142,8 @@ func ExampleInterfaceInStruct() {
 	*/
 
 	ip = &sp
-	fmt.Printf("Underlying Type: %T\n", ip)
-	fmt.Printf("Underlying Value: %v\n", ip)
+	fmt.Fprintf(w, "Underlying Type: %T\n", ip)
+	fmt.Fprintf(w, "Underlying Value: %v\n", ip)
 	/*
 		Memory Map : ip =
 		[
@@ -173,8 +174,8 @@ func ExampleInterfaceInStruct() {
 
 
 	*/
-	fmt.Println("cr.PrintCricketer() call using object of type -> Cricketer struct ")
-	cr.PrintCricketer(ip)
+	fmt.Fprintln(w, "cr.PrintCricketer() call using object of type -> Cricketer struct ")
+	cr.PrintCricketer(w, ip)
 
 	var crI ICricketer
 	/*
@@ -185,8 +186,8 @@ func ExampleInterfaceInStruct() {
 		]
 	*/
 	crI = &cr
-	fmt.Printf("Underlying Type: %T\n", crI)
-	fmt.Printf("Underlying Value: %v\n", crI)
+	fmt.Fprintf(w, "Underlying Type: %T\n", crI)
+	fmt.Fprintf(w, "Underlying Value: %v\n", crI)
 	// crI = cr
 	//  - Compile Error :
 	//  cr (Cricketer) does not implement ICricketer
@@ -200,17 +201,17 @@ func ExampleInterfaceInStruct() {
 			Pointer -> cr.PrintCricketer()
 		]
 	*/
-	fmt.Println("crI.PrintCricketer() call using variable of type -> ICricketer interface ")
-	crI.PrintCricketer(ip)
+	fmt.Fprintln(w, "crI.PrintCricketer() call using variable of type -> ICricketer interface ")
+	crI.PrintCricketer(w, ip)
 
 	cr1 := new(Cricketer)
 	// cr1 is Pointer type struct (Cricketer) object, AND is able to access BOTH
 	// Value & Pointer Receiver Methods of struct (Cricketer)
 
 	cr1.IPerson = ip
-	cr1.PrintCricketer(ip)
+	cr1.PrintCricketer(w, ip)
 	cr1.SetCricketer("Virat Kohli", "Male", "RCB")
-	cr1.PrintCricketer(ip)
+	cr1.PrintCricketer(w, ip)
 
-	cr1.PrintCricketer1()
+	cr1.PrintCricketer1(w)
 }
@@ -0,0 +1,22 @@
+package interfaces
+
+import "examples/registry"
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "interface",
+		Title:       "Interface In Struct",
+		Category:    registry.CATEGORY_DATA_TYPES,
+		Description: "Interface embedded in a struct, value vs pointer receivers",
+		Source:      "data-types/interfaces/interface_in_struct.go",
+		Run:         registry.Simple(ExampleInterfaceInStruct),
+	})
+	registry.Register(registry.Example{
+		Name:        "struct/basics",
+		Title:       "Struct Basics",
+		Category:    registry.CATEGORY_DATA_TYPES,
+		Description: "Struct embedding and access of exported/unexported fields",
+		Source:      "data-types/interfaces/struct_in_struct.go",
+		Run:         registry.Simple(ExampleStructInStruct),
+	})
+}
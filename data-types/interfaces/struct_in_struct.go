@@ -1,6 +1,9 @@
 package interfaces
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 type Person struct {
 	Name   string
@@ -8,8 +11,8 @@ type Person struct {
 	Gender string
 }
 
-func (p *Person) PrintDetails() {
-	fmt.Printf("%s : %v/%v", p.Name, p.Age, p.Gender)
+func (p *Person) PrintDetails(w io.Writer) {
+	fmt.Fprintf(w, "%s : %v/%v", p.Name, p.Age, p.Gender)
 }
 
 /*
@@ -36,3 +39,28 @@ type PersonalSecretory struct {
 type Manager struct {
 	Person
 }
+
+func ExampleStructInStruct(w io.Writer) {
+	//p := &Person{Name: "Harry", Age: 41, Gender: "Male"}
+	/*
+		Instantiace struct with new function with default attribte values
+	*/
+	p := new(Person)
+	p.PrintDetails(w)
+	fmt.Fprintln(w)
+
+	e := &Employee{P: Person{Name: "Harry", Age: 41, Gender: "Male"}}
+
+	/*
+		Cannot access Person attributes as it is defined as private with attribute [p Person] instead of [P Person]
+		Outside this package, only PersonalSecretory{} can be created
+	*/
+	ps := &PersonalSecretory{p: Person{Name: "Harry", Age: 41, Gender: "Male"}}
+
+	m := &Manager{Person: Person{Name: "Harry", Age: 41, Gender: "Male"}}
+
+	fmt.Fprintf(w, "Person: %+v\n", p)
+	fmt.Fprintf(w, "Employee: %+v\n", e)
+	fmt.Fprintf(w, "Manager: %+v\n", m)
+	fmt.Fprintf(w, "PersonalSecretory: %+v\n", ps)
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"io"
 )
 
 /*
@@ -52,44 +53,44 @@ Value can be of any type in a map.
 	or when we want to assign the result of a function.
 */
 
-func MapCreation() {
-	fmt.Println("Map Creation !!")
+func MapCreation(w io.Writer) {
+	fmt.Fprintln(w, "Map Creation !!")
 
-	fmt.Println("1. Using Map literal")
-	fmt.Println("m := map[int]string{1: \"a\", 2: \"b\"}")
+	fmt.Fprintln(w, "1. Using Map literal")
+	fmt.Fprintln(w, "m := map[int]string{1: \"a\", 2: \"b\"}")
 	m := map[int]string{1: "a", 2: "b"}
-	fmt.Println(m)
+	fmt.Fprintln(w, m)
 
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Println("2. Using make() function")
-	fmt.Println("m1 := make(map[string]int)")
+	fmt.Fprintln(w, "2. Using make() function")
+	fmt.Fprintln(w, "m1 := make(map[string]int)")
 	m1 := make(map[string]int)
 	m1["a"] = 1
 	m1["b"] = 2
-	fmt.Println(m1)
-	fmt.Println()
+	fmt.Fprintln(w, m1)
+	fmt.Fprintln(w)
 
-	fmt.Println("3. Using var")
-	fmt.Println("var m2 map[string]int")
-	fmt.Println("m2 = m1")
+	fmt.Fprintln(w, "3. Using var")
+	fmt.Fprintln(w, "var m2 map[string]int")
+	fmt.Fprintln(w, "m2 = m1")
 	var m2 map[string]int
 	m2 = m1
-	fmt.Println(m2)
+	fmt.Fprintln(w, m2)
 
 }
 
-func MapIterate() {
-	fmt.Println("Map Iteration !!")
+func MapIterate(w io.Writer) {
+	fmt.Fprintln(w, "Map Iteration !!")
 
 	m := map[int]string{
 		1: "a", 2: "b", 3: "c",
 	}
 
 	for i, v := range m {
-		fmt.Printf("%v:%v ,", i, v)
+		fmt.Fprintf(w, "%v:%v ,", i, v)
 
 	}
 
-	fmt.Println()
+	fmt.Fprintln(w)
 }
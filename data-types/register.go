@@ -0,0 +1,33 @@
+package types
+
+import (
+	"examples/registry"
+	"io"
+)
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "array",
+		Title:       "Array",
+		Category:    registry.CATEGORY_DATA_TYPES,
+		Description: "Array declaration, iteration and multi dimensional arrays",
+		Source:      "data-types/array.go",
+		Run:         registry.Simple(func(w io.Writer) { ArrayDeclaration(w); ArrayIterate(w); ArrayMultiDimention(w) }),
+	})
+	registry.Register(registry.Example{
+		Name:        "slice",
+		Title:       "Slice",
+		Category:    registry.CATEGORY_DATA_TYPES,
+		Description: "Slice creation, append and iteration",
+		Source:      "data-types/slice.go",
+		Run:         registry.Simple(func(w io.Writer) { SliceCreation(w); SliceAppend(w); SliceIterate(w) }),
+	})
+	registry.Register(registry.Example{
+		Name:        "map",
+		Title:       "Map",
+		Category:    registry.CATEGORY_DATA_TYPES,
+		Description: "Map creation and iteration",
+		Source:      "data-types/map.go",
+		Run:         registry.Simple(func(w io.Writer) { MapCreation(w); MapIterate(w) }),
+	})
+}
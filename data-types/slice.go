@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"io"
 )
 
 /*
@@ -124,87 +125,87 @@ Basically, with the above declaration, we create a slice of 2*3 dimensions which
 The same idea can be extended to two-dimension, three-dimension, and so on.
 */
 
-func SliceCreation() {
-	fmt.Println("Slice Creation!!")
+func SliceCreation(w io.Writer) {
+	fmt.Fprintln(w, "Slice Creation!!")
 
-	fmt.Println()
-	fmt.Println("1. Direct Initialisation")
-	fmt.Println("s := []int{1, 2, 3, 4, 5}")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "1. Direct Initialisation")
+	fmt.Fprintln(w, "s := []int{1, 2, 3, 4, 5}")
 	s := []int{1, 2, 3, 4, 5}
-	fmt.Printf("%v len: %v  capacity: %v", s, len(s), cap(s))
-	fmt.Println()
+	fmt.Fprintf(w, "%v len: %v  capacity: %v", s, len(s), cap(s))
+	fmt.Fprintln(w)
 
-	fmt.Println()
-	fmt.Println("2. Using make() function")
-	fmt.Println("s1 := make([]int, 3)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "2. Using make() function")
+	fmt.Fprintln(w, "s1 := make([]int, 3)")
 	s1 := make([]int, 3)
-	fmt.Printf("%v len: %v  capacity: %v", s1, len(s1), cap(s1))
-	fmt.Println()
+	fmt.Fprintf(w, "%v len: %v  capacity: %v", s1, len(s1), cap(s1))
+	fmt.Fprintln(w)
 
-	fmt.Println()
-	fmt.Println("3. Using new() function")
-	fmt.Println("s1 := new([]int)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "3. Using new() function")
+	fmt.Fprintln(w, "s1 := new([]int)")
 	s2 := new([]int)
-	fmt.Printf("%v len: %v  capacity: %v", *s2, len(*s2), cap(*s2)) // dereference to use the slice
-	fmt.Println()
+	fmt.Fprintf(w, "%v len: %v  capacity: %v", *s2, len(*s2), cap(*s2)) // dereference to use the slice
+	fmt.Fprintln(w)
 
-	fmt.Println()
-	fmt.Println("4. Using re-slicing (Array or Slice)")
-	fmt.Println("s3 := s[:]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "4. Using re-slicing (Array or Slice)")
+	fmt.Fprintln(w, "s3 := s[:]")
 	s3 := s[:]
-	fmt.Printf("%v len: %v  capacity: %v", s3, len(s3), cap(s3))
-	fmt.Println()
+	fmt.Fprintf(w, "%v len: %v  capacity: %v", s3, len(s3), cap(s3))
+	fmt.Fprintln(w)
 
 }
 
-func SliceAppend() {
-	fmt.Println()
-	fmt.Println("Slice Append!!")
+func SliceAppend(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Slice Append!!")
 
-	fmt.Println()
-	fmt.Println("Original Slice : s := []int{1, 2, 3}")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Original Slice : s := []int{1, 2, 3}")
 	s := []int{1, 2, 3}
-	fmt.Printf("%v len: %v  capacity: %v", s, len(s), cap(s))
-	fmt.Println()
+	fmt.Fprintf(w, "%v len: %v  capacity: %v", s, len(s), cap(s))
+	fmt.Fprintln(w)
 
-	fmt.Println("1. Append single element: s = append(s, 4) ")
+	fmt.Fprintln(w, "1. Append single element: s = append(s, 4) ")
 	s = append(s, 4) /// append single element
-	fmt.Printf("%v len: %v  capacity: %v", s, len(s), cap(s))
-	fmt.Println()
+	fmt.Fprintf(w, "%v len: %v  capacity: %v", s, len(s), cap(s))
+	fmt.Fprintln(w)
 
-	fmt.Println("2. Append multiple element: s = append(s, 5, 6, 7) ")
+	fmt.Fprintln(w, "2. Append multiple element: s = append(s, 5, 6, 7) ")
 	s = append(s, 5, 6, 7) // append multiple elements
-	fmt.Printf("%v len: %v  capacity: %v", s, len(s), cap(s))
-	fmt.Println()
+	fmt.Fprintf(w, "%v len: %v  capacity: %v", s, len(s), cap(s))
+	fmt.Fprintln(w)
 
-	fmt.Println()
-	fmt.Println("3. Append one slice to another : s = append(s, s1...) ")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "3. Append one slice to another : s = append(s, s1...) ")
 	s1 := []int{8, 9}
 	s2 := []int{10}
 	s1 = append(s1, s2...) // append one slice to another
 	s = append(s, s1...)   // append one slice to another
-	fmt.Printf("%v len: %v  capacity: %v", s, len(s), cap(s))
-	fmt.Println()
+	fmt.Fprintf(w, "%v len: %v  capacity: %v", s, len(s), cap(s))
+	fmt.Fprintln(w)
 
 	// copy slice to another
 
 }
 
-func SliceIterate() {
-	fmt.Println()
-	fmt.Println("Slice Iterate!!")
+func SliceIterate(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Slice Iterate!!")
 
-	fmt.Println()
+	fmt.Fprintln(w)
 	s := []int{1, 2, 3, 4, 5}
-	fmt.Println("1. Using for loop")
+	fmt.Fprintln(w, "1. Using for loop")
 	for i := 0; i < len(s); i++ {
-		fmt.Printf("%v ", s[i])
+		fmt.Fprintf(w, "%v ", s[i])
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Println("2. Using for-range loop")
+	fmt.Fprintln(w, "2. Using for-range loop")
 	for i, v := range s {
-		fmt.Printf("s[%v]=%v ", i, v)
+		fmt.Fprintf(w, "s[%v]=%v ", i, v)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 }
@@ -1,6 +1,9 @@
 package strings
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /*
 We use a hash and three variables
@@ -19,15 +22,14 @@ We reset the start position and currentSubstringLength to include the current ch
 Before resetting we check if currentSubstringLength is greater than longestSubstringLength.
 If yes then we set longestSubstringLength to currentSubstringLength.
 */
-func LongestSubstring() {
-	str := "abbabcda"
-	fmt.Println("Input string: ", str)
+func LongestSubstring(w io.Writer, str string) {
+	fmt.Fprintln(w, "Input string: ", str)
 
 	charLastIndex := make(map[string]int)
 	var currStrLen, LongestStrLen int
 	strStartIndex := 0
 	for index := range str {
-		fmt.Printf("\nindex: %v, char: %v", index, str[index])
+		fmt.Fprintf(w, "\nindex: %v, char: %v", index, str[index])
 
 		lastIndex, ok := charLastIndex[string(str[index])]
 		if !ok || lastIndex < index-currStrLen {
@@ -41,14 +43,14 @@ func LongestSubstring() {
 		}
 		charLastIndex[string(str[index])] = index
 
-		fmt.Println(strStartIndex)
+		fmt.Fprintln(w, strStartIndex)
 	}
 	if currStrLen > LongestStrLen {
 		LongestStrLen = currStrLen
 	}
 
-	fmt.Println(charLastIndex)
-	fmt.Println("Longest SubString Len: ", LongestStrLen)
+	fmt.Fprintln(w, charLastIndex)
+	fmt.Fprintln(w, "Longest SubString Len: ", LongestStrLen)
 	var substr string
 	for char := range charLastIndex {
 		substr = fmt.Sprintf("%s%s", substr, char)
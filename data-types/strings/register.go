@@ -0,0 +1,24 @@
+package strings
+
+import (
+	"context"
+	"examples/registry"
+	"io"
+)
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "string",
+		Title:       "Longest Substring",
+		Category:    registry.CATEGORY_DATA_TYPES,
+		Description: "Longest substring without repeating characters",
+		Source:      "data-types/strings/longest.substring.go",
+		Params: []registry.Param{
+			{Name: "input", Type: registry.PARAM_STRING, Default: "abbabcda", Usage: "string to search"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			LongestSubstring(w, args.String("input"))
+			return nil
+		},
+	})
+}
@@ -1,6 +1,9 @@
 package structs
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /*
 #### Reference Links: ####
@@ -71,8 +74,8 @@ type Person struct {
 	Gender string
 }
 
-func (p *Person) PrintDetails() {
-	fmt.Printf("%s : %v/%v", p.Name, p.Age, p.Gender)
+func (p *Person) PrintDetails(w io.Writer) {
+	fmt.Fprintf(w, "%s : %v/%v", p.Name, p.Age, p.Gender)
 }
 
 /*
@@ -100,13 +103,13 @@ type Manager struct {
 	Person
 }
 
-func ExampleBasics() {
+func ExampleBasics(w io.Writer) {
 	m := Manager{Person: Person{"Manager", 40, "Male"}}
-	m.PrintDetails()
+	m.PrintDetails(w)
 
 	e := Employee{P: Person{"Employee", 30, "Male"}}
-	e.P.PrintDetails()
+	e.P.PrintDetails(w)
 
 	s := PersonalSecretory{p: Person{"Secretory", 20, "Female"}}
-	s.p.PrintDetails()
+	s.p.PrintDetails(w)
 }
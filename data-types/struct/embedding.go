@@ -2,6 +2,7 @@ package structs
 
 import (
 	"fmt"
+	"io"
 	"sync"
 	"time"
 )
@@ -65,7 +66,7 @@ func (c Container) incValueReceiverWithPointerLock(key string) {
 	c.counter[key]++
 }
 
-func EmbeddingExample() {
+func EmbeddingExample(w io.Writer) {
 	c := Container{
 		counter: map[string]int{
 			"a": 0, "b": 0,
@@ -86,7 +87,7 @@ func EmbeddingExample() {
 	doIncrementNoLock("a") // This will work, but wrong counter value
 	//go doIncrementNoLock("a") // This will give error -> fatal error: concurrent map writes
 
-	fmt.Println(c.counter)
+	fmt.Fprintln(w, c.counter)
 
 	doIncrementWithLock := func(key string) {
 		for i := 0; i < 100000; i++ {
@@ -97,7 +98,7 @@ func EmbeddingExample() {
 
 	doIncrementWithLock("b") // This will work, but wrong counter value
 	//go doIncrementWithLock("b")  // This will give error -> fatal error: concurrent map writes
-	fmt.Println(c.counter)
+	fmt.Fprintln(w, c.counter)
 
 	doIncrementWithLockAndPointerReceiver := func(key string) {
 		for i := 0; i < 100000; i++ {
@@ -108,7 +109,7 @@ func EmbeddingExample() {
 
 	go doIncrementWithLockAndPointerReceiver("b") // Only this will work PROPERLY with concurrent goroutines. Why?????
 	time.Sleep(time.Second * 5)
-	fmt.Println(c.counter)
+	fmt.Fprintln(w, c.counter)
 	/*
 		doIncrementNoLock() : With no lock, goroutines will not work at all.
 		When we concurrently try to access map, it will return error as map is a pointer type value i.e. a single variable being accessed concurrently
@@ -137,5 +138,5 @@ func EmbeddingExample() {
 	go doIncrementWithPointerLockAndValueReceiver("b")
 
 	time.Sleep(time.Second * 5)
-	fmt.Println(c.counter)
+	fmt.Fprintln(w, c.counter)
 }
@@ -1,6 +1,9 @@
 package structs
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /*
 #### Inheritance ####
@@ -67,20 +70,20 @@ lion struct - embedding  nonAquatic struct
 	In our example “swim”  and “walk”  are those methods.
 */
 type iAnimal interface {
-	breathe()
+	breathe(w io.Writer)
 }
 type animal struct {
 }
 
 // animal struct implements iAnimal interface
-func (a *animal) breathe() {
-	fmt.Println("Animal breate")
+func (a *animal) breathe(w io.Writer) {
+	fmt.Fprintln(w, "Animal breate")
 }
 
 // iAquatic interface embedds iAnimal interface
 type iAquatic interface {
 	iAnimal
-	swim()
+	swim(w io.Writer)
 }
 
 // aquatic struct embedds animal struct
@@ -89,14 +92,14 @@ type aquatic struct {
 }
 
 // aquatic sturct implements iAquatic interface
-func (a *aquatic) swim() {
-	fmt.Println("Aquatic swim")
+func (a *aquatic) swim(w io.Writer) {
+	fmt.Fprintln(w, "Aquatic swim")
 }
 
 // iNonAquatic interface embedds iAnimal interface
 type iNonAquatic interface {
 	iAnimal
-	walk()
+	walk(w io.Writer)
 }
 
 // nonAquatic struct embedds animal struct
@@ -105,8 +108,8 @@ type nonAquatic struct {
 }
 
 // nonAquatic struct implements iNonAquatic interface
-func (a *nonAquatic) walk() {
-	fmt.Println("Non-Aquatic walk")
+func (a *nonAquatic) walk(w io.Writer) {
+	fmt.Fprintln(w, "Non-Aquatic walk")
 }
 
 type shark struct {
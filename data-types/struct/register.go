@@ -0,0 +1,14 @@
+package structs
+
+import "examples/registry"
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "struct/embedding",
+		Title:       "Struct Embedding",
+		Category:    registry.CATEGORY_DATA_TYPES,
+		Description: "Embedding a sync.Mutex, value vs pointer receivers and copied locks",
+		Source:      "data-types/struct/embedding.go",
+		Run:         registry.Simple(EmbeddingExample),
+	})
+}
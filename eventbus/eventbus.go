@@ -0,0 +1,276 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+Bus broadcasts events to the subscribers of their topic, in-process, unlike the fan-out of a
+channel where every value goes to a single receiver.
+
+Topics are dot separated, ie "worker.result". A subscription pattern matches a topic segment by
+segment, where "*" matches any one segment and a trailing "#" matches all the remaining ones:
+
+	worker.result    only worker.result
+	worker.*         worker.result, worker.metrics but not worker.result.late
+	worker.#         worker.result, worker.result.late but not worker
+	#                every topic
+
+Every subscriber has its own buffered channel, so a slow subscriber does not delay the others,
+its Policy decides what happens once its buffer is full. A subscription ends once its context
+is cancelled, its channel is then closed.
+*/
+type Bus struct {
+	mulock sync.RWMutex
+	subs   map[uint64]*Subscription
+	nextID uint64
+
+	seq       uint64 // of the published events
+	published int64
+	delivered int64
+	dropped   int64
+}
+
+type Policy string
+
+const (
+	POLICY_DROP_OLDEST Policy = "drop-oldest" // makes room by dropping the oldest buffered event
+	POLICY_DROP_NEWEST Policy = "drop-newest" // drops the event being published
+	POLICY_BLOCK       Policy = "block"       // the publisher waits up to BlockTimeout, then drops the event
+)
+
+const (
+	DEFAULT_BUFFER        = 64
+	DEFAULT_BLOCK_TIMEOUT = 100 * time.Millisecond
+)
+
+var (
+	ErrInvalidTopic  = errors.New("Invalid topic!")
+	ErrInvalidPolicy = errors.New("Invalid slow consumer policy!")
+)
+
+type Event struct {
+	Seq   uint64      `json:"seq"`
+	Topic string      `json:"topic"`
+	Data  interface{} `json:"data"`
+	Time  time.Time   `json:"time"`
+}
+
+type Options struct {
+	Buffer       int           // default DEFAULT_BUFFER
+	Policy       Policy        // default POLICY_DROP_OLDEST
+	BlockTimeout time.Duration // of POLICY_BLOCK, default DEFAULT_BLOCK_TIMEOUT
+}
+
+type Metrics struct {
+	Subscribers int   `json:"subscribers"`
+	Published   int64 `json:"published"`
+	Delivered   int64 `json:"delivered"` // to a subscriber, an event can be delivered to many
+	Dropped     int64 `json:"dropped"`   // by the policy of a slow subscriber
+}
+
+/*
+Subscription receives the events matching its pattern on C, till its context is cancelled.
+*/
+type Subscription struct {
+	ID      uint64
+	Pattern string
+
+	bus     *Bus
+	ch      chan Event
+	opts    Options
+	ctx     context.Context
+	mulock  sync.Mutex // serialises the deliveries and the close
+	closed  bool
+	dropped int64
+}
+
+func New() *Bus {
+	return &Bus{subs: map[uint64]*Subscription{}}
+}
+
+/*
+Subscribe to the topics matching pattern, the subscription ends once ctx is cancelled.
+*/
+func (b *Bus) Subscribe(ctx context.Context, pattern string, opts Options) (s *Subscription, err error) {
+	if err = validate(pattern, true); err != nil {
+		return
+	}
+	if opts.Policy == "" {
+		opts.Policy = POLICY_DROP_OLDEST
+	}
+	if opts.Policy != POLICY_DROP_OLDEST && opts.Policy != POLICY_DROP_NEWEST && opts.Policy != POLICY_BLOCK {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPolicy, opts.Policy)
+	}
+	if opts.Buffer < 1 {
+		opts.Buffer = DEFAULT_BUFFER
+	}
+	if opts.BlockTimeout <= 0 {
+		opts.BlockTimeout = DEFAULT_BLOCK_TIMEOUT
+	}
+
+	s = &Subscription{Pattern: pattern, bus: b, ch: make(chan Event, opts.Buffer), opts: opts, ctx: ctx}
+	b.mulock.Lock()
+	b.nextID++
+	s.ID = b.nextID
+	b.subs[s.ID] = s
+	b.mulock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mulock.Lock()
+		delete(b.subs, s.ID)
+		b.mulock.Unlock()
+		s.close()
+	}()
+	return
+}
+
+/*
+Publish sends the event to every subscriber of the topic, returning to how many it was delivered.
+It only waits on the subscribers with POLICY_BLOCK.
+*/
+func (b *Bus) Publish(topic string, data interface{}) (delivered int, err error) {
+	if err = validate(topic, false); err != nil {
+		return
+	}
+	e := Event{Seq: atomic.AddUint64(&b.seq, 1), Topic: topic, Data: data, Time: time.Now()}
+	atomic.AddInt64(&b.published, 1)
+
+	// deliver outside the lock of the bus, a blocking subscriber must not hold up Subscribe
+	b.mulock.RLock()
+	subs := make([]*Subscription, 0, len(b.subs))
+	for _, s := range b.subs {
+		if match(s.Pattern, topic) {
+			subs = append(subs, s)
+		}
+	}
+	b.mulock.RUnlock()
+
+	for _, s := range subs {
+		if s.deliver(e) {
+			delivered++
+		}
+	}
+	atomic.AddInt64(&b.delivered, int64(delivered))
+	return
+}
+
+func (b *Bus) Metrics() Metrics {
+	b.mulock.RLock()
+	subscribers := len(b.subs)
+	b.mulock.RUnlock()
+	return Metrics{
+		Subscribers: subscribers,
+		Published:   atomic.LoadInt64(&b.published),
+		Delivered:   atomic.LoadInt64(&b.delivered),
+		Dropped:     atomic.LoadInt64(&b.dropped),
+	}
+}
+
+// C is closed once the subscription ends
+func (s *Subscription) C() <-chan Event {
+	return s.ch
+}
+
+// Dropped events of this subscriber, by its policy
+func (s *Subscription) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+/*
+deliver applies the policy of the subscriber when its buffer is full, reporting whether the event
+was delivered. The oldest event dropped to make room (POLICY_DROP_OLDEST) counts as dropped.
+*/
+func (s *Subscription) deliver(e Event) bool {
+	s.mulock.Lock()
+	defer s.mulock.Unlock()
+	if s.closed {
+		return false
+	}
+	select {
+	case s.ch <- e:
+		return true
+	default:
+	}
+
+	switch s.opts.Policy {
+	case POLICY_DROP_OLDEST:
+		// the subscriber might read meanwhile, then there is room without dropping
+		for {
+			select {
+			case <-s.ch:
+				s.drop()
+			default:
+			}
+			select {
+			case s.ch <- e:
+				return true
+			default:
+			}
+		}
+	case POLICY_BLOCK:
+		timer := time.NewTimer(s.opts.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case s.ch <- e:
+			return true
+		case <-timer.C:
+		case <-s.ctx.Done():
+		}
+	}
+	s.drop()
+	return false
+}
+
+func (s *Subscription) drop() {
+	atomic.AddInt64(&s.dropped, 1)
+	atomic.AddInt64(&s.bus.dropped, 1)
+}
+
+func (s *Subscription) close() {
+	s.mulock.Lock()
+	defer s.mulock.Unlock()
+	s.closed = true
+	close(s.ch)
+}
+
+/*
+validate the topic, or the pattern when wildcards are allowed: no empty segment and "#" only as
+the last segment.
+*/
+func validate(topic string, wildcards bool) error {
+	segments := strings.Split(topic, ".")
+	for i, seg := range segments {
+		switch {
+		case seg == "":
+		case !wildcards && (seg == "*" || seg == "#"):
+		case seg == "#" && i != len(segments)-1:
+		default:
+			continue
+		}
+		return fmt.Errorf("%w: %q", ErrInvalidTopic, topic)
+	}
+	return nil
+}
+
+// match the topic against the pattern, segment by segment
+func match(pattern, topic string) bool {
+	patterns, topics := strings.Split(pattern, "."), strings.Split(topic, ".")
+	for i, p := range patterns {
+		if p == "#" {
+			// at least one segment, worker.# does not match worker
+			return i < len(topics)
+		}
+		if i >= len(topics) || (p != "*" && p != topics[i]) {
+			return false
+		}
+	}
+	return len(patterns) == len(topics)
+}
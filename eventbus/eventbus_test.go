@@ -0,0 +1,196 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		name            string
+		pattern         string
+		topic           string
+		output_expected bool
+	}{
+		{name: "Exact-TC-1", pattern: "worker.result", topic: "worker.result", output_expected: true},
+		{name: "Exact-TC-2", pattern: "worker.result", topic: "worker.metrics"},
+		{name: "Star-TC-3", pattern: "worker.*", topic: "worker.result", output_expected: true},
+		{name: "Star-TC-4", pattern: "worker.*", topic: "worker.result.late"},
+		{name: "Star-TC-5", pattern: "*.result", topic: "pipeline.result", output_expected: true},
+		{name: "Star-TC-6", pattern: "worker.*", topic: "worker"},
+		{name: "Hash-TC-7", pattern: "worker.#", topic: "worker.result.late", output_expected: true},
+		{name: "Hash-TC-8", pattern: "worker.#", topic: "worker"},
+		{name: "Hash-TC-9", pattern: "#", topic: "pipeline", output_expected: true},
+		{name: "Longer-TC-10", pattern: "worker.result.late", topic: "worker.result"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := match(tc.pattern, tc.topic); got != tc.output_expected {
+				t.Errorf("Expected %v, got: %v", tc.output_expected, got)
+			}
+		})
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	b := New()
+	for _, pattern := range []string{"", "worker..result", "#.result"} {
+		if _, err := b.Subscribe(context.Background(), pattern, Options{}); !errors.Is(err, ErrInvalidTopic) {
+			t.Errorf("Expected %v for pattern %q, got: %v", ErrInvalidTopic, pattern, err)
+		}
+	}
+	for _, topic := range []string{"", "worker.*", "worker.#"} {
+		if _, err := b.Publish(topic, nil); !errors.Is(err, ErrInvalidTopic) {
+			t.Errorf("Expected %v for topic %q, got: %v", ErrInvalidTopic, topic, err)
+		}
+	}
+	if _, err := b.Subscribe(context.Background(), "#", Options{Policy: "retry"}); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("Expected %v, got: %v", ErrInvalidPolicy, err)
+	}
+}
+
+func drain(s *Subscription) (data []interface{}) {
+	for {
+		select {
+		case e := <-s.C():
+			data = append(data, e.Data)
+		default:
+			return
+		}
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	testCases := []struct {
+		name            string
+		policy          Policy
+		output_expected []interface{}
+		delivered       int64 // the dropped oldest ones were delivered before
+	}{
+		{name: "DropOldest-TC-1", policy: POLICY_DROP_OLDEST, output_expected: []interface{}{3, 4, 5}, delivered: 10},
+		{name: "DropNewest-TC-2", policy: POLICY_DROP_NEWEST, output_expected: []interface{}{1, 2, 3}, delivered: 8},
+		{name: "Block-TC-3", policy: POLICY_BLOCK, output_expected: []interface{}{1, 2, 3}, delivered: 8},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			b := New()
+			slow, _ := b.Subscribe(ctx, "worker.*", Options{Buffer: 3, Policy: tc.policy, BlockTimeout: 10 * time.Millisecond})
+			fast, _ := b.Subscribe(ctx, "#", Options{Buffer: 10})
+
+			for i := 1; i <= 5; i++ {
+				b.Publish("worker.result", i)
+			}
+			got := drain(slow)
+			if len(got) != len(tc.output_expected) {
+				t.Fatalf("Expected %v, got: %v", tc.output_expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.output_expected[i] {
+					t.Fatalf("Expected %v, got: %v", tc.output_expected, got)
+				}
+			}
+			// the slow subscriber does not hold up the others
+			if got := drain(fast); len(got) != 5 {
+				t.Errorf("Expected all the events for the fast subscriber, got: %v", got)
+			}
+			m := b.Metrics()
+			if slow.Dropped() != 2 || m.Dropped != 2 || m.Published != 5 || m.Delivered != tc.delivered {
+				t.Errorf("Unexpected metrics %+v, dropped by the subscriber: %v", m, slow.Dropped())
+			}
+		})
+	}
+}
+
+func TestPolicyBlock(t *testing.T) {
+	b := New()
+	s, _ := b.Subscribe(context.Background(), "worker.result", Options{Buffer: 1, Policy: POLICY_BLOCK, BlockTimeout: time.Second})
+	b.Publish("worker.result", 1)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		<-s.C()
+	}()
+	// waits for the subscriber to read, rather than dropping
+	if n, _ := b.Publish("worker.result", 2); n != 1 || s.Dropped() != 0 {
+		t.Errorf("Expected the event delivered once read, got: %v delivered, %v dropped", n, s.Dropped())
+	}
+	if e := <-s.C(); e.Data != 2 || e.Seq != 2 || e.Topic != "worker.result" {
+		t.Errorf("Unexpected event: %+v", e)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	b := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	s, _ := b.Subscribe(ctx, "#", Options{})
+	b.Publish("pipeline.result", 1)
+	cancel()
+
+	got := []interface{}{}
+	for e := range s.C() {
+		got = append(got, e.Data)
+	}
+	if len(got) > 1 {
+		t.Errorf("Unexpected events: %v", got)
+	}
+	if n, _ := b.Publish("pipeline.result", 2); n != 0 || b.Metrics().Subscribers != 0 {
+		t.Errorf("Expected no subscribers, got: %+v", b.Metrics())
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	b := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		s, _ := b.Subscribe(ctx, "#", Options{Buffer: 4})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range s.C() {
+			}
+		}()
+	}
+	publishers := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		publishers.Add(1)
+		go func() {
+			defer publishers.Done()
+			for j := 0; j < 100; j++ {
+				b.Publish("worker.result", j)
+			}
+		}()
+	}
+	// unsubscribing while publishing must not send on a closed channel
+	time.Sleep(time.Millisecond)
+	cancel()
+	publishers.Wait()
+	wg.Wait()
+	if m := b.Metrics(); m.Published != 500 {
+		t.Errorf("Unexpected metrics: %+v", m)
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	b := New()
+	s, _ := b.Subscribe(context.Background(), "example.#", Options{})
+	lw := NewLineWriter(b, "example.channel.worker")
+	lw.Write([]byte("Primes: [2 3"))
+	lw.Write([]byte(" 5 7]\nChecked 10\nTook"))
+	lw.Flush()
+
+	got := drain(s)
+	expected := []interface{}{"Primes: [2 3 5 7]", "Checked 10", "Took"}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got: %v", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("Expected %v, got: %v", expected, got)
+		}
+	}
+}
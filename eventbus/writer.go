@@ -0,0 +1,51 @@
+package eventbus
+
+import (
+	"bytes"
+	"sync"
+)
+
+/*
+LineWriter publishes every line written to it as an event of topic, ie to stream the output of an
+example while it runs. It is safe for concurrent use, a partial line is held till its new line
+or Flush.
+*/
+type LineWriter struct {
+	bus   *Bus
+	topic string
+
+	mulock  sync.Mutex
+	partial []byte
+}
+
+func NewLineWriter(bus *Bus, topic string) *LineWriter {
+	return &LineWriter{bus: bus, topic: topic}
+}
+
+func (lw *LineWriter) Write(p []byte) (n int, err error) {
+	lw.mulock.Lock()
+	defer lw.mulock.Unlock()
+	lw.partial = append(lw.partial, p...)
+	for {
+		i := bytes.IndexByte(lw.partial, '\n')
+		if i < 0 {
+			break
+		}
+		if _, err = lw.bus.Publish(lw.topic, string(lw.partial[:i])); err != nil {
+			return
+		}
+		lw.partial = lw.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush publishes the partial line, if any
+func (lw *LineWriter) Flush() (err error) {
+	lw.mulock.Lock()
+	defer lw.mulock.Unlock()
+	if len(lw.partial) > 0 {
+		_, err = lw.bus.Publish(lw.topic, string(lw.partial))
+		lw.partial = nil
+	}
+	return
+}
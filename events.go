@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"examples/eventbus"
+	"examples/registry"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	SSE_HEARTBEAT  = 15 * time.Second
+	MAX_SSE_BUFFER = 1024 // events buffered per client
+)
+
+var (
+	ErrSSEBuffer = fmt.Errorf("Buffer should be 1 to %d!", MAX_SSE_BUFFER)
+	// a slow or stalled client would hold every Publish, ie the examples writing their output
+	ErrSSEPolicy = errors.New("Policy block is not allowed over HTTP, use drop-oldest or drop-newest!")
+)
+
+// the routes of the bus, mounted by serve before the circuit breaker
+func init() {
+	registry.RegisterRoute(registry.Route{Method: fiber.MethodGet, Path: "/events", Title: "Events",
+		Description: "Server-Sent Events of the bus, ie the output lines of the examples while they run (output.<name>) and their results (result.<name>)",
+		Params: []registry.Param{
+			{Name: "topic", Type: registry.PARAM_STRING, Default: "#", Usage: "topic pattern, ie output.channel.*"},
+			{Name: "buffer", Type: registry.PARAM_INT, Default: "64", Usage: "events buffered for the client, at most 1024"},
+			{Name: "policy", Type: registry.PARAM_STRING, Default: "drop-oldest", Usage: "once the buffer is full: drop-oldest or drop-newest"},
+		}})
+	registry.RegisterRoute(registry.Route{Method: fiber.MethodGet, Path: "/events/metrics", Title: "Events Metrics",
+		Description: "Subscribers, published, delivered and dropped events of the bus"})
+	registry.RegisterRoute(registry.Route{Method: fiber.MethodGet, Path: "/resilience/breaker", Title: "Circuit Breaker",
+		Description: "State of the circuit breaker of the /golang routes"})
+}
+
+/*
+exampleTopic of the events of an example, its name with dots ie channel.worker, as the slashes
+are not topic separators.
+*/
+func exampleTopic(kind, name string) string {
+	return kind + "." + strings.ReplaceAll(name, "/", ".")
+}
+
+/*
+events streams the events of the bus as Server-Sent Events, ie the output of the examples while
+they run:
+
+	curl -N 'localhost:3000/golang/events?topic=output.channel.*'
+
+Query parameters: topic (pattern, default #), buffer (at most MAX_SSE_BUFFER) and policy (drop-oldest
+or drop-newest) of the subscription. POLICY_BLOCK is refused, as the publishers would wait on the client.
+The stream ends once the client disconnects or the server shuts down.
+*/
+func events(bus *eventbus.Bus) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		opts := eventbus.Options{Policy: eventbus.Policy(c.Query("policy"))}
+		if opts.Policy == eventbus.POLICY_BLOCK {
+			return errorJSON(c, fiber.StatusBadRequest, ErrSSEPolicy)
+		}
+		if buffer := c.Query("buffer"); buffer != "" {
+			var err error
+			if opts.Buffer, err = strconv.Atoi(buffer); err != nil {
+				return errorJSON(c, fiber.StatusBadRequest, fmt.Errorf("Invalid buffer: %v", err))
+			}
+			if opts.Buffer < 1 || opts.Buffer > MAX_SSE_BUFFER {
+				return errorJSON(c, fiber.StatusBadRequest, fmt.Errorf("%w: %d", ErrSSEBuffer, opts.Buffer))
+			}
+		}
+		// the root context, the handler returns before the stream is written
+		ctx, cancel := context.WithCancel(c.UserContext())
+		sub, err := bus.Subscribe(ctx, c.Query("topic", "#"), opts)
+		if err != nil {
+			cancel()
+			return errorJSON(c, fiber.StatusBadRequest, err)
+		}
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer cancel()
+			heartbeat := time.NewTicker(SSE_HEARTBEAT)
+			defer heartbeat.Stop()
+
+			fmt.Fprintf(w, ": subscribed to %s\n\n", sub.Pattern)
+			// a failing flush is the client gone, which ends the subscription
+			for w.Flush() == nil {
+				select {
+				case e, ok := <-sub.C():
+					if !ok {
+						return
+					}
+					data, err := json.Marshal(e)
+					if err != nil {
+						data, _ = json.Marshal(err.Error())
+					}
+					fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Seq, e.Topic, data)
+				case <-heartbeat.C:
+					fmt.Fprint(w, ": ping\n\n")
+				}
+			}
+		})
+		return nil
+	}
+}
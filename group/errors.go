@@ -0,0 +1,81 @@
+package group
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrPanic = errors.New("Goroutine panicked!")
+
+/*
+PanicError is the error of a goroutine which panicked, with the stack at the panic.
+It matches ErrPanic via errors.Is.
+*/
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%v %v\n%s", ErrPanic, e.Value, e.Stack)
+}
+
+func (e *PanicError) Is(target error) bool {
+	return target == ErrPanic
+}
+
+/*
+MultiError holds several errors, see Join. errors.Is and errors.As match any of them.
+*/
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is and As are required till Go 1.20, errors.Is and errors.As do not look into Unwrap() []error before
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *MultiError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Join returns the errors as one, with the semantics of errors.Join of Go 1.20: the nil errors are
+dropped, it is nil when all of them are nil and the message is the messages one per line.
+*/
+func Join(errs ...error) error {
+	joined := &MultiError{}
+	for _, err := range errs {
+		if err != nil {
+			joined.Errors = append(joined.Errors, err)
+		}
+	}
+	if len(joined.Errors) == 0 {
+		return nil
+	}
+	return joined
+}
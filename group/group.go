@@ -0,0 +1,171 @@
+package group
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+/*
+Group runs goroutines working on the same task and waits for them, like errgroup:
+
+  - fail-fast (default): the first error cancels the context of the others, Wait returns that error
+  - collect-all (CollectAll option): every goroutine runs to its end, Wait returns all the errors joined
+  - the goroutines running at a time can be limited (WithLimit option), Go then waits for a slot
+  - a panic is recovered into a PanicError with its stack, rather than crashing the process
+
+For example:
+
+	g, ctx := group.New(ctx, group.WithLimit(4))
+	for _, url := range urls {
+		url := url
+		g.Go(func(ctx context.Context) error { return fetch(ctx, url) })
+	}
+	err := g.Wait()
+*/
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	opts   options
+	sem    chan struct{} // nil without a limit
+	wg     sync.WaitGroup
+
+	mulock sync.Mutex
+	errs   []error
+}
+
+type options struct {
+	limit      int
+	collectAll bool
+}
+
+type Option func(*options)
+
+// WithLimit allows at most n goroutines running at a time, n < 1 means no limit
+func WithLimit(n int) Option {
+	return func(o *options) { o.limit = n }
+}
+
+// CollectAll keeps the other goroutines running on an error, Wait joins all the errors
+func CollectAll() Option {
+	return func(o *options) { o.collectAll = true }
+}
+
+/*
+New returns the group along with its context, which is cancelled on the first error when failing
+fast, or once Wait returns.
+*/
+func New(ctx context.Context, opts ...Option) (*Group, context.Context) {
+	g := &Group{}
+	for _, opt := range opts {
+		opt(&g.opts)
+	}
+	if g.opts.limit > 0 {
+		g.sem = make(chan struct{}, g.opts.limit)
+	}
+	g.ctx, g.cancel = context.WithCancel(ctx)
+	return g, g.ctx
+}
+
+/*
+Go runs fn in a goroutine with the context of the group, waiting for a slot when limited.
+*/
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(fn)
+}
+
+// TryGo runs fn only if there is a slot free right away, reporting whether it did
+func (g *Group) TryGo(fn func(ctx context.Context) error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(fn)
+	return true
+}
+
+/*
+Wait waits for all the goroutines, then returns the first error when failing fast, or all the
+errors joined (see Join) in the order they occurred when collecting all.
+*/
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+
+	g.mulock.Lock()
+	defer g.mulock.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	if g.opts.collectAll {
+		return Join(g.errs...)
+	}
+	return g.errs[0]
+}
+
+func (g *Group) start(fn func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		if err := run(g.ctx, fn); err != nil {
+			g.fail(err)
+		}
+	}()
+}
+
+func (g *Group) fail(err error) {
+	g.mulock.Lock()
+	defer g.mulock.Unlock()
+	g.errs = append(g.errs, err)
+	if !g.opts.collectAll && len(g.errs) == 1 {
+		g.cancel()
+	}
+}
+
+// run calls fn, turning a panic into a PanicError
+func run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = &PanicError{Value: rec, Stack: debug.Stack()}
+		}
+	}()
+	return fn(ctx)
+}
+
+/*
+Map calls fn for every input in a group, returning the results in the order of the inputs along
+with the error of Wait. With CollectAll every input has its own result, else the inputs not run
+before the first error fail with the context error.
+*/
+func Map[In, Out any](ctx context.Context, inputs []In, fn func(context.Context, In) (Out, error), opts ...Option) ([]Result[Out], error) {
+	g, ctx := New(ctx, opts...)
+	results := make([]Result[Out], len(inputs))
+	for i, in := range inputs {
+		i, in := i, in
+		g.Go(func(ctx context.Context) error {
+			if err := ctx.Err(); err != nil {
+				results[i] = Fail[Out](err)
+				return err
+			}
+			var out Out
+			// recovered here, so the result of a panic holds the PanicError as well
+			err := run(ctx, func(ctx context.Context) (err error) {
+				out, err = fn(ctx, in)
+				return
+			})
+			results[i] = Result[Out]{Value: out, Err: err}
+			return err
+		})
+	}
+	err := g.Wait()
+	return results, err
+}
@@ -0,0 +1,185 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errOdd = errors.New("Odd!")
+
+func TestGroup(t *testing.T) {
+	testCases := []struct {
+		name          string
+		opts          []Option
+		tasks         int
+		fail          func(i int) bool
+		errs_expected int // 0: nil, 1: the first error, n: joined
+		cancelled     bool
+	}{
+		{name: "Success-TC-1", tasks: 10, fail: func(i int) bool { return false }},
+		{name: "FailFast-TC-2", tasks: 10, fail: func(i int) bool { return i == 3 }, errs_expected: 1, cancelled: true},
+		{name: "CollectAll-TC-3", opts: []Option{CollectAll()}, tasks: 10, fail: func(i int) bool { return i%2 == 1 }, errs_expected: 5},
+		{name: "Limit-TC-4", opts: []Option{WithLimit(2), CollectAll()}, tasks: 6, fail: func(i int) bool { return i == 0 }, errs_expected: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, ctx := New(context.Background(), tc.opts...)
+			var cancelled int64
+			for i := 0; i < tc.tasks; i++ {
+				i := i
+				g.Go(func(ctx context.Context) error {
+					if tc.fail(i) {
+						return errOdd
+					}
+					select {
+					case <-time.After(50 * time.Millisecond):
+					case <-ctx.Done():
+						atomic.AddInt64(&cancelled, 1)
+					}
+					return nil
+				})
+			}
+			err := g.Wait()
+
+			var multi *MultiError
+			switch {
+			case tc.errs_expected == 0 && err != nil:
+				t.Errorf("Expected no error, got: %v", err)
+			case tc.errs_expected > 0 && !errors.Is(err, errOdd):
+				t.Errorf("Expected %v, got: %v", errOdd, err)
+			case tc.errs_expected > 1 && (!errors.As(err, &multi) || len(multi.Errors) != tc.errs_expected):
+				t.Errorf("Expected %d errors joined, got: %v", tc.errs_expected, err)
+			}
+			if tc.cancelled != (atomic.LoadInt64(&cancelled) > 0) {
+				t.Errorf("Expected cancelled %v, got %d cancelled", tc.cancelled, cancelled)
+			}
+			if ctx.Err() == nil {
+				t.Error("Expected the context cancelled once Wait returns")
+			}
+		})
+	}
+}
+
+func TestGroupLimit(t *testing.T) {
+	g, _ := New(context.Background(), WithLimit(3))
+	var running, peak int64
+	for i := 0; i < 12; i++ {
+		g.Go(func(ctx context.Context) error {
+			n := atomic.AddInt64(&running, 1)
+			for p := atomic.LoadInt64(&peak); n > p && !atomic.CompareAndSwapInt64(&peak, p, n); p = atomic.LoadInt64(&peak) {
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt64(&running, -1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Error(err)
+	}
+	if peak != 3 {
+		t.Errorf("Expected 3 goroutines at most, got: %v", peak)
+	}
+}
+
+func TestGroupTryGo(t *testing.T) {
+	g, _ := New(context.Background(), WithLimit(2))
+	release := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		if !g.TryGo(func(ctx context.Context) error { <-release; return nil }) {
+			t.Errorf("Expected TryGo-%d to run", i)
+		}
+	}
+	if g.TryGo(func(ctx context.Context) error { return nil }) {
+		t.Error("Expected TryGo to fail, all the slots being taken")
+	}
+	close(release)
+	if err := g.Wait(); err != nil {
+		t.Error(err)
+	}
+	if !g.TryGo(func(ctx context.Context) error { return nil }) {
+		t.Error("Expected TryGo to run, the slots being free")
+	}
+	g.Wait()
+}
+
+func TestGroupPanic(t *testing.T) {
+	g, _ := New(context.Background())
+	g.Go(func(ctx context.Context) error {
+		var m map[string]int
+		m["boom"] = 1
+		return nil
+	})
+	err := g.Wait()
+
+	var panicErr *PanicError
+	if !errors.Is(err, ErrPanic) || !errors.As(err, &panicErr) {
+		t.Fatalf("Expected %v, got: %v", ErrPanic, err)
+	}
+	if !strings.Contains(err.Error(), "assignment to entry in nil map") || !strings.Contains(string(panicErr.Stack), "TestGroupPanic") {
+		t.Errorf("Expected the panic value and its stack, got: %v", err)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	errA, errB := errors.New("A!"), errors.New("B!")
+	testCases := []struct {
+		name            string
+		errs            []error
+		output_expected string
+	}{
+		{name: "Empty-TC-1"},
+		{name: "Nils-TC-2", errs: []error{nil, nil}},
+		{name: "One-TC-3", errs: []error{nil, errA}, output_expected: "A!"},
+		{name: "Many-TC-4", errs: []error{errA, nil, errB}, output_expected: "A!\nB!"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Join(tc.errs...)
+			if tc.output_expected == "" {
+				if err != nil {
+					t.Errorf("Expected nil, got: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.output_expected {
+				t.Fatalf("Expected %q, got: %v", tc.output_expected, err)
+			}
+			for _, e := range tc.errs {
+				if e != nil && !errors.Is(err, e) {
+					t.Errorf("Expected to match %v", e)
+				}
+			}
+		})
+	}
+}
+
+func TestMap(t *testing.T) {
+	half := func(ctx context.Context, n int) (int, error) {
+		time.Sleep(time.Duration(10-n) * time.Millisecond)
+		switch {
+		case n == 7:
+			panic("seven")
+		case n%2 == 1:
+			return 0, errOdd
+		}
+		return n / 2, nil
+	}
+	results, err := Map(context.Background(), []int{0, 1, 2, 3, 4, 5, 6, 7, 8}, half, CollectAll(), WithLimit(3))
+	if !errors.Is(err, errOdd) || !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected %v and %v, got: %v", errOdd, ErrPanic, err)
+	}
+	for i, r := range results {
+		v, err := r.Get()
+		switch {
+		case i == 7 && errors.Is(err, ErrPanic):
+		case i != 7 && i%2 == 1 && errors.Is(err, errOdd):
+		case i%2 == 0 && err == nil && v == i/2:
+		default:
+			t.Errorf("Unexpected result of %d: %+v", i, r)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package group
+
+/*
+Result couples a value with the error of producing it, so a goroutine can send both over one
+typed channel rather than an interface{} to be asserted, and the receiver can read the error.
+*/
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+func Ok[T any](value T) Result[T] {
+	return Result[T]{Value: value}
+}
+
+func Fail[T any](err error) Result[T] {
+	return Result[T]{Err: err}
+}
+
+// Get returns the value and the error, ie value, err := r.Get()
+func (r Result[T]) Get() (T, error) {
+	return r.Value, r.Err
+}
@@ -0,0 +1,38 @@
+package iterator
+
+import "context"
+
+/*
+Channel adapters, to use iterators with the pipeline and fan-out/fan-in code of data-types/channel.
+*/
+
+// FromChan iterates the values received from ch till it is closed or ctx is done
+func FromChan[T any](ctx context.Context, ch <-chan T) Iterator[T] {
+	return Func[T](func() (v T, ok bool) {
+		select {
+		case v, ok = <-ch:
+		case <-ctx.Done():
+		}
+		return
+	})
+}
+
+/*
+ToChan sends the elements of the iterator on the returned channel from a new goroutine,
+like the generator of a pipeline. The channel is closed at the end, or once ctx is done,
+so the goroutine never leaks on a receiver which stopped reading.
+*/
+func ToChan[T any](ctx context.Context, it Iterator[T]) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for v, ok := it.Next(); ok; v, ok = it.Next() {
+			select {
+			case ch <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,70 @@
+package iterator
+
+/*
+Combinators wrap an iterator into another one, nothing is iterated until Next is called:
+
+	Collect(Take(Filter(Map(list.Iter(), square), isEven), 3))
+*/
+
+func Map[T, U any](it Iterator[T], fn func(T) U) Iterator[U] {
+	return Func[U](func() (u U, ok bool) {
+		v, ok := it.Next()
+		if !ok {
+			return
+		}
+		return fn(v), true
+	})
+}
+
+func Filter[T any](it Iterator[T], keep func(T) bool) Iterator[T] {
+	return Func[T](func() (v T, ok bool) {
+		for v, ok = it.Next(); ok; v, ok = it.Next() {
+			if keep(v) {
+				return
+			}
+		}
+		return
+	})
+}
+
+// Take the first n elements, the rest of the source is not iterated
+func Take[T any](it Iterator[T], n int) Iterator[T] {
+	taken := 0
+	return Func[T](func() (v T, ok bool) {
+		if taken >= n {
+			return
+		}
+		if v, ok = it.Next(); ok {
+			taken++
+		}
+		return
+	})
+}
+
+// Zip pairs the elements of a and b, it stops with the shorter of the two
+func Zip[A, B any](a Iterator[A], b Iterator[B]) Iterator[Pair[A, B]] {
+	done := false
+	return Func[Pair[A, B]](func() (p Pair[A, B], ok bool) {
+		if done {
+			return
+		}
+		if p.First, ok = a.Next(); ok {
+			p.Second, ok = b.Next()
+		}
+		done = !ok
+		return
+	})
+}
+
+// Chain iterates the iterators one after the other
+func Chain[T any](its ...Iterator[T]) Iterator[T] {
+	return Func[T](func() (v T, ok bool) {
+		for len(its) > 0 {
+			if v, ok = its[0].Next(); ok {
+				return
+			}
+			its = its[1:]
+		}
+		return
+	})
+}
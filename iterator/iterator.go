@@ -0,0 +1,106 @@
+package iterator
+
+import "sort"
+
+/*
+Generic version of the behavioural iterator pattern.
+
+An Iterator returns the elements of a collection one by one, independent of the collection type.
+Next returns false once there are no more elements, it combines hasNext and getNext of the user iterator:
+
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		...
+	}
+
+Iterators are lazy and single use, a collection implementing Iterable creates a new one on every Iter() call.
+Modifying a collection while iterating it gives undefined results, as with the slices and maps of the language.
+*/
+type Iterator[T any] interface {
+	Next() (T, bool)
+}
+
+type Iterable[T any] interface {
+	Iter() Iterator[T]
+}
+
+// Func adapts a function to an Iterator
+type Func[T any] func() (T, bool)
+
+func (f Func[T]) Next() (T, bool) {
+	return f()
+}
+
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Ordered types, used to iterate maps in the order of their keys
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+func FromSlice[T any](s []T) Iterator[T] {
+	i := 0
+	return Func[T](func() (v T, ok bool) {
+		if i >= len(s) {
+			return
+		}
+		v = s[i]
+		i++
+		return v, true
+	})
+}
+
+/*
+FromMap iterates the key/value pairs of a map in random order, like range does.
+The keys are read when the iterator is created, a deleted key is skipped.
+*/
+func FromMap[K comparable, V any](m map[K]V) Iterator[Pair[K, V]] {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return fromKeys(m, keys)
+}
+
+// FromMapSorted iterates the key/value pairs of a map in the order of the keys
+func FromMapSorted[K Ordered, V any](m map[K]V) Iterator[Pair[K, V]] {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return fromKeys(m, keys)
+}
+
+func fromKeys[K comparable, V any](m map[K]V, keys []K) Iterator[Pair[K, V]] {
+	i := 0
+	return Func[Pair[K, V]](func() (p Pair[K, V], ok bool) {
+		for i < len(keys) {
+			k := keys[i]
+			i++
+			if v, found := m[k]; found {
+				return Pair[K, V]{First: k, Second: v}, true
+			}
+		}
+		return
+	})
+}
+
+// Collect the remaining elements into a slice
+func Collect[T any](it Iterator[T]) []T {
+	s := []T{}
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		s = append(s, v)
+	}
+	return s
+}
+
+func ForEach[T any](it Iterator[T], fn func(T)) {
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		fn(v)
+	}
+}
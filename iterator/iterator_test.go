@@ -0,0 +1,116 @@
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestCombinators(t *testing.T) {
+	ints := func() Iterator[int] { return FromSlice([]int{1, 2, 3, 4, 5, 6}) }
+	isEven := func(v int) bool { return v%2 == 0 }
+	testCases := []struct {
+		name            string
+		it              Iterator[int]
+		output_expected []int
+	}{
+		{name: "Slice-TC-1", it: ints(), output_expected: []int{1, 2, 3, 4, 5, 6}},
+		{name: "Empty-TC-2", it: FromSlice([]int{}), output_expected: []int{}},
+		{name: "Map-TC-3", it: Map(ints(), func(v int) int { return v * v }), output_expected: []int{1, 4, 9, 16, 25, 36}},
+		{name: "Filter-TC-4", it: Filter(ints(), isEven), output_expected: []int{2, 4, 6}},
+		{name: "FilterNone-TC-5", it: Filter(ints(), func(int) bool { return false }), output_expected: []int{}},
+		{name: "Take-TC-6", it: Take(ints(), 2), output_expected: []int{1, 2}},
+		{name: "TakeMore-TC-7", it: Take(ints(), 10), output_expected: []int{1, 2, 3, 4, 5, 6}},
+		{name: "TakeZero-TC-8", it: Take(ints(), 0), output_expected: []int{}},
+		{name: "Chain-TC-9", it: Chain(Take(ints(), 1), FromSlice([]int{}), Filter(ints(), isEven)), output_expected: []int{1, 2, 4, 6}},
+		{name: "ChainNone-TC-10", it: Chain[int](), output_expected: []int{}},
+		{name: "Composed-TC-11", it: Take(Filter(Map(ints(), func(v int) int { return v * 3 }), isEven), 2), output_expected: []int{6, 12}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if out := Collect(tc.it); !reflect.DeepEqual(out, tc.output_expected) {
+				t.Errorf("Actual output: %v, Expected output: %v", out, tc.output_expected)
+			}
+			// an exhausted iterator stays exhausted
+			if v, ok := tc.it.Next(); ok {
+				t.Errorf("Expected no more elements, got: %v", v)
+			}
+		})
+	}
+}
+
+func TestTakeIsLazy(t *testing.T) {
+	pulled := 0
+	var counter Iterator[int] = Func[int](func() (int, bool) { pulled++; return pulled, true })
+
+	if out := Collect(Take(counter, 3)); !reflect.DeepEqual(out, []int{1, 2, 3}) || pulled != 3 {
+		t.Errorf("Expected 3 elements pulled from an infinite iterator, got: %v pulled: %v", out, pulled)
+	}
+}
+
+func TestZip(t *testing.T) {
+	zipped := Zip(FromSlice([]int{1, 2, 3}), Map(FromSlice([]int{1, 2}), strconv.Itoa))
+	expected := []Pair[int, string]{{1, "1"}, {2, "2"}}
+	if out := Collect(zipped); !reflect.DeepEqual(out, expected) {
+		t.Errorf("Actual output: %v, Expected output: %v", out, expected)
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	m := map[string]int{"c": 3, "a": 1, "b": 2}
+
+	sorted := Collect(FromMapSorted(m))
+	expected := []Pair[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}
+	if !reflect.DeepEqual(sorted, expected) {
+		t.Errorf("Actual output: %v, Expected output: %v", sorted, expected)
+	}
+
+	sum := 0
+	ForEach(FromMap(m), func(p Pair[string, int]) { sum += p.Second })
+	if sum != 6 {
+		t.Errorf("Expected all pairs, got sum: %v", sum)
+	}
+
+	// a key deleted during the iteration is skipped
+	it := FromMapSorted(m)
+	delete(m, "b")
+	if out := Collect(it); len(out) != 2 {
+		t.Errorf("Expected deleted key to be skipped, got: %v", out)
+	}
+}
+
+func TestChan(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := ToChan(ctx, FromSlice([]string{"a", "b", "c"}))
+	if out := Collect(FromChan(ctx, ch)); fmt.Sprint(out) != "[a b c]" {
+		t.Errorf("Actual output: %v", out)
+	}
+
+	// cancelling ctx stops the sender goroutine and closes the channel
+	pulled := 0
+	var infinite Iterator[int] = Func[int](func() (int, bool) { pulled++; return pulled, true })
+	stop, cancelStop := context.WithCancel(context.Background())
+	ch2 := ToChan(stop, infinite)
+	<-ch2
+	cancelStop()
+	for range ch2 {
+	}
+}
+
+// FromChan stops once ctx is done, even if the channel is never closed
+func TestFromChanCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int, 1)
+	ch <- 1
+	it := FromChan(ctx, ch)
+	if v, ok := it.Next(); v != 1 || !ok {
+		t.Errorf("Expected 1, got: %v %v", v, ok)
+	}
+	cancel()
+	if v, ok := it.Next(); ok {
+		t.Errorf("Expected the end once cancelled, got: %v", v)
+	}
+}
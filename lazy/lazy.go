@@ -0,0 +1,114 @@
+package lazy
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+/*
+Lazy initialises a value once, on the first Get, and is safe for concurrent use.
+
+Unlike sync.Once, the init function can fail: the error is returned to the callers waiting on
+that attempt and the next Get retries, so a transient failure (ie DB not reachable yet) does
+not leave a broken singleton behind for the life of the process.
+The value is published via an atomic pointer, read without the lock on the fast path, so Reset can
+drop it while other goroutines Get it.
+*/
+type Lazy[T any] struct {
+	init func() (T, error)
+
+	mulock sync.Mutex
+	cell   atomic.Pointer[cell[T]] // nil till initialised
+}
+
+// cell holds the value, it is never modified once stored
+type cell[T any] struct {
+	value T
+}
+
+func New[T any](init func() (T, error)) *Lazy[T] {
+	return &Lazy[T]{init: init}
+}
+
+/*
+Get returns the value, initialising it if required. Concurrent callers wait for the one
+initialising it, rather than initialising it again.
+*/
+func (l *Lazy[T]) Get() (value T, err error) {
+	if c := l.cell.Load(); c != nil {
+		return c.value, nil
+	}
+
+	l.mulock.Lock()
+	defer l.mulock.Unlock()
+	c := l.cell.Load()
+	if c == nil {
+		if value, err = l.init(); err != nil {
+			return
+		}
+		c = &cell[T]{value: value}
+		l.cell.Store(c)
+	}
+	return c.value, nil
+}
+
+// Initialised reports whether Get has succeeded already
+func (l *Lazy[T]) Initialised() bool {
+	return l.cell.Load() != nil
+}
+
+/*
+Reset drops the value, so the next Get initialises it again, ie to start each test with a fresh
+singleton. It is safe for concurrent use: the callers which got the value already keep it.
+*/
+func (l *Lazy[T]) Reset() {
+	l.mulock.Lock()
+	defer l.mulock.Unlock()
+	l.cell.Store(nil)
+}
+
+/*
+Registry is a set of singletons, one per key, each initialised lazily via the same init function.
+Keys are initialised independently, a slow or failing key does not block the others.
+*/
+type Registry[K comparable, V any] struct {
+	init func(K) (V, error)
+
+	mulock sync.Mutex
+	items  map[K]*Lazy[V]
+}
+
+func NewRegistry[K comparable, V any](init func(K) (V, error)) *Registry[K, V] {
+	return &Registry[K, V]{init: init, items: map[K]*Lazy[V]{}}
+}
+
+func (r *Registry[K, V]) Get(key K) (V, error) {
+	r.mulock.Lock()
+	l, ok := r.items[key]
+	if !ok {
+		l = New(func() (V, error) { return r.init(key) })
+		r.items[key] = l
+	}
+	r.mulock.Unlock()
+
+	return l.Get()
+}
+
+// Keys returns the keys initialised successfully
+func (r *Registry[K, V]) Keys() (keys []K) {
+	r.mulock.Lock()
+	defer r.mulock.Unlock()
+	for key, l := range r.items {
+		if l.Initialised() {
+			keys = append(keys, key)
+		}
+	}
+	return
+}
+
+// Reset drops all the singletons of the registry, the next Get of each key initialises it again
+func (r *Registry[K, V]) Reset() {
+	r.mulock.Lock()
+	defer r.mulock.Unlock()
+	r.items = map[K]*Lazy[V]{}
+}
@@ -0,0 +1,126 @@
+package lazy
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+var errNotReady = errors.New("Not ready!")
+
+func TestLazy(t *testing.T) {
+	calls := int32(0)
+	l := New(func() (int, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return 0, errNotReady
+		}
+		return 42, nil
+	})
+
+	if _, err := l.Get(); !errors.Is(err, errNotReady) || l.Initialised() {
+		t.Fatalf("Expected: %v, got: %v", errNotReady, err)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := l.Get(); v != 42 || err != nil {
+				t.Errorf("Expected: 42, got: %v %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 2 {
+		t.Errorf("Expected init to be retried once and then memoised, got %v calls", calls)
+	}
+
+	l.Reset()
+	if l.Initialised() {
+		t.Fatal("Expected reset")
+	}
+	l.Get()
+	if calls != 3 {
+		t.Errorf("Expected init again after reset, got %v calls", calls)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	calls := map[string]int{}
+	var mulock sync.Mutex
+	r := NewRegistry(func(tenant string) (*string, error) {
+		mulock.Lock()
+		defer mulock.Unlock()
+		calls[tenant]++
+		if tenant == "" {
+			return nil, errNotReady
+		}
+		dsn := "postgres://" + tenant
+		return &dsn, nil
+	})
+
+	testCases := []struct {
+		name     string
+		tenant   string
+		expected error
+	}{
+		{name: "First-TC-1", tenant: "acme"},
+		{name: "Again-TC-2", tenant: "acme"},
+		{name: "Other-TC-3", tenant: "globex"},
+		{name: "Error-TC-4", tenant: "", expected: errNotReady},
+		{name: "Retry-TC-5", tenant: "", expected: errNotReady},
+	}
+	first := map[string]*string{}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := r.Get(tc.tenant)
+			if !errors.Is(err, tc.expected) {
+				t.Fatalf("Expected: %v, got: %v", tc.expected, err)
+			}
+			if err != nil {
+				return
+			}
+			if p, ok := first[tc.tenant]; ok && p != v {
+				t.Errorf("Expected the same instance for %v", tc.tenant)
+			}
+			first[tc.tenant] = v
+		})
+	}
+	if calls["acme"] != 1 || calls["globex"] != 1 || calls[""] != 2 || len(r.Keys()) != 2 {
+		t.Errorf("Unexpected init calls: %v, keys: %v", calls, r.Keys())
+	}
+
+	r.Reset()
+	if len(r.Keys()) != 0 {
+		t.Errorf("Expected no keys after reset, got: %v", r.Keys())
+	}
+}
+
+// Reset while other goroutines Get the value, run with -race
+func TestLazyResetConcurrent(t *testing.T) {
+	calls := int32(0)
+	l := New(func() (int32, error) { return atomic.AddInt32(&calls, 1), nil })
+
+	wg := sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if g == 0 && i%100 == 0 {
+					l.Reset()
+				}
+				if v, err := l.Get(); v < 1 || err != nil {
+					t.Errorf("Expected an initialised value, got: %v %v", v, err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if v, _ := l.Get(); v != atomic.LoadInt32(&calls) {
+		t.Errorf("Expected the value of the last init %v, got: %v", calls, v)
+	}
+}
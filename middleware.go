@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"examples/resilience"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+/*
+rateLimit rejects the requests beyond the rate of the limiter with 429, along with Retry-After.
+*/
+func rateLimit(l resilience.Limiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if ok, retryAfter := l.Allow(); !ok {
+			c.Set(fiber.HeaderRetryAfter, retryAfterSeconds(retryAfter))
+			return errorJSON(c, fiber.StatusTooManyRequests, resilience.ErrRateLimited)
+		}
+		return c.Next()
+	}
+}
+
+/*
+circuitBreaker fails fast with 503 while the breaker is open. The responses with a 5xx status
+are the failures counted by the breaker, ie examples failing, rather than the 4xx of the client's
+mistakes such as invalid arguments or a too short ?timeout= (see exampleStatus).
+*/
+func circuitBreaker(b *resilience.Breaker) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		done, err := b.Allow()
+		if err != nil {
+			var openErr *resilience.OpenError
+			if errors.As(err, &openErr) {
+				c.Set(fiber.HeaderRetryAfter, retryAfterSeconds(openErr.RetryAfter))
+			}
+			return errorJSON(c, fiber.StatusServiceUnavailable, err)
+		}
+
+		err = c.Next()
+		status := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if err != nil {
+			// the error handler sets the status only after the middlewares
+			status = fiber.StatusInternalServerError
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+		if status >= fiber.StatusInternalServerError {
+			done(fmt.Errorf("Status %d", status))
+		} else {
+			done(nil)
+		}
+		return
+	}
+}
+
+// retryAfterSeconds rounds up, so the client does not retry too early
+func retryAfterSeconds(d time.Duration) string {
+	return fmt.Sprint(int(math.Ceil(d.Seconds())))
+}
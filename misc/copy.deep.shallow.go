@@ -1,6 +1,9 @@
 package misc
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /*
 1. Deep Copy:
@@ -17,9 +20,11 @@ Copy the data address, only copy the pointer to the object. At this time, the me
 and the old object will change when the new object value is modified. When the memory address is released, the memory address is also released.
 
 All data of reference type are light copy, Slice and Map by default.
+
+See clone.DeepCopy to deep copy any value, including its slices, maps, pointers and cyclic references.
 */
 
-func CopyDeepShallow() {
+func CopyDeepShallow(w io.Writer) {
 	Int_1 := 0
 	Int_2 := Int_1
 	Array_1 := [3]int{1, 2, 3}
@@ -29,30 +34,30 @@ func CopyDeepShallow() {
 		age  int
 	}{"Harry", 41}
 	Struct_2 := Struct_1
-	fmt.Println("Observation: All the data types will have different memory address, however some may have values pointing to the same data type.")
-	fmt.Println("Deep Copy : Data Types")
-	fmt.Printf("\nInt: %p - %p", &Int_1, &Int_2)
+	fmt.Fprintln(w, "Observation: All the data types will have different memory address, however some may have values pointing to the same data type.")
+	fmt.Fprintln(w, "Deep Copy : Data Types")
+	fmt.Fprintf(w, "\nInt: %p - %p", &Int_1, &Int_2)
 	Int_2 = Int_2 + 1
-	fmt.Printf("\nInt: %v - %v", Int_1, Int_2)
-	fmt.Printf("\nArray: %p - %p", &Array_1, &Array_2)
+	fmt.Fprintf(w, "\nInt: %v - %v", Int_1, Int_2)
+	fmt.Fprintf(w, "\nArray: %p - %p", &Array_1, &Array_2)
 	Array_2[0] = 99
-	fmt.Printf("\nArray: %v - %v", Array_1, Array_2)
-	fmt.Printf("\nStruct: %p - %p", &Struct_1, &Struct_2)
+	fmt.Fprintf(w, "\nArray: %v - %v", Array_1, Array_2)
+	fmt.Fprintf(w, "\nStruct: %p - %p", &Struct_1, &Struct_2)
 	Struct_2.name = "Hardy"
-	fmt.Printf("\nStruct: %v - %v", Struct_1, Struct_2)
+	fmt.Fprintf(w, "\nStruct: %v - %v", Struct_1, Struct_2)
 
 	Slice_1 := []int{1, 2, 3}
 	Slice_2 := Slice_1
 	Map_1 := map[int]string{1: "a", 2: "b", 3: "c"}
 	Map_2 := Map_1
-	fmt.Println()
-	fmt.Println("Shallow Copy : Data Types")
-	fmt.Printf("\nSlice: %p - %p", &Slice_1, &Slice_2)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Shallow Copy : Data Types")
+	fmt.Fprintf(w, "\nSlice: %p - %p", &Slice_1, &Slice_2)
 	Slice_2[0] = 99
-	fmt.Printf("\nSlice: %v - %v", Slice_1, Slice_2)
-	fmt.Printf("\nMap: %p - %p", &Map_1, &Map_2)
+	fmt.Fprintf(w, "\nSlice: %v - %v", Slice_1, Slice_2)
+	fmt.Fprintf(w, "\nMap: %p - %p", &Map_1, &Map_2)
 	Map_2[1] = "xxx"
-	fmt.Printf("\nMap: %v - %v", Map_1, Map_2)
+	fmt.Fprintf(w, "\nMap: %v - %v", Map_1, Map_2)
 
 	/*
 		Caveats to value type assignment.
@@ -61,22 +66,22 @@ func CopyDeepShallow() {
 		Hence, it will create a Shallow coppy for even a struct
 	*/
 
-	fmt.Println()
-	fmt.Println("\n\nCaveats: Not Shallow BUT Deep Copy : Struct Type !!!!")
-	fmt.Println("\n1. Pointer to a Struct: Struct_3 := &Struct_2")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "\n\nCaveats: Not Shallow BUT Deep Copy : Struct Type !!!!")
+	fmt.Fprintln(w, "\n1. Pointer to a Struct: Struct_3 := &Struct_2")
 	Struct_3 := &Struct_2
 	Struct_3.name = "Garry"
-	fmt.Printf("\nStruct: %p - %p", &Struct_2, &Struct_3)
-	fmt.Printf("\nStruct: %v - %v", Struct_2, Struct_3)
+	fmt.Fprintf(w, "\nStruct: %p - %p", &Struct_2, &Struct_3)
+	fmt.Fprintf(w, "\nStruct: %v - %v", Struct_2, Struct_3)
 
-	fmt.Println()
-	fmt.Println("\n2. Using new() function: It returns Pointer to Struct")
-	fmt.Println("\nStruct_4 := new(struct{ name string })")
-	fmt.Println("Struct_5 := Struct_4")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "\n2. Using new() function: It returns Pointer to Struct")
+	fmt.Fprintln(w, "\nStruct_4 := new(struct{ name string })")
+	fmt.Fprintln(w, "Struct_5 := Struct_4")
 	Struct_4 := new(struct{ name string })
 	Struct_4.name = "Sherry"
 	Struct_5 := Struct_4 // ideally it should be shallow copy, but as new() returns a pointer, it is a deep copy
 	Struct_5.name = "Marry"
-	fmt.Printf("\nStruct: %p - %p", &Struct_4, &Struct_5)
-	fmt.Printf("\nStruct: %v - %v", Struct_4, Struct_5)
+	fmt.Fprintf(w, "\nStruct: %p - %p", &Struct_4, &Struct_5)
+	fmt.Fprintf(w, "\nStruct: %v - %v", Struct_4, Struct_5)
 }
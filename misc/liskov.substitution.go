@@ -1,6 +1,9 @@
 package misc
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /*
 In the code above we can analyze the following:
@@ -42,13 +45,15 @@ type motorcycle struct {
 }
 
 // common method to get vehicle name
-type Printer struct{}
+type Printer struct {
+	out io.Writer
+}
 
 func (p Printer) printVehicleName(t transport) {
-	fmt.Println("Vehicle Name: ", t.getVehicleName())
+	fmt.Fprintln(p.out, "Vehicle Name: ", t.getVehicleName())
 }
 
-func LiskovSubstitutionExample() {
+func LiskovSubstitutionExample(w io.Writer) {
 	car1 := car{
 		vehicle: vehicle{name: "Alto"},
 		wheel:   4,
@@ -58,7 +63,7 @@ func LiskovSubstitutionExample() {
 		vehicle: vehicle{name: "royal enfield"},
 		wheel:   2,
 	}
-	p := Printer{}
+	p := Printer{out: w}
 
 	// we should be able to use memthod of vehicle concrete type for car and motorcycle concrete types,
 	// if they have the same(implement or access via embedding) type ie transport interface type
@@ -0,0 +1,14 @@
+package misc
+
+import "examples/registry"
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "copy/deep-shallow",
+		Title:       "Copy Deep and Shallow",
+		Category:    registry.CATEGORY_MISC,
+		Description: "Value types are deep copied while slices, maps and pointers are shallow copied",
+		Source:      "misc/copy.deep.shallow.go",
+		Run:         registry.Simple(CopyDeepShallow),
+	})
+}
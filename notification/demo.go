@@ -0,0 +1,32 @@
+package notification
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	demoOnce      sync.Once
+	demoNotifier  *Notifier
+	demoProviders []*Memory
+)
+
+/*
+Demo returns a notifier shared by the examples and the API: every type is bridged with
+the in-memory xvendor (primary) and yvendor (failover) providers.
+*/
+func Demo() (n *Notifier, xvendor, yvendor *Memory) {
+	demoOnce.Do(func() {
+		t := NewTemplates()
+		t.Register("welcome", "Welcome {{.name}}", "Hi {{.name}}, welcome aboard!")
+		t.Register("otp", "Your OTP", "{{.otp}} is your OTP, valid for {{.ttl}}. Do not share it with anyone.")
+
+		demoProviders = []*Memory{NewMemory("xvendor"), NewMemory("yvendor")}
+		demoNotifier = NewNotifier(NewFactory(), t)
+		retry := RetryPolicy{Attempts: 2, Backoff: 10 * time.Millisecond}
+		for _, nt := range demoNotifier.Factory.Types() {
+			demoNotifier.Configure(nt, retry, demoProviders[0], demoProviders[1])
+		}
+	})
+	return demoNotifier, demoProviders[0], demoProviders[1]
+}
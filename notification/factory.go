@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Builder creates a Channel bridged with the given providers
+type Builder func(retry RetryPolicy, providers ...Provider) Channel
+
+/*
+Factory creates channels by NotificationType, like creational.NotificationFactory,
+but the types are not hard coded in a switch: new types are registered at runtime.
+*/
+type Factory struct {
+	mulock   sync.RWMutex
+	builders map[NotificationType]Builder
+}
+
+// NewFactory with the SMS, WhatsApp and Email channels registered
+func NewFactory() *Factory {
+	f := &Factory{builders: map[NotificationType]Builder{}}
+	f.Register(SMS_NOTIFICATION, NewSMS)
+	f.Register(WHATSAPP_NOTIFICATION, NewWhatsApp)
+	f.Register(EMAIL_NOTIFICATION, NewEmail)
+	return f
+}
+
+func (f *Factory) Register(nt NotificationType, b Builder) (err error) {
+	if nt == "" || b == nil {
+		return fmt.Errorf("Notification type and builder are required!")
+	}
+	f.mulock.Lock()
+	defer f.mulock.Unlock()
+	if _, ok := f.builders[nt]; ok {
+		return fmt.Errorf("Notification type already registered: %v", nt)
+	}
+	f.builders[nt] = b
+	return
+}
+
+func (f *Factory) Create(nt NotificationType, retry RetryPolicy, providers ...Provider) (ch Channel, err error) {
+	f.mulock.RLock()
+	b, ok := f.builders[nt]
+	f.mulock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unknown notification type: %v", nt)
+	}
+	return b(retry, providers...), nil
+}
+
+func (f *Factory) Types() (types []NotificationType) {
+	f.mulock.RLock()
+	defer f.mulock.RUnlock()
+	for nt := range f.builders {
+		types = append(types, nt)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return
+}
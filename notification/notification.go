@@ -0,0 +1,213 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sync/atomic"
+	"time"
+)
+
+/*
+Notification subsystem, the working version of creational.NotificationFactory and the structural bridge example.
+
+Bridge: a Channel (SMS/WhatsApp/Email) is the abstraction, it validates the recipient and the content.
+A Provider (vendor) is the implementation, it delivers the message. Any channel can be bridged with any provider(s)
+at runtime, the first provider is the primary one and the others are used for failover.
+
+Factory: the channels are created by a Factory keyed by NotificationType, new types can be registered at runtime.
+*/
+
+type NotificationType string
+
+const (
+	SMS_NOTIFICATION      NotificationType = "SMS"
+	WHATSAPP_NOTIFICATION NotificationType = "WHATSAPP"
+	EMAIL_NOTIFICATION    NotificationType = "EMAIL"
+)
+
+var (
+	ErrInvalidRecipient = errors.New("Invalid recipient!")
+	ErrInvalidContent   = errors.New("Invalid content!")
+	ErrNoProvider       = errors.New("No provider configured!")
+	ErrDeliveryFailed   = errors.New("Delivery failed on all providers!")
+)
+
+type Content struct {
+	Subject string `json:"subject,omitempty"`
+	Body    string `json:"body"`
+}
+
+// Message handed over to a provider
+type Message struct {
+	ID      string           `json:"id"`
+	Type    NotificationType `json:"type"`
+	To      string           `json:"to"`
+	Subject string           `json:"subject,omitempty"`
+	Body    string           `json:"body"`
+}
+
+type Attempt struct {
+	Provider string `json:"provider"`
+	Attempt  int    `json:"attempt"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Receipt of a notification, Provider is the one which delivered it and Attempts lists every try
+type Receipt struct {
+	MessageID string           `json:"message_id"`
+	Type      NotificationType `json:"type"`
+	To        string           `json:"to"`
+	Provider  string           `json:"provider,omitempty"`
+	Attempts  []Attempt        `json:"attempts"`
+}
+
+type Channel interface {
+	Type() NotificationType
+	Send(ctx context.Context, to string, c Content) (Receipt, error)
+}
+
+/*
+RetryPolicy per provider: a provider is tried Attempts times, waiting Backoff before the first retry
+and doubling it for every next retry. After that the next provider is tried (failover).
+*/
+type RetryPolicy struct {
+	Attempts int
+	Backoff  time.Duration
+}
+
+var messageSeq int64
+
+/*
+channel implements Channel for all the built-in types, they only differ in validation.
+It is also usable for new types via NewChannel.
+*/
+type channel struct {
+	ntype     NotificationType
+	validate  func(to string, c Content) error
+	providers []Provider
+	retry     RetryPolicy
+}
+
+/*
+NewChannel bridges a notification type with its providers.
+validate checks the recipient and content before any provider is called, it is optional.
+*/
+func NewChannel(nt NotificationType, validate func(to string, c Content) error, retry RetryPolicy, providers ...Provider) Channel {
+	if retry.Attempts < 1 {
+		retry.Attempts = 1
+	}
+	return &channel{ntype: nt, validate: validate, providers: providers, retry: retry}
+}
+
+var mobileRegex = regexp.MustCompile(`^\+?[0-9]{10,15}$`)
+
+func validateMobile(to string) error {
+	if !mobileRegex.MatchString(to) {
+		return fmt.Errorf("%w: %q is not a mobile number", ErrInvalidRecipient, to)
+	}
+	return nil
+}
+
+func NewSMS(retry RetryPolicy, providers ...Provider) Channel {
+	return NewChannel(SMS_NOTIFICATION, func(to string, c Content) error {
+		if err := validateMobile(to); err != nil {
+			return err
+		}
+		if len(c.Body) == 0 || len(c.Body) > 160 {
+			return fmt.Errorf("%w: SMS body must be 1-160 characters", ErrInvalidContent)
+		}
+		return nil
+	}, retry, providers...)
+}
+
+func NewWhatsApp(retry RetryPolicy, providers ...Provider) Channel {
+	return NewChannel(WHATSAPP_NOTIFICATION, func(to string, c Content) error {
+		if err := validateMobile(to); err != nil {
+			return err
+		}
+		if len(c.Body) == 0 {
+			return fmt.Errorf("%w: WhatsApp body is empty", ErrInvalidContent)
+		}
+		return nil
+	}, retry, providers...)
+}
+
+func NewEmail(retry RetryPolicy, providers ...Provider) Channel {
+	return NewChannel(EMAIL_NOTIFICATION, func(to string, c Content) error {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("%w: %q is not an email address", ErrInvalidRecipient, to)
+		}
+		if c.Subject == "" || c.Body == "" {
+			return fmt.Errorf("%w: email needs a subject and a body", ErrInvalidContent)
+		}
+		return nil
+	}, retry, providers...)
+}
+
+func (ch *channel) Type() NotificationType {
+	return ch.ntype
+}
+
+/*
+Send validates the message and delivers it via the first provider which succeeds.
+Each provider is retried as per the RetryPolicy, unless it returns a Permanent error.
+*/
+func (ch *channel) Send(ctx context.Context, to string, c Content) (r Receipt, err error) {
+	if ch.validate != nil {
+		if err = ch.validate(to, c); err != nil {
+			return
+		}
+	}
+	if len(ch.providers) == 0 {
+		return r, fmt.Errorf("%w: %v", ErrNoProvider, ch.ntype)
+	}
+
+	msg := Message{
+		ID:      fmt.Sprintf("msg-%d", atomic.AddInt64(&messageSeq, 1)),
+		Type:    ch.ntype,
+		To:      to,
+		Subject: c.Subject,
+		Body:    c.Body,
+	}
+	r = Receipt{MessageID: msg.ID, Type: ch.ntype, To: to, Attempts: []Attempt{}}
+
+	for _, p := range ch.providers {
+		backoff := ch.retry.Backoff
+		for attempt := 1; attempt <= ch.retry.Attempts; attempt++ {
+			if attempt > 1 {
+				if err = sleep(ctx, backoff); err != nil {
+					return r, fmt.Errorf("%w: %v", ErrDeliveryFailed, err)
+				}
+				backoff *= 2
+			}
+
+			sendErr := p.Send(ctx, msg)
+			a := Attempt{Provider: p.Name(), Attempt: attempt}
+			if sendErr == nil {
+				r.Attempts = append(r.Attempts, a)
+				r.Provider = p.Name()
+				return r, nil
+			}
+			a.Error = sendErr.Error()
+			r.Attempts = append(r.Attempts, a)
+			if IsPermanent(sendErr) {
+				break // failover, retrying this provider would not help
+			}
+		}
+	}
+	return r, fmt.Errorf("%w: %v after %d attempt(s)", ErrDeliveryFailed, ch.ntype, len(r.Attempts))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,182 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func testNotifier(t *testing.T, retry RetryPolicy) (n *Notifier, xv, yv *Memory) {
+	tmpl := NewTemplates()
+	if err := tmpl.Register("welcome", "Welcome {{.name}}", "Hi {{.name}}"); err != nil {
+		t.Fatal(err)
+	}
+	xv, yv = NewMemory("xvendor"), NewMemory("yvendor")
+	n = NewNotifier(NewFactory(), tmpl)
+	for _, nt := range n.Factory.Types() {
+		if err := n.Configure(nt, retry, xv, yv); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return
+}
+
+func TestNotify(t *testing.T) {
+	testCases := []struct {
+		name              string
+		req               Request
+		xvendorFails      int
+		xvendorErr        error
+		provider_expected string
+		attempts_expected int
+		err_expected      error
+	}{
+		{
+			name:              "Template-TC-1",
+			req:               Request{Type: EMAIL_NOTIFICATION, To: "harry@gmail.com", Template: "welcome", Data: map[string]interface{}{"name": "Harry"}},
+			provider_expected: "xvendor",
+			attempts_expected: 1,
+		},
+		{
+			name:              "Retry-TC-2",
+			req:               Request{Type: SMS_NOTIFICATION, To: "9910825975", Body: "Hi"},
+			xvendorFails:      1,
+			provider_expected: "xvendor",
+			attempts_expected: 2,
+		},
+		{
+			name:              "Failover-TC-3",
+			req:               Request{Type: WHATSAPP_NOTIFICATION, To: "+919910825975", Body: "Hi"},
+			xvendorFails:      2,
+			provider_expected: "yvendor",
+			attempts_expected: 3,
+		},
+		{
+			name:              "Permanent-TC-4",
+			req:               Request{Type: SMS_NOTIFICATION, To: "9910825975", Body: "Hi"},
+			xvendorFails:      1,
+			xvendorErr:        Permanent(errors.New("rejected")),
+			provider_expected: "yvendor",
+			attempts_expected: 2,
+		},
+		{
+			name:         "InvalidRecipient-TC-5",
+			req:          Request{Type: SMS_NOTIFICATION, To: "harry@gmail.com", Body: "Hi"},
+			err_expected: ErrInvalidRecipient,
+		},
+		{
+			name:         "MissingTemplateData-TC-6",
+			req:          Request{Type: SMS_NOTIFICATION, To: "9910825975", Template: "welcome"},
+			err_expected: ErrInvalidContent,
+		},
+		{
+			name:         "UnknownTemplate-TC-7",
+			req:          Request{Type: SMS_NOTIFICATION, To: "9910825975", Template: "unknown"},
+			err_expected: ErrInvalidContent,
+		},
+		{
+			name:         "UnknownType-TC-8",
+			req:          Request{Type: "PIGEON", To: "9910825975", Body: "Hi"},
+			err_expected: ErrNoProvider,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n, xv, yv := testNotifier(t, RetryPolicy{Attempts: 2, Backoff: time.Millisecond})
+			xv.FailNext(tc.xvendorFails, tc.xvendorErr)
+
+			r, err := n.Notify(context.Background(), tc.req)
+			if tc.err_expected != nil {
+				if !errors.Is(err, tc.err_expected) {
+					t.Errorf("Expected error: %v, got: %v", tc.err_expected, err)
+				}
+				if len(xv.Sent())+len(yv.Sent()) != 0 {
+					t.Errorf("Expected nothing to be sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if r.Provider != tc.provider_expected || len(r.Attempts) != tc.attempts_expected {
+				t.Errorf("Expected provider %v after %v attempts, got: %+v", tc.provider_expected, tc.attempts_expected, r)
+			}
+
+			sent := append(xv.Sent(), yv.Sent()...)
+			if len(sent) != 1 || sent[0].ID != r.MessageID || sent[0].To != tc.req.To {
+				t.Errorf("Expected exactly the message to be delivered, got: %+v", sent)
+			}
+		})
+	}
+}
+
+func TestNotifyDeliveryFailed(t *testing.T) {
+	n, xv, yv := testNotifier(t, RetryPolicy{Attempts: 2})
+	xv.FailNext(2, nil)
+	yv.FailNext(2, nil)
+
+	r, err := n.Notify(context.Background(), Request{Type: SMS_NOTIFICATION, To: "9910825975", Body: "Hi"})
+	if !errors.Is(err, ErrDeliveryFailed) || len(r.Attempts) != 4 {
+		t.Errorf("Expected delivery failure after 4 attempts, got: %v %+v", err, r)
+	}
+}
+
+func TestNotifyTemplateContent(t *testing.T) {
+	n, xv, _ := testNotifier(t, RetryPolicy{})
+	_, err := n.Notify(context.Background(), Request{
+		Type: EMAIL_NOTIFICATION, To: "harry@gmail.com", Template: "welcome", Data: map[string]interface{}{"name": "Harry"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := xv.Sent()[0]; m.Subject != "Welcome Harry" || m.Body != "Hi Harry" {
+		t.Errorf("Unexpected content: %+v", m)
+	}
+}
+
+func TestFactoryRegister(t *testing.T) {
+	f := NewFactory()
+	if err := f.Register(SMS_NOTIFICATION, NewSMS); err == nil {
+		t.Errorf("Expected duplicate registration to fail")
+	}
+
+	push := NotificationType("PUSH")
+	if _, err := f.Create(push, RetryPolicy{}); err == nil {
+		t.Errorf("Expected unknown type to fail")
+	}
+	err := f.Register(push, func(retry RetryPolicy, providers ...Provider) Channel {
+		return NewChannel(push, nil, retry, providers...)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mem := NewMemory("fcm")
+	ch, err := f.Create(push, RetryPolicy{}, mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ch.Send(context.Background(), "device-token", Content{Body: "Hi"}); err != nil || len(mem.Sent()) != 1 {
+		t.Errorf("Expected push to be sent, got: %v", err)
+	}
+}
+
+// the provider keeps only the last MAX_MEMORY_SENT messages, oldest first
+func TestMemorySentIsBounded(t *testing.T) {
+	m := NewMemory("vendor")
+	for i := 0; i < MAX_MEMORY_SENT+10; i++ {
+		m.Send(context.Background(), Message{ID: fmt.Sprint(i)})
+	}
+	sent := m.Sent()
+	if len(sent) != MAX_MEMORY_SENT || sent[0].ID != "10" || sent[len(sent)-1].ID != fmt.Sprint(MAX_MEMORY_SENT+9) {
+		t.Errorf("Expected the last %d messages, got: %d from %v", MAX_MEMORY_SENT, len(sent), sent[0].ID)
+	}
+	if _, found := m.Find("9"); found {
+		t.Error("Expected the dropped message not to be found")
+	}
+	if _, found := m.Find("10"); !found {
+		t.Error("Expected the oldest kept message to be found")
+	}
+}
@@ -0,0 +1,62 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Request to send a notification, either Template (with Data) or the content itself
+type Request struct {
+	Type     NotificationType       `json:"type"`
+	To       string                 `json:"to"`
+	Template string                 `json:"template,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+	Subject  string                 `json:"subject,omitempty"`
+	Body     string                 `json:"body,omitempty"`
+}
+
+/*
+Notifier is the entry point of the subsystem: it renders the content and sends it
+via the channel configured for the type of the request.
+*/
+type Notifier struct {
+	Factory   *Factory
+	Templates *Templates
+
+	mulock   sync.RWMutex
+	channels map[NotificationType]Channel
+}
+
+func NewNotifier(f *Factory, t *Templates) *Notifier {
+	return &Notifier{Factory: f, Templates: t, channels: map[NotificationType]Channel{}}
+}
+
+// Configure creates (or replaces) the channel of a type, bridged with the given providers in failover order
+func (n *Notifier) Configure(nt NotificationType, retry RetryPolicy, providers ...Provider) (err error) {
+	ch, err := n.Factory.Create(nt, retry, providers...)
+	if err != nil {
+		return
+	}
+	n.mulock.Lock()
+	defer n.mulock.Unlock()
+	n.channels[nt] = ch
+	return
+}
+
+func (n *Notifier) Notify(ctx context.Context, req Request) (r Receipt, err error) {
+	n.mulock.RLock()
+	ch, ok := n.channels[req.Type]
+	n.mulock.RUnlock()
+	if !ok {
+		return r, fmt.Errorf("%w: %q", ErrNoProvider, req.Type)
+	}
+
+	c := Content{Subject: req.Subject, Body: req.Body}
+	if req.Template != "" {
+		if c, err = n.Templates.Render(req.Template, req.Data); err != nil {
+			return
+		}
+	}
+	return ch.Send(ctx, req.To, c)
+}
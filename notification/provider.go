@@ -0,0 +1,114 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+/*
+Provider is a vendor delivering the messages, ie an SMS gateway or an SMTP server.
+It returns a Permanent error if retrying the same message would fail again (ie the vendor rejected the recipient).
+*/
+type Provider interface {
+	Name() string
+	Send(ctx context.Context, m Message) error
+}
+
+type permanentError struct {
+	err error
+}
+
+func (pe *permanentError) Error() string { return pe.err.Error() }
+func (pe *permanentError) Unwrap() error { return pe.err }
+
+// Permanent marks an error as not retryable, the channel fails over to the next provider
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func IsPermanent(err error) bool {
+	var pe *permanentError
+	return errors.As(err, &pe)
+}
+
+// MAX_MEMORY_SENT messages are kept by a Memory provider, the older ones are dropped
+const MAX_MEMORY_SENT = 1000
+
+/*
+Memory provider records the sent messages instead of delivering them, so tests and examples can check delivery.
+Only the last MAX_MEMORY_SENT are kept, in a ring, as the demo providers serve the API for the life of the server.
+FailNext makes the next n sends fail, to simulate a vendor outage.
+*/
+type Memory struct {
+	name     string
+	mulock   sync.Mutex
+	sent     []Message // ring of at most MAX_MEMORY_SENT messages
+	next     int       // index of the oldest message once the ring is full
+	failNext int
+	failErr  error
+}
+
+func NewMemory(name string) *Memory {
+	return &Memory{name: name}
+}
+
+func (m *Memory) Name() string {
+	return m.name
+}
+
+func (m *Memory) Send(ctx context.Context, msg Message) (err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	m.mulock.Lock()
+	defer m.mulock.Unlock()
+	if m.failNext > 0 {
+		m.failNext--
+		if m.failErr != nil {
+			return m.failErr
+		}
+		return fmt.Errorf("%s: vendor unavailable", m.name)
+	}
+	if len(m.sent) < MAX_MEMORY_SENT {
+		m.sent = append(m.sent, msg)
+		return
+	}
+	m.sent[m.next] = msg
+	m.next = (m.next + 1) % MAX_MEMORY_SENT
+	return
+}
+
+// FailNext n sends with err, a nil err fails with a retryable error
+func (m *Memory) FailNext(n int, err error) {
+	m.mulock.Lock()
+	defer m.mulock.Unlock()
+	m.failNext = n
+	m.failErr = err
+}
+
+// Sent returns a copy of the last messages delivered, the oldest first
+func (m *Memory) Sent() []Message {
+	m.mulock.Lock()
+	defer m.mulock.Unlock()
+	return append(append([]Message{}, m.sent[m.next:]...), m.sent[:m.next]...)
+}
+
+// Find a delivered message by ID, among the last ones kept
+func (m *Memory) Find(id string) (msg Message, found bool) {
+	m.mulock.Lock()
+	defer m.mulock.Unlock()
+	for _, msg = range m.sent {
+		if msg.ID == id {
+			return msg, true
+		}
+	}
+	return Message{}, false
+}
+
+func (m *Memory) Reset() {
+	m.mulock.Lock()
+	defer m.mulock.Unlock()
+	m.sent, m.next, m.failNext, m.failErr = nil, 0, 0, nil
+}
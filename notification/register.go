@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"examples/registry"
+	"fmt"
+	"io"
+)
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "notification",
+		Title:       "Notification: Factory + Bridge",
+		Category:    registry.CATEGORY_STRUCTURAL,
+		Description: "Send templated notifications via channels bridged with vendors, with retry and failover on a vendor outage",
+		Source:      "notification/notification.go",
+		Params: []registry.Param{
+			{Name: "fail", Type: registry.PARAM_INT, Default: "2", Usage: "sends failing on xvendor before it recovers"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return ExampleNotification(ctx, w, args.Int("fail"))
+		},
+	})
+}
+
+func ExampleNotification(ctx context.Context, w io.Writer, fail int) (err error) {
+	t := NewTemplates()
+	if err = t.Register("welcome", "Welcome {{.name}}", "Hi {{.name}}, welcome aboard!"); err != nil {
+		return
+	}
+	xv, yv := NewMemory("xvendor"), NewMemory("yvendor")
+	n := NewNotifier(NewFactory(), t)
+	for _, nt := range n.Factory.Types() {
+		if err = n.Configure(nt, RetryPolicy{Attempts: 2}, xv, yv); err != nil {
+			return
+		}
+	}
+
+	// xvendor is down for the next few sends, each type retries it and then fails over to yvendor
+	xv.FailNext(fail, nil)
+	requests := []Request{
+		{Type: SMS_NOTIFICATION, To: "9910825975", Template: "welcome", Data: map[string]interface{}{"name": "Harry"}},
+		{Type: EMAIL_NOTIFICATION, To: "harry@gmail.com", Template: "welcome", Data: map[string]interface{}{"name": "Harry"}},
+		{Type: WHATSAPP_NOTIFICATION, To: "9910825975", Body: "Your order is shipped"},
+		{Type: EMAIL_NOTIFICATION, To: "not-an-email", Subject: "Hi", Body: "Hi"},
+	}
+	for _, req := range requests {
+		r, err := n.Notify(ctx, req)
+		fmt.Fprintf(w, "%v to %v: provider: %v attempts: %+v error: %v\n", req.Type, req.To, r.Provider, r.Attempts, err)
+	}
+
+	// a permanent error is not retried, it fails over straight away
+	xv.FailNext(1, Permanent(errors.New("xvendor: number is blacklisted")))
+	r, err := n.Notify(ctx, Request{Type: SMS_NOTIFICATION, To: "9910825975", Body: "Hi"})
+	fmt.Fprintf(w, "SMS with permanent error: provider: %v attempts: %+v error: %v\n", r.Provider, r.Attempts, err)
+
+	fmt.Fprintln(w, "Sent via xvendor: ", len(xv.Sent()))
+	fmt.Fprintln(w, "Sent via yvendor: ", len(yv.Sent()))
+	return nil
+}
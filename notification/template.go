@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"text/template"
+)
+
+type contentTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+/*
+Templates are text/template pairs (subject, body) registered by name.
+A missing key in the data is an error, instead of sending "<no value>" to the user.
+*/
+type Templates struct {
+	mulock    sync.RWMutex
+	templates map[string]contentTemplate
+}
+
+func NewTemplates() *Templates {
+	return &Templates{templates: map[string]contentTemplate{}}
+}
+
+// Register (or replace) a template, subject is optional
+func (t *Templates) Register(name, subject, body string) (err error) {
+	ct := contentTemplate{}
+	if ct.subject, err = template.New(name + ".subject").Option("missingkey=error").Parse(subject); err != nil {
+		return fmt.Errorf("Invalid subject template %v: %w", name, err)
+	}
+	if ct.body, err = template.New(name + ".body").Option("missingkey=error").Parse(body); err != nil {
+		return fmt.Errorf("Invalid body template %v: %w", name, err)
+	}
+
+	t.mulock.Lock()
+	defer t.mulock.Unlock()
+	t.templates[name] = ct
+	return
+}
+
+func (t *Templates) Render(name string, data map[string]interface{}) (c Content, err error) {
+	t.mulock.RLock()
+	ct, ok := t.templates[name]
+	t.mulock.RUnlock()
+	if !ok {
+		return c, fmt.Errorf("%w: unknown template %v", ErrInvalidContent, name)
+	}
+
+	buf := bytes.Buffer{}
+	if err = ct.subject.Execute(&buf, data); err != nil {
+		return c, fmt.Errorf("%w: %v", ErrInvalidContent, err)
+	}
+	c.Subject = buf.String()
+	buf.Reset()
+	if err = ct.body.Execute(&buf, data); err != nil {
+		return c, fmt.Errorf("%w: %v", ErrInvalidContent, err)
+	}
+	c.Body = buf.String()
+	return
+}
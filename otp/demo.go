@@ -0,0 +1,35 @@
+package otp
+
+import (
+	"examples/notification"
+	"sync"
+	"time"
+)
+
+var (
+	demoOnce    sync.Once
+	demoService *Service
+	demoErr     error
+)
+
+// Demo service shared by the examples and the API, it sends via the demo notifier (in-memory vendors)
+func Demo() (*Service, error) {
+	demoOnce.Do(func() {
+		n, _, _ := notification.Demo()
+		demoService, demoErr = NewService(Config{Length: 6, TTL: 2 * time.Minute, MaxAttempts: 3, Cooldown: 10 * time.Second},
+			NewMemoryStore(), n)
+	})
+	return demoService, demoErr
+}
+
+/*
+Delivered returns the message of a receipt from the demo vendors.
+Only for the demo: the vendors are in-memory, so there is no phone or inbox to read the OTP from.
+*/
+func Delivered(r notification.Receipt) (msg notification.Message, found bool) {
+	_, xvendor, yvendor := notification.Demo()
+	if msg, found = xvendor.Find(r.MessageID); !found {
+		msg, found = yvendor.Find(r.MessageID)
+	}
+	return
+}
@@ -0,0 +1,241 @@
+package otp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"examples/notification"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+/*
+OTP service, the working version of the behavioural template method example.
+Sending an OTP runs the same steps (generate, save for verification, create content, send) for every channel,
+the channel specific part (SMS/WhatsApp/Email) is left to the notification package.
+
+- Codes are generated from crypto/rand, with a configurable number of digits.
+- Only an HMAC of the code is stored, with a TTL, in a pluggable Store.
+- Verify allows MaxAttempts tries per code, and a new code can not be sent before the Cooldown.
+  The used (verified or locked) entry is kept till the end of the cooldown, so burning the attempts and
+  sending again right away is refused: at most MaxAttempts guesses per Cooldown.
+*/
+
+var (
+	ErrNotFound        = errors.New("OTP not found or expired!")
+	ErrInvalidCode     = errors.New("Invalid OTP!")
+	ErrTooManyAttempts = errors.New("Too many attempts, request a new OTP!")
+	ErrCooldown        = errors.New("OTP already sent, retry later!")
+)
+
+type Config struct {
+	Length      int           // digits in the code, 4 to 10 (default 6)
+	TTL         time.Duration // validity of a code (default 5m)
+	MaxAttempts int           // verification attempts per code (default 3)
+	Cooldown    time.Duration // min time between two sends to the same recipient (default 30s)
+	Template    string        // notification template, rendered with otp and ttl (default "otp")
+	Secret      []byte        // HMAC key of the stored codes, random if not set
+	Now         func() time.Time
+}
+
+type Service struct {
+	cfg      Config
+	store    Store
+	notifier *notification.Notifier
+}
+
+// SendResult tells when the code expires and when a new one can be requested
+type SendResult struct {
+	ExpiresAt   time.Time            `json:"expires_at"`
+	ResendAfter time.Time            `json:"resend_after"`
+	Receipt     notification.Receipt `json:"receipt"`
+}
+
+// CooldownError is returned (wrapping ErrCooldown) when an OTP is requested again too soon
+type CooldownError struct {
+	RetryAfter time.Duration
+}
+
+func (ce *CooldownError) Error() string {
+	return fmt.Sprintf("%v retry after %v", ErrCooldown, ce.RetryAfter.Round(time.Second))
+}
+
+func (ce *CooldownError) Unwrap() error { return ErrCooldown }
+
+func NewService(cfg Config, store Store, n *notification.Notifier) (s *Service, err error) {
+	if cfg.Length == 0 {
+		cfg.Length = 6
+	}
+	if cfg.Length < 4 || cfg.Length > 10 {
+		return nil, fmt.Errorf("OTP length must be 4-10 digits, got: %v", cfg.Length)
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 5 * time.Minute
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+	if cfg.Template == "" {
+		cfg.Template = "otp"
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if len(cfg.Secret) == 0 {
+		cfg.Secret = make([]byte, 32)
+		if _, err = rand.Read(cfg.Secret); err != nil {
+			return nil, fmt.Errorf("Unable to generate OTP secret: %w", err)
+		}
+	}
+	if store == nil || n == nil {
+		return nil, fmt.Errorf("OTP store and notifier are required!")
+	}
+	return &Service{cfg: cfg, store: store, notifier: n}, nil
+}
+
+/*
+iOTP has the steps of the template method, like behavioural.iOTP,
+but every step can fail and the error is returned to the caller.
+*/
+type iOTP interface {
+	generateOTP() (string, error)
+	saveOTPForVerification(ctx context.Context, code string) error
+	createOTPContent(code string) notification.Request
+	sendOTP(ctx context.Context, req notification.Request) error
+}
+
+func generateAndSendOTP(ctx context.Context, otp iOTP) (err error) {
+	code, err := otp.generateOTP()
+	if err != nil {
+		return
+	}
+	if err = otp.saveOTPForVerification(ctx, code); err != nil {
+		return
+	}
+	return otp.sendOTP(ctx, otp.createOTPContent(code))
+}
+
+/*
+Send generates a new code for the recipient and sends it via the channel, unless the previous one
+was sent less than Cooldown ago, checked and saved atomically by the store.
+*/
+func (s *Service) Send(ctx context.Context, channel notification.NotificationType, recipient string) (r SendResult, err error) {
+	flow := &otpFlow{Service: s, channel: channel, recipient: recipient, key: s.key(channel, recipient), now: s.cfg.Now()}
+	if err = generateAndSendOTP(ctx, flow); err != nil {
+		return
+	}
+	return SendResult{ExpiresAt: flow.entry.ExpiresAt, ResendAfter: flow.now.Add(s.cfg.Cooldown), Receipt: flow.receipt}, nil
+}
+
+/*
+Verify checks the code sent to the recipient, a verified code can not be used again.
+Every call counts as an attempt, after MaxAttempts the code is locked. Either way the entry is
+marked used rather than deleted, so the cooldown of Send still applies.
+*/
+func (s *Service) Verify(ctx context.Context, channel notification.NotificationType, recipient, code string) (err error) {
+	key := s.key(channel, recipient)
+	e, found, err := s.store.Load(ctx, key)
+	if err != nil {
+		return
+	}
+	if !found || e.Used || !s.cfg.Now().Before(e.ExpiresAt) {
+		return ErrNotFound
+	}
+
+	attempts, err := s.store.IncrAttempts(ctx, key)
+	if err != nil {
+		return
+	}
+	if attempts > s.cfg.MaxAttempts {
+		s.store.MarkUsed(ctx, key)
+		return ErrTooManyAttempts
+	}
+
+	if !hmac.Equal(e.Hash, s.hash(key, code)) {
+		if attempts == s.cfg.MaxAttempts {
+			s.store.MarkUsed(ctx, key)
+			return ErrTooManyAttempts
+		}
+		return fmt.Errorf("%w %d attempt(s) left", ErrInvalidCode, s.cfg.MaxAttempts-attempts)
+	}
+	// only the first of concurrent verifications of the right code uses it
+	used, err := s.store.MarkUsed(ctx, key)
+	if err == nil && used {
+		err = ErrNotFound
+	}
+	return
+}
+
+func (s *Service) key(channel notification.NotificationType, recipient string) string {
+	return string(channel) + ":" + recipient
+}
+
+// hash binds the code to the key, so the same code of another recipient has another hash
+func (s *Service) hash(key, code string) []byte {
+	mac := hmac.New(sha256.New, s.cfg.Secret)
+	mac.Write([]byte(key))
+	mac.Write([]byte{0})
+	mac.Write([]byte(code))
+	return mac.Sum(nil)
+}
+
+// otpFlow implements the iOTP steps for one Send
+type otpFlow struct {
+	*Service
+	channel   notification.NotificationType
+	recipient string
+	key       string
+	now       time.Time
+
+	entry   Entry
+	receipt notification.Receipt
+}
+
+func (f *otpFlow) generateOTP() (code string, err error) {
+	digits := make([]byte, f.cfg.Length)
+	ten := big.NewInt(10)
+	for i := range digits {
+		n, err := rand.Int(rand.Reader, ten)
+		if err != nil {
+			return "", fmt.Errorf("Unable to generate OTP: %w", err)
+		}
+		digits[i] = byte('0' + n.Int64())
+	}
+	return string(digits), nil
+}
+
+// saveOTPForVerification fails with a CooldownError if the previous code was sent less than Cooldown ago
+func (f *otpFlow) saveOTPForVerification(ctx context.Context, code string) (err error) {
+	f.entry = Entry{Hash: f.hash(f.key, code), SentAt: f.now, ExpiresAt: f.now.Add(f.cfg.TTL), KeepUntil: f.now.Add(f.cfg.TTL)}
+	if resendAt := f.now.Add(f.cfg.Cooldown); resendAt.After(f.entry.KeepUntil) {
+		f.entry.KeepUntil = resendAt
+	}
+	prev, saved, err := f.store.SaveUnlessSentAfter(ctx, f.key, f.entry, f.now.Add(-f.cfg.Cooldown))
+	if err != nil || saved {
+		return
+	}
+	return &CooldownError{RetryAfter: prev.SentAt.Add(f.cfg.Cooldown).Sub(f.now)}
+}
+
+func (f *otpFlow) createOTPContent(code string) notification.Request {
+	return notification.Request{
+		Type:     f.channel,
+		To:       f.recipient,
+		Template: f.cfg.Template,
+		Data:     map[string]interface{}{"otp": code, "ttl": f.cfg.TTL.String()},
+	}
+}
+
+// sendOTP discards the saved code if it could not be sent, so that it can be requested again without the cooldown
+func (f *otpFlow) sendOTP(ctx context.Context, req notification.Request) (err error) {
+	if f.receipt, err = f.notifier.Notify(ctx, req); err != nil {
+		f.store.Delete(ctx, f.key)
+	}
+	return
+}
@@ -0,0 +1,260 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"examples/notification"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	mulock sync.Mutex
+	now    time.Time
+}
+
+func (tc *testClock) Now() time.Time {
+	tc.mulock.Lock()
+	defer tc.mulock.Unlock()
+	return tc.now
+}
+
+func (tc *testClock) Add(d time.Duration) {
+	tc.mulock.Lock()
+	defer tc.mulock.Unlock()
+	tc.now = tc.now.Add(d)
+}
+
+func testService(t *testing.T, cfg Config) (s *Service, vendor *notification.Memory, clock *testClock) {
+	tmpl := notification.NewTemplates()
+	tmpl.Register("otp", "Your OTP", "{{.otp}} is your OTP")
+	vendor = notification.NewMemory("vendor")
+	n := notification.NewNotifier(notification.NewFactory(), tmpl)
+	n.Configure(notification.SMS_NOTIFICATION, notification.RetryPolicy{}, vendor)
+
+	clock = &testClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cfg.Now = clock.Now
+	store := NewMemoryStore()
+	store.now = clock.Now
+	s, err := NewService(cfg, store, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func sentCode(t *testing.T, vendor *notification.Memory) string {
+	sent := vendor.Sent()
+	if len(sent) == 0 {
+		t.Fatal("No OTP sent")
+	}
+	return strings.Fields(sent[len(sent)-1].Body)[0]
+}
+
+func TestGenerateOTP(t *testing.T) {
+	for _, length := range []int{4, 6, 10} {
+		s, _, _ := testService(t, Config{Length: length})
+		f := &otpFlow{Service: s}
+		seen := map[string]bool{}
+		for i := 0; i < 20; i++ {
+			code, err := f.generateOTP()
+			if err != nil || len(code) != length || strings.Trim(code, "0123456789") != "" {
+				t.Fatalf("Invalid code %q: %v", code, err)
+			}
+			seen[code] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("Expected random codes, got: %v", seen)
+		}
+	}
+
+	if _, err := NewService(Config{Length: 3}, NewMemoryStore(), &notification.Notifier{}); err == nil {
+		t.Errorf("Expected invalid length to fail")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		name         string
+		wrong        int           // wrong codes before the right one
+		wait         time.Duration // wait before verifying
+		err_expected error
+	}{
+		{name: "Valid-TC-1"},
+		{name: "WrongThenValid-TC-2", wrong: 2},
+		// the code is discarded by the 3rd wrong attempt
+		{name: "TooManyAttempts-TC-3", wrong: 3, err_expected: ErrNotFound},
+		{name: "Expired-TC-4", wait: 6 * time.Minute, err_expected: ErrNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, vendor, clock := testService(t, Config{MaxAttempts: 3})
+			if _, err := s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975"); err != nil {
+				t.Fatal(err)
+			}
+			code := sentCode(t, vendor)
+			clock.Add(tc.wait)
+
+			for i := 0; i < tc.wrong; i++ {
+				wrong := "000000"
+				if code == wrong {
+					wrong = "111111"
+				}
+				err := s.Verify(ctx, notification.SMS_NOTIFICATION, "9910825975", wrong)
+				if i == 2 && !errors.Is(err, ErrTooManyAttempts) {
+					t.Fatalf("Expected ErrTooManyAttempts on the last attempt, got: %v", err)
+				} else if i < 2 && !errors.Is(err, ErrInvalidCode) {
+					t.Fatalf("Expected ErrInvalidCode, got: %v", err)
+				}
+			}
+
+			err := s.Verify(ctx, notification.SMS_NOTIFICATION, "9910825975", code)
+			if !errors.Is(err, tc.err_expected) {
+				t.Errorf("Expected: %v, got: %v", tc.err_expected, err)
+			}
+			// a code is never accepted twice
+			if err = s.Verify(ctx, notification.SMS_NOTIFICATION, "9910825975", code); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected ErrNotFound on reuse, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyOtherRecipient(t *testing.T) {
+	ctx := context.Background()
+	s, vendor, _ := testService(t, Config{})
+	s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975")
+	s.Send(ctx, notification.SMS_NOTIFICATION, "9910825976")
+	first, second := strings.Fields(vendor.Sent()[0].Body)[0], sentCode(t, vendor)
+	if first == second {
+		t.Skip("Both recipients got the same code")
+	}
+
+	if err := s.Verify(ctx, notification.SMS_NOTIFICATION, "9910825975", second); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Expected the code of another recipient to fail, got: %v", err)
+	}
+}
+
+func TestSendCooldown(t *testing.T) {
+	ctx := context.Background()
+	s, vendor, clock := testService(t, Config{Cooldown: 30 * time.Second})
+
+	if _, err := s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975"); err != nil {
+		t.Fatal(err)
+	}
+	clock.Add(10 * time.Second)
+	_, err := s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975")
+	var ce *CooldownError
+	if !errors.As(err, &ce) || !errors.Is(err, ErrCooldown) || ce.RetryAfter != 20*time.Second {
+		t.Errorf("Expected cooldown of 20s, got: %v", err)
+	}
+
+	clock.Add(20 * time.Second)
+	if _, err = s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975"); err != nil {
+		t.Errorf("Expected resend after the cooldown, got: %v", err)
+	}
+	if len(vendor.Sent()) != 2 {
+		t.Errorf("Expected 2 OTPs sent, got: %v", len(vendor.Sent()))
+	}
+}
+
+func TestSendFailure(t *testing.T) {
+	ctx := context.Background()
+	s, vendor, _ := testService(t, Config{})
+	vendor.FailNext(1, nil)
+
+	if _, err := s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975"); !errors.Is(err, notification.ErrDeliveryFailed) {
+		t.Fatalf("Expected delivery failure, got: %v", err)
+	}
+	// the code was not delivered, so there is neither a code to verify nor a cooldown
+	if _, err := s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975"); err != nil {
+		t.Errorf("Expected resend to succeed, got: %v", err)
+	}
+}
+
+func TestVerifyConcurrent(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := testService(t, Config{MaxAttempts: 3})
+	s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975")
+
+	// the attempts cap holds even when the wrong codes are tried concurrently
+	var mulock sync.Mutex
+	invalid := 0
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Verify(ctx, notification.SMS_NOTIFICATION, "9910825975", "abc"); errors.Is(err, ErrInvalidCode) {
+				mulock.Lock()
+				invalid++
+				mulock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if invalid > 2 {
+		t.Errorf("Expected at most 2 invalid code errors before the cap, got: %v", invalid)
+	}
+}
+
+// burning the attempts does not reset the cooldown, ie a new code and new attempts right away
+func TestSendAfterTooManyAttempts(t *testing.T) {
+	ctx := context.Background()
+	s, vendor, clock := testService(t, Config{MaxAttempts: 3, Cooldown: 30 * time.Second})
+	if _, err := s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975"); err != nil {
+		t.Fatal(err)
+	}
+	code := sentCode(t, vendor)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	for i := 0; i < 3; i++ {
+		s.Verify(ctx, notification.SMS_NOTIFICATION, "9910825975", wrong)
+	}
+
+	clock.Add(time.Second)
+	if _, err := s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975"); !errors.Is(err, ErrCooldown) {
+		t.Errorf("Expected: %v after the attempts, got: %v", ErrCooldown, err)
+	}
+	if err := s.Verify(ctx, notification.SMS_NOTIFICATION, "9910825975", code); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the locked code to be refused, got: %v", err)
+	}
+
+	clock.Add(29 * time.Second)
+	if _, err := s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975"); err != nil {
+		t.Errorf("Expected resend after the cooldown, got: %v", err)
+	}
+}
+
+func TestSendConcurrent(t *testing.T) {
+	ctx := context.Background()
+	s, vendor, _ := testService(t, Config{})
+
+	// only one of the concurrent sends passes the cooldown
+	var mulock sync.Mutex
+	sent, cooldown := 0, 0
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.Send(ctx, notification.SMS_NOTIFICATION, "9910825975")
+			mulock.Lock()
+			defer mulock.Unlock()
+			if err == nil {
+				sent++
+			} else if errors.Is(err, ErrCooldown) {
+				cooldown++
+			}
+		}()
+	}
+	wg.Wait()
+	if sent != 1 || cooldown != 19 || len(vendor.Sent()) != 1 {
+		t.Errorf("Expected 1 OTP sent and 19 cooldowns, got: %d sent, %d cooldowns, %d delivered", sent, cooldown, len(vendor.Sent()))
+	}
+}
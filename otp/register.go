@@ -0,0 +1,55 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"examples/notification"
+	"examples/registry"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "otp",
+		Title:       "OTP Service",
+		Category:    registry.CATEGORY_BEHAVIOURAL,
+		Description: "Send an OTP following the template method steps, verify it with an attempts cap and a resend cooldown",
+		Source:      "otp/otp.go",
+		Params: []registry.Param{
+			{Name: "to", Type: registry.PARAM_STRING, Default: "9910825975", Usage: "mobile number the OTP is sent to"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return ExampleOTP(ctx, w, args.String("to"))
+		},
+	})
+}
+
+func ExampleOTP(ctx context.Context, w io.Writer, to string) (err error) {
+	n, _, _ := notification.Demo()
+	// a new store on every run, so that the cooldown of the previous run does not apply
+	s, err := NewService(Config{Length: 6, MaxAttempts: 3}, NewMemoryStore(), n)
+	if err != nil {
+		return
+	}
+
+	r, err := s.Send(ctx, notification.SMS_NOTIFICATION, to)
+	if errors.Is(err, notification.ErrInvalidRecipient) {
+		return registry.InvalidArgs(err)
+	}
+	if err != nil {
+		return
+	}
+	msg, _ := Delivered(r.Receipt)
+	fmt.Fprintf(w, "Sent via %v: %q\n", r.Receipt.Provider, msg.Body)
+	code := strings.Fields(msg.Body)[0]
+
+	_, err = s.Send(ctx, notification.SMS_NOTIFICATION, to)
+	fmt.Fprintln(w, "Send again: ", err)
+
+	fmt.Fprintln(w, "Verify 000000: ", s.Verify(ctx, notification.SMS_NOTIFICATION, to, "000000"))
+	fmt.Fprintf(w, "Verify %v: %v\n", code, s.Verify(ctx, notification.SMS_NOTIFICATION, to, code))
+	fmt.Fprintf(w, "Verify %v again: %v\n", code, s.Verify(ctx, notification.SMS_NOTIFICATION, to, code))
+	return nil
+}
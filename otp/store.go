@@ -0,0 +1,113 @@
+package otp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*
+Entry stored per channel + recipient, only the hash of the code is kept.
+A used entry (verified, or locked after MaxAttempts) is a tombstone: its code is never accepted again,
+but it is kept till KeepUntil so that the cooldown still applies to the next Send.
+*/
+type Entry struct {
+	Hash      []byte
+	SentAt    time.Time
+	ExpiresAt time.Time // of the code
+	KeepUntil time.Time // of the entry, the later of ExpiresAt and the end of the cooldown
+	Attempts  int
+	Used      bool
+}
+
+/*
+Store keeps the OTP entries till KeepUntil. It is pluggable, ie Redis with a key TTL:
+SaveUnlessSentAfter is a Lua script (or WATCH/MULTI) checking the SentAt of the entry before the SET with expiry,
+IncrAttempts is HINCRBY and MarkUsed is HSETNX, so concurrent sends can not both pass the cooldown, concurrent
+verifications can not exceed the attempts cap and a code is used only once.
+*/
+type Store interface {
+	// SaveUnlessSentAfter atomically saves e unless the entry kept was sent after the given time, returned then in prev
+	SaveUnlessSentAfter(ctx context.Context, key string, e Entry, after time.Time) (prev Entry, saved bool, err error)
+	// Load returns found false if there is no entry or it is past KeepUntil
+	Load(ctx context.Context, key string) (e Entry, found bool, err error)
+	// IncrAttempts atomically increments and returns the verification attempts of the entry
+	IncrAttempts(ctx context.Context, key string) (attempts int, err error)
+	// MarkUsed atomically marks the entry as used, used is true if it already was (or is gone)
+	MarkUsed(ctx context.Context, key string) (used bool, err error)
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryStore drops the entries past KeepUntil lazily, when they are loaded or on the next save
+type MemoryStore struct {
+	mulock  sync.Mutex
+	entries map[string]Entry
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]Entry{}, now: time.Now}
+}
+
+func (ms *MemoryStore) SaveUnlessSentAfter(ctx context.Context, key string, e Entry, after time.Time) (prev Entry, saved bool, err error) {
+	ms.mulock.Lock()
+	defer ms.mulock.Unlock()
+	now := ms.now()
+	for k, old := range ms.entries {
+		if !now.Before(old.KeepUntil) {
+			delete(ms.entries, k)
+		}
+	}
+	if old, ok := ms.entries[key]; ok && old.SentAt.After(after) {
+		return old, false, nil
+	}
+	ms.entries[key] = e
+	return e, true, nil
+}
+
+func (ms *MemoryStore) Load(ctx context.Context, key string) (e Entry, found bool, err error) {
+	ms.mulock.Lock()
+	defer ms.mulock.Unlock()
+	if e, found = ms.entries[key]; found && !ms.now().Before(e.KeepUntil) {
+		delete(ms.entries, key)
+		return Entry{}, false, nil
+	}
+	return
+}
+
+func (ms *MemoryStore) IncrAttempts(ctx context.Context, key string) (attempts int, err error) {
+	ms.mulock.Lock()
+	defer ms.mulock.Unlock()
+	e, ok := ms.entries[key]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	e.Attempts++
+	ms.entries[key] = e
+	return e.Attempts, nil
+}
+
+func (ms *MemoryStore) MarkUsed(ctx context.Context, key string) (used bool, err error) {
+	ms.mulock.Lock()
+	defer ms.mulock.Unlock()
+	e, ok := ms.entries[key]
+	if !ok || e.Used {
+		return true, nil
+	}
+	e.Used = true
+	ms.entries[key] = e
+	return false, nil
+}
+
+func (ms *MemoryStore) Delete(ctx context.Context, key string) error {
+	ms.mulock.Lock()
+	defer ms.mulock.Unlock()
+	delete(ms.entries, key)
+	return nil
+}
+
+func (ms *MemoryStore) Len() int {
+	ms.mulock.Lock()
+	defer ms.mulock.Unlock()
+	return len(ms.entries)
+}
@@ -1,7 +1,13 @@
 package behavioural
 
 import (
+	"examples/data-structure/linklist"
+	"examples/data-structure/stack"
+	"examples/data-structure/tree"
+	"examples/iterator"
 	"fmt"
+	"io"
+	"strings"
 )
 
 /*
@@ -10,6 +16,9 @@ We want to create an iterator for collection of user struct.
 The main idea behind this pattern is to expose the iteration logic of a Collection struct into a different
 object (which implements the iterator interface). This iterator provides a generic method of iterating over a
 collection independent of its type.
+
+The collection implements iterator.Iterable[*user] and createIterator returns an iterator.Iterator[*user],
+so that the same iterator interface and combinators work for users, the data structures, slices, maps and channels.
 */
 type user struct {
 	name string
@@ -27,43 +36,61 @@ type UserIterator struct {
 	users []*user
 }
 
-type iterator interface {
-	hasNext() bool
-	getNext() *user
-}
-
-type collection interface {
-	createIterator() iterator
-}
-
-func (ui *UserIterator) hasNext() bool {
-	if ui.index < len(ui.users) { // ui.users[ui.index] != nil {
-		return true
-	}
-	return false
-}
-func (ui *UserIterator) getNext() (u *user) {
-	if ui.hasNext() { //ui.users[ui.index] != nil {
+func (ui *UserIterator) Next() (u *user, ok bool) {
+	if ui.index < len(ui.users) {
 		u = ui.users[ui.index]
 		ui.index++
+		return u, true
 	}
 	return
 }
 
-func (uc *UserCollection) createIterator() (i iterator) {
-	i = &UserIterator{users: uc.users}
-	return
+func (uc *UserCollection) Iter() iterator.Iterator[*user] {
+	return &UserIterator{users: uc.users}
 }
 
-func ExecuteIterator() {
+func ExecuteIterator(w io.Writer) {
 	user1 := &user{"Harry", 41}
 	user2 := &user{"Garry", 42}
+	user3 := &user{"Barry", 43}
 
-	userCollection := &UserCollection{users: []*user{user1, user2}}
-	iterateUsers := userCollection.createIterator()
-	fmt.Println("Iterate Users")
-	for iterateUsers.hasNext() {
-		u := iterateUsers.getNext()
-		fmt.Println(u)
+	userCollection := &UserCollection{users: []*user{user1, user2, user3}}
+	iterateUsers := userCollection.Iter()
+	fmt.Fprintln(w, "Iterate Users")
+	for u, ok := iterateUsers.Next(); ok; u, ok = iterateUsers.Next() {
+		fmt.Fprintln(w, u)
 	}
+
+	// combinators
+	names := iterator.Map(
+		iterator.Filter(userCollection.Iter(), func(u *user) bool { return u.age > 41 }),
+		func(u *user) string { return strings.ToUpper(u.name) })
+	fmt.Fprintln(w, "Users older than 41: ", iterator.Collect(names))
+
+	ranks := iterator.Zip(iterator.FromSlice([]int{1, 2, 3}), userCollection.Iter())
+	iterator.ForEach(ranks, func(p iterator.Pair[int, *user]) { fmt.Fprintf(w, "Rank %d: %s\n", p.First, p.Second.name) })
+
+	// the data structures are iterated the same way
+	s := stack.InitStack[string](0)
+	ll := linklist.InitList[string]()
+	ld := linklist.InitListDouble[string]()
+	for _, v := range []string{"a", "b", "c"} {
+		s.Push(v)
+		ll.AddBack(v)
+		ld.AddBack(v)
+	}
+	fmt.Fprintln(w, "Stack: ", iterator.Collect(s.Iter()))
+	fmt.Fprintln(w, "Linklist + Doubly reverse: ", iterator.Collect(iterator.Chain(ll.Iter(), ld.IterReverse())))
+
+	t := &tree.Bst{}
+	for _, v := range []int{5, 3, 2, 4, 1, 7, 6, 8, 9} {
+		t.InsertNode(v)
+	}
+	fmt.Fprintln(w, "Tree InOrder: ", iterator.Collect(t.InOrder()))
+	fmt.Fprintln(w, "Tree PreOrder: ", iterator.Collect(t.PreOrder()))
+	fmt.Fprintln(w, "Tree PostOrder: ", iterator.Collect(t.PostOrder()))
+	fmt.Fprintln(w, "Tree LevelOrder, first 4: ", iterator.Collect(iterator.Take(t.LevelOrder(), 4)))
+
+	ages := iterator.FromMapSorted(map[string]int{"Harry": 41, "Garry": 42})
+	iterator.ForEach(ages, func(p iterator.Pair[string, int]) { fmt.Fprintf(w, "%s is %d\n", p.First, p.Second) })
 }
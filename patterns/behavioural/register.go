@@ -0,0 +1,22 @@
+package behavioural
+
+import "examples/registry"
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "pattern/behavioural/template-method",
+		Title:       "Template Method",
+		Category:    registry.CATEGORY_BEHAVIOURAL,
+		Description: "Generate, save and send an OTP via SMS/Email following the same set of steps",
+		Source:      "patterns/behavioural/template_method.go",
+		Run:         registry.Simple(ExecuteTemplateMethod),
+	})
+	registry.Register(registry.Example{
+		Name:        "pattern/behavioural/iterator",
+		Title:       "Iterator",
+		Category:    registry.CATEGORY_BEHAVIOURAL,
+		Description: "Generic iterators and combinators over users, stack, linked lists, BST orders and maps",
+		Source:      "patterns/behavioural/iterator.go",
+		Run:         registry.Simple(ExecuteIterator),
+	})
+}
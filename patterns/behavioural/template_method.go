@@ -2,6 +2,7 @@ package behavioural
 
 import (
 	"fmt"
+	"io"
 )
 
 /*
@@ -9,6 +10,7 @@ Template Method is a beharioural design pattern.
 If we have a set of steps or algorithm, which can be implemented by any entity in similar way, then we can use this pattern.
 
 For instance, OTP generation/verification related implementation via SMS, Email, Whatsapp or Push Notification can use this.
+See package otp for a working OTP service built on the same steps.
 
 */
 
@@ -25,62 +27,63 @@ func generateAndSendOTP(otp iOTP) (err error) {
 		return
 	}
 	content := otp.createOTPContent(otpVal)
-	otp.sendOTP(content)
-	return
+	return otp.sendOTP(content)
 }
 
 type SMS struct {
 	mobile string
+	out    io.Writer
 }
 type Email struct {
 	emailID string
+	out     io.Writer
 }
 
 func (s *SMS) generateOTP() (otp int) {
 	otp = 2369
-	fmt.Println("SMS: Generate random OTP : ", otp)
+	fmt.Fprintln(s.out, "SMS: Generate random OTP : ", otp)
 	return
 }
 
 func (s *SMS) saveOTPForVerification(otp int) (err error) {
-	fmt.Println("SMS: Save OTP in cache/DB")
+	fmt.Fprintln(s.out, "SMS: Save OTP in cache/DB")
 	return
 }
 
 func (s *SMS) createOTPContent(otp int) (content string) {
-	fmt.Println("SMS: create content to be sent")
+	fmt.Fprintln(s.out, "SMS: create content to be sent")
 	content = "SMS OTP content"
 	return
 }
 func (s *SMS) sendOTP(content string) (err error) {
-	fmt.Println("SMS: OTP sent successfully to ", s.mobile)
+	fmt.Fprintln(s.out, "SMS: OTP sent successfully to ", s.mobile)
 	return
 }
 
 func (s *Email) generateOTP() (otp int) {
 	otp = 2369
-	fmt.Println("Email: Generate random OTP : ", otp)
+	fmt.Fprintln(s.out, "Email: Generate random OTP : ", otp)
 	return
 }
 
 func (s *Email) saveOTPForVerification(otp int) (err error) {
-	fmt.Println("Email: Save OTP in cache/DB")
+	fmt.Fprintln(s.out, "Email: Save OTP in cache/DB")
 	return
 }
 
 func (s *Email) createOTPContent(otp int) (content string) {
-	fmt.Println("Email: create content to be sent")
+	fmt.Fprintln(s.out, "Email: create content to be sent")
 	content = "Email OTP content"
 	return
 }
 func (s *Email) sendOTP(content string) (err error) {
-	fmt.Println("Email: OTP sent successfully to ", s.emailID)
+	fmt.Fprintln(s.out, "Email: OTP sent successfully to ", s.emailID)
 	return
 }
 
-func ExecuteTemplateMethod() {
-	sms := &SMS{mobile: "9910825975"}
-	email := &Email{emailID: "harry@gmail.com"}
+func ExecuteTemplateMethod(w io.Writer) {
+	sms := &SMS{mobile: "9910825975", out: w}
+	email := &Email{emailID: "harry@gmail.com", out: w}
 
 	generateAndSendOTP(sms)
 	generateAndSendOTP(email)
@@ -2,6 +2,10 @@ package creational
 
 import (
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,34 +16,72 @@ const (
 	ORG_MAKEMYTRIP = "makemytrip"
 )
 
-type iBookingAbstractFactory interface {
-	bookFlight() iFlight
-	bookTrain() iTrain
+/*
+Every booking org (ixigo/makemytrip) is a factory of a family of products: its own flight and train bookings.
+The BookingEngine (booking.go) only talks to this interface, so adding a provider only needs
+a new factory registered via RegisterBookingFactory, from any package: its bookings embed the
+Flight & Train built by NewFlight & NewTrain to implement IFlight & ITrain.
+*/
+type BookingAbstractFactory interface {
+	BookFlight(route Route, passengers []Passenger) IFlight
+	BookTrain(route Route, passengers []Passenger) ITrain
+	FareRule(mode string) FareRule
+}
+
+var (
+	bookingFactories = map[string]BookingAbstractFactory{}
+	factoriesLock    sync.RWMutex
+)
+
+// RegisterBookingFactory adds the org, it panics if the org is already registered
+func RegisterBookingFactory(orgName string, baf BookingAbstractFactory) {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	if _, ok := bookingFactories[orgName]; ok {
+		panic("Booking org already registered: " + orgName)
+	}
+	bookingFactories[orgName] = baf
+}
+
+func init() {
+	RegisterBookingFactory(ORG_IXIGO, &ixigo{})
+	RegisterBookingFactory(ORG_MAKEMYTRIP, &makemytrip{})
+}
+
+func getBookingFactory(orgName string) (baf BookingAbstractFactory, err error) {
+	factoriesLock.RLock()
+	defer factoriesLock.RUnlock()
+	baf, ok := bookingFactories[strings.ToLower(orgName)]
+	if !ok {
+		err = fmt.Errorf("Booking org not defined: %q", orgName)
+	}
+	return
 }
 
-func getBookingFactory(orgName string) (baf iBookingAbstractFactory, err error) {
-	switch orgName {
-	case ORG_IXIGO:
-		baf = &ixigo{}
-	case ORG_MAKEMYTRIP:
-		baf = &makemytrip{}
-	default:
-		err = fmt.Errorf("Booking org not defined!")
+// BookingOrgs registered, sorted by name
+func BookingOrgs() (orgs []string) {
+	factoriesLock.RLock()
+	defer factoriesLock.RUnlock()
+	for org := range bookingFactories {
+		orgs = append(orgs, org)
 	}
+	sort.Strings(orgs)
 	return
 }
 
-type iFlight interface {
+type IFlight interface {
 	setFlight(pnr string)
 	getFlight() (pnr string)
+	getDetails() map[string]interface{}
 }
-type iTrain interface {
+type ITrain interface {
 	setTrain(pnr string)
 	getTrain() (pnr string)
+	getDetails() map[string]interface{}
 }
 
 /*
-Implement iFlight & iTrain interfaces by Flight & Train concrete struct types
+Implement IFlight & ITrain interfaces by Flight & Train concrete struct types
 */
 type Flight struct {
 	pnr                string
@@ -65,6 +107,18 @@ func (f *Flight) getFlight() (pnr string) {
 	return
 }
 
+func (f *Flight) getDetails() (details map[string]interface{}) {
+	details = map[string]interface{}{
+		"travel_date":         f.travelDate,
+		"source_airport":      f.sourceAirport,
+		"destination_airport": f.destinationAirport,
+	}
+	for k, v := range f.flightDetails {
+		details[k] = v
+	}
+	return
+}
+
 func (t *Train) setTrain(pnr string) {
 	t.pnr = pnr
 }
@@ -73,6 +127,45 @@ func (t *Train) getTrain() (pnr string) {
 	return
 }
 
+func (t *Train) getDetails() (details map[string]interface{}) {
+	details = map[string]interface{}{
+		"travel_date":         t.travelDate,
+		"source_station":      t.sourceStation,
+		"destination_station": t.destinationStation,
+	}
+	for k, v := range t.trainDetails {
+		details[k] = v
+	}
+	return
+}
+
+// NewFlight booked on the route, with a new PNR starting by pnrPrefix
+func NewFlight(pnrPrefix string, route Route, passengers []Passenger) Flight {
+	return Flight{
+		pnr:                newPNR(pnrPrefix),
+		travelDate:         route.Departure,
+		sourceAirport:      route.Source,
+		destinationAirport: route.Destination,
+		flightDetails:      map[string]interface{}{"flight": route.ID, "airline": route.Carrier, "passengers": len(passengers)},
+	}
+}
+
+// NewTrain booked on the route, with a new PNR starting by pnrPrefix
+func NewTrain(pnrPrefix string, route Route, passengers []Passenger) Train {
+	return Train{
+		pnr:                newPNR(pnrPrefix),
+		travelDate:         route.Departure,
+		sourceStation:      route.Source,
+		destinationStation: route.Destination,
+		trainDetails:       map[string]interface{}{"train": route.ID, "name": route.Carrier, "passengers": len(passengers)},
+	}
+}
+
+func newPNR(prefix string) string {
+	id, _ := uuid.NewRandom()
+	return prefix + strings.ToUpper(strings.ReplaceAll(id.String(), "-", "")[:10])
+}
+
 /*
 struct embedding: Flight & Train
 Concrete types for ixigo/makemytrip bookings - train & flights
@@ -96,68 +189,91 @@ type makemytripTrain struct {
 Implement BookingAbstractFactory interface by ixigo & makemytrip concrete struct types
 */
 type ixigo struct {
-	//Flight *Flight
-	//Train  *Train
 }
 
 type makemytrip struct {
-	//Flight *Flight
-	//Train  *Train
 }
 
-func (i *ixigo) bookFlight() iFlight {
-	id, _ := uuid.NewUUID()
-	return &ixigoFlight{Flight{pnr: "IXI-F" + id.String()}}
-
-	//i.Flight = &Flight{}
-	//return i.Flight
+func (i *ixigo) BookFlight(route Route, passengers []Passenger) IFlight {
+	return &ixigoFlight{NewFlight("IXI-F", route, passengers)}
 }
 
-func (i *ixigo) bookTrain() iTrain {
-
-	id, _ := uuid.NewRandom()
-	return &ixigoTrain{Train{pnr: "IXI-T" + id.String()}}
-
-	//i.Train = &Train{}
-	//return i.Train
+func (i *ixigo) BookTrain(route Route, passengers []Passenger) ITrain {
+	return &ixigoTrain{NewTrain("IXI-T", route, passengers)}
 }
 
-func (i *makemytrip) bookFlight() iFlight {
-	id, _ := uuid.NewUUID()
-	return &makemytripFlight{Flight{pnr: "MMT-F" + id.String()}}
+// ixigo: no markup, low convenience fee, free cancellation till 24 hours (flights) or 48 hours (trains) before departure
+func (i *ixigo) FareRule(mode string) FareRule {
+	if mode == MODE_TRAIN {
+		return FareRule{ConvenienceFee: 15_00, CancellationFee: 60_00, FreeCancellationHours: 48}
+	}
+	return FareRule{ConvenienceFee: 99_00, CancellationFee: 1500_00, FreeCancellationHours: 24}
+}
 
-	//i.Flight = &Flight{}
-	//return i.Flight
+func (i *makemytrip) BookFlight(route Route, passengers []Passenger) IFlight {
+	return &makemytripFlight{NewFlight("MMT-F", route, passengers)}
 }
 
-func (i *makemytrip) bookTrain() iTrain {
+func (i *makemytrip) BookTrain(route Route, passengers []Passenger) ITrain {
+	return &makemytripTrain{NewTrain("MMT-T", route, passengers)}
+}
 
-	id, _ := uuid.NewRandom()
-	return &makemytripTrain{Train{pnr: "MMT-T" + id.String()}}
-	//i.Train = &Train{}
-	//return i.Train
+// makemytrip: 2% markup, no convenience fee, cancellation fee always applies
+func (i *makemytrip) FareRule(mode string) FareRule {
+	if mode == MODE_TRAIN {
+		return FareRule{Markup: 200, CancellationFee: 120_00}
+	}
+	return FareRule{Markup: 200, CancellationFee: 2000_00}
 }
 
-func ExecuteAbstractFactory() {
-	ix, _ := getBookingFactory(ORG_IXIGO)
-	//ix := &ixigo{}
-	ixF := ix.bookFlight()
-	PrintFlightDetails(ixF)
-	ixT := ix.bookTrain()
-	PrintTrainDetails(ixT)
+func ExecuteAbstractFactory(w io.Writer) {
+	// a new engine on every run, with the demo inventory
+	e := NewBookingEngine(DemoRoutes(time.Now()))
 
-	mmt, _ := getBookingFactory(ORG_MAKEMYTRIP)
-	//mmt := &makemytrip{}
-	mmtF := mmt.bookFlight()
-	PrintFlightDetails(mmtF)
-	mmtT := mmt.bookTrain()
-	PrintTrainDetails(mmtT)
+	requests := []BookingRequest{
+		{Org: ORG_IXIGO, RouteID: "6E-201", Passengers: []Passenger{{Name: "Harry", Age: 41}, {Name: "Garry", Age: 12}}},
+		{Org: ORG_IXIGO, RouteID: "12951", Passengers: []Passenger{{Name: "Harry", Age: 41}}},
+		{Org: ORG_MAKEMYTRIP, RouteID: "6E-201", Passengers: []Passenger{{Name: "Barry", Age: 35}}},
+		{Org: ORG_MAKEMYTRIP, RouteID: "G8-101", Passengers: []Passenger{{Name: "A", Age: 30}, {Name: "B", Age: 30}, {Name: "C", Age: 30}}},
+		{Org: ORG_MAKEMYTRIP, RouteID: "G8-101", Passengers: []Passenger{{Name: "D", Age: 30}}},
+		{Org: "cleartrip", RouteID: "6E-201", Passengers: []Passenger{{Name: "E", Age: 30}}},
+	}
+	pnrs := []string{}
+	for _, req := range requests {
+		b, err := e.Book(req)
+		if err != nil {
+			fmt.Fprintf(w, "%v %v: %v\n", req.Org, req.RouteID, err)
+			continue
+		}
+		pnrs = append(pnrs, b.PNR)
+		fmt.Fprintf(w, "%v %v: PNR %v, %v %v -> %v, %d passenger(s), fare: %v\n",
+			b.Org, b.Mode, b.PNR, b.Route.ID, b.Route.Source, b.Route.Destination, len(b.Passengers), b.Fare.Total)
+	}
+
+	if b, err := e.Get(pnrs[0]); err == nil {
+		fmt.Fprintf(w, "\nLookup %v: %v, seats left on %v: %v\n", b.PNR, b.Status, b.Route.ID, e.SeatsAvailable(b.Route.ID))
+		PrintFlightDetails(w, b.details.(IFlight))
+	}
+	if b, err := e.Get(pnrs[1]); err == nil {
+		PrintTrainDetails(w, b.details.(ITrain))
+	}
+	for _, pnr := range pnrs[:2] {
+		b, err := e.Cancel(pnr)
+		if err != nil {
+			fmt.Fprintln(w, err)
+			continue
+		}
+		fmt.Fprintf(w, "Cancel %v: %v, refund: %v of %v\n", b.PNR, b.Status, b.Refund, b.Fare.Total)
+	}
+	_, err := e.Cancel(pnrs[0])
+	fmt.Fprintf(w, "Cancel %v again: %v\n", pnrs[0], err)
+	fmt.Fprintf(w, "Seats left on 6E-201: %v\n", e.SeatsAvailable("6E-201"))
 }
 
-func PrintFlightDetails(f iFlight) {
-	fmt.Println("Flight: ", f.getFlight())
+func PrintFlightDetails(w io.Writer, f IFlight) {
+	fmt.Fprintln(w, "Flight: ", f.getFlight(), f.getDetails())
 }
 
-func PrintTrainDetails(t iTrain) {
-	fmt.Println("Train: ", t.getTrain())
+func PrintTrainDetails(w io.Writer, t ITrain) {
+	fmt.Fprintln(w, "Train: ", t.getTrain(), t.getDetails())
 }
@@ -0,0 +1,268 @@
+package creational
+
+import (
+	"errors"
+	"examples/pricing"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+Booking engine behind the abstract factory: an in-memory inventory of routes and seats shared by all the orgs,
+and the bookings by PNR. The org of a booking request picks the factory, which creates the flight/train
+booking and has the fare rules of the org.
+*/
+
+const (
+	MODE_FLIGHT = "FLIGHT"
+	MODE_TRAIN  = "TRAIN"
+
+	BOOKING_CONFIRMED = "CONFIRMED"
+	BOOKING_CANCELLED = "CANCELLED"
+)
+
+var (
+	ErrRouteNotFound    = errors.New("Route not found!")
+	ErrSoldOut          = errors.New("Not enough seats available!")
+	ErrBookingNotFound  = errors.New("Booking not found!")
+	ErrAlreadyCancelled = errors.New("Booking already cancelled!")
+	ErrDeparted         = errors.New("Route already departed!")
+)
+
+// Route is a flight or a train with its seats, BaseFare is per passenger in minor units
+type Route struct {
+	ID          string        `json:"id"`
+	Mode        string        `json:"mode"`
+	Carrier     string        `json:"carrier"`
+	Source      string        `json:"source"`
+	Destination string        `json:"destination"`
+	Departure   time.Time     `json:"departure"`
+	BaseFare    pricing.Money `json:"base_fare"`
+	Seats       int           `json:"seats"`
+	Available   int           `json:"available"`
+}
+
+type Passenger struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+/*
+FareRule of an org, per passenger: Markup (basis points) on the base fare and a ConvenienceFee.
+On cancellation the convenience fee is not refunded, and CancellationFee is charged unless
+the booking is cancelled more than FreeCancellationHours before departure (0 means never free).
+*/
+type FareRule struct {
+	Markup                pricing.BasisPoints `json:"markup_bps"`
+	ConvenienceFee        pricing.Money       `json:"convenience_fee"`
+	CancellationFee       pricing.Money       `json:"cancellation_fee"`
+	FreeCancellationHours int                 `json:"free_cancellation_hours"`
+}
+
+type Fare struct {
+	BaseFare       pricing.Money `json:"base_fare"`
+	Markup         pricing.Money `json:"markup"`
+	ConvenienceFee pricing.Money `json:"convenience_fee"`
+	PerPassenger   pricing.Money `json:"per_passenger"`
+	Passengers     int           `json:"passengers"`
+	Total          pricing.Money `json:"total"`
+}
+
+type BookingRequest struct {
+	Org        string      `json:"org"`
+	RouteID    string      `json:"route_id"`
+	Passengers []Passenger `json:"passengers"`
+}
+
+type Booking struct {
+	PNR         string        `json:"pnr"`
+	Org         string        `json:"org"`
+	Mode        string        `json:"mode"`
+	Route       Route         `json:"route"`
+	Passengers  []Passenger   `json:"passengers"`
+	Fare        Fare          `json:"fare"`
+	Status      string        `json:"status"`
+	Refund      pricing.Money `json:"refund"`
+	CreatedAt   time.Time     `json:"created_at"`
+	CancelledAt *time.Time    `json:"cancelled_at,omitempty"`
+	// Details of the flight/train created by the factory of the org
+	Details map[string]interface{} `json:"details"`
+
+	details interface{} // IFlight or ITrain
+}
+
+type BookingEngine struct {
+	mulock   sync.Mutex
+	routes   map[string]*Route
+	bookings map[string]*Booking
+	now      func() time.Time
+}
+
+func NewBookingEngine(routes []Route) *BookingEngine {
+	e := &BookingEngine{routes: map[string]*Route{}, bookings: map[string]*Booking{}, now: time.Now}
+	for _, r := range routes {
+		r := r
+		if r.Available == 0 {
+			r.Available = r.Seats
+		}
+		e.routes[r.ID] = &r
+	}
+	return e
+}
+
+// Routes in the inventory, by departure
+func (e *BookingEngine) Routes() (routes []Route) {
+	e.mulock.Lock()
+	defer e.mulock.Unlock()
+	routes = []Route{}
+	for _, r := range e.routes {
+		routes = append(routes, *r)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Departure.Before(routes[j].Departure) })
+	return
+}
+
+func (e *BookingEngine) SeatsAvailable(routeID string) int {
+	e.mulock.Lock()
+	defer e.mulock.Unlock()
+	if r, ok := e.routes[routeID]; ok {
+		return r.Available
+	}
+	return 0
+}
+
+// Book reserves the seats and creates the booking via the factory of the org
+func (e *BookingEngine) Book(req BookingRequest) (b Booking, err error) {
+	baf, err := getBookingFactory(req.Org)
+	if err != nil {
+		return
+	}
+	if len(req.Passengers) == 0 {
+		return b, fmt.Errorf("At least one passenger is required!")
+	}
+	for i, p := range req.Passengers {
+		if strings.TrimSpace(p.Name) == "" || p.Age <= 0 || p.Age > 120 {
+			return b, fmt.Errorf("Invalid passenger %d: name and age (1-120) are required", i+1)
+		}
+	}
+
+	e.mulock.Lock()
+	defer e.mulock.Unlock()
+	route, ok := e.routes[req.RouteID]
+	if !ok {
+		return b, fmt.Errorf("%w: %q", ErrRouteNotFound, req.RouteID)
+	}
+	now := e.now()
+	if !now.Before(route.Departure) {
+		return b, fmt.Errorf("%w: %v", ErrDeparted, route.ID)
+	}
+	if route.Available < len(req.Passengers) {
+		return b, fmt.Errorf("%w: %d left on %v", ErrSoldOut, route.Available, route.ID)
+	}
+
+	booking := &Booking{
+		Org:        strings.ToLower(req.Org),
+		Mode:       route.Mode,
+		Passengers: append([]Passenger{}, req.Passengers...),
+		Fare:       computeFare(baf.FareRule(route.Mode), route.BaseFare, len(req.Passengers)),
+		Status:     BOOKING_CONFIRMED,
+		CreatedAt:  now,
+	}
+	switch route.Mode {
+	case MODE_FLIGHT:
+		f := baf.BookFlight(*route, booking.Passengers)
+		booking.PNR, booking.details, booking.Details = f.getFlight(), f, f.getDetails()
+	case MODE_TRAIN:
+		t := baf.BookTrain(*route, booking.Passengers)
+		booking.PNR, booking.details, booking.Details = t.getTrain(), t, t.getDetails()
+	default:
+		return b, fmt.Errorf("Mode not supported: %v", route.Mode)
+	}
+
+	route.Available -= len(req.Passengers)
+	booking.Route = *route
+	e.bookings[booking.PNR] = booking
+	return *booking, nil
+}
+
+func computeFare(rule FareRule, baseFare pricing.Money, passengers int) (f Fare) {
+	f = Fare{BaseFare: baseFare, Markup: rule.Markup.Of(baseFare), ConvenienceFee: rule.ConvenienceFee, Passengers: passengers}
+	f.PerPassenger = f.BaseFare + f.Markup + f.ConvenienceFee
+	f.Total = f.PerPassenger * pricing.Money(passengers)
+	return
+}
+
+func (e *BookingEngine) Get(pnr string) (b Booking, err error) {
+	e.mulock.Lock()
+	defer e.mulock.Unlock()
+	booking, ok := e.bookings[strings.ToUpper(pnr)]
+	if !ok {
+		return b, fmt.Errorf("%w: %q", ErrBookingNotFound, pnr)
+	}
+	return *booking, nil
+}
+
+// Cancel releases the seats and refunds as per the fare rule of the org
+func (e *BookingEngine) Cancel(pnr string) (b Booking, err error) {
+	e.mulock.Lock()
+	defer e.mulock.Unlock()
+	booking, ok := e.bookings[strings.ToUpper(pnr)]
+	if !ok {
+		return b, fmt.Errorf("%w: %q", ErrBookingNotFound, pnr)
+	}
+	if booking.Status == BOOKING_CANCELLED {
+		return *booking, ErrAlreadyCancelled
+	}
+	now := e.now()
+	if !now.Before(booking.Route.Departure) {
+		return *booking, fmt.Errorf("%w: %v", ErrDeparted, booking.Route.ID)
+	}
+	baf, err := getBookingFactory(booking.Org)
+	if err != nil {
+		return
+	}
+
+	rule := baf.FareRule(booking.Mode)
+	n := pricing.Money(len(booking.Passengers))
+	refund := booking.Fare.Total - booking.Fare.ConvenienceFee*n
+	free := rule.FreeCancellationHours > 0 && booking.Route.Departure.Sub(now) > time.Duration(rule.FreeCancellationHours)*time.Hour
+	if !free {
+		refund -= rule.CancellationFee * n
+	}
+	if refund < 0 {
+		refund = 0
+	}
+
+	booking.Status, booking.Refund, booking.CancelledAt = BOOKING_CANCELLED, refund, &now
+	if route, ok := e.routes[booking.Route.ID]; ok {
+		route.Available += len(booking.Passengers)
+	}
+	return *booking, nil
+}
+
+// DemoRoutes with departures relative to now, so that they can always be booked
+func DemoRoutes(now time.Time) []Route {
+	day := now.Truncate(24 * time.Hour)
+	return []Route{
+		{ID: "6E-201", Mode: MODE_FLIGHT, Carrier: "Indigo", Source: "DEL", Destination: "BOM", Departure: day.Add(3*24*time.Hour + 10*time.Hour), BaseFare: 5200_00, Seats: 180},
+		{ID: "G8-101", Mode: MODE_FLIGHT, Carrier: "GoFirst", Source: "DEL", Destination: "BLR", Departure: day.Add(24*time.Hour + 18*time.Hour), BaseFare: 6100_00, Seats: 3},
+		{ID: "12951", Mode: MODE_TRAIN, Carrier: "Mumbai Rajdhani", Source: "BCT", Destination: "NDLS", Departure: day.Add(2*24*time.Hour + 17*time.Hour), BaseFare: 3150_00, Seats: 72},
+		{ID: "12002", Mode: MODE_TRAIN, Carrier: "Bhopal Shatabdi", Source: "NDLS", Destination: "RKMP", Departure: day.Add(30*24*time.Hour + 6*time.Hour), BaseFare: 1450_00, Seats: 78},
+	}
+}
+
+var (
+	demoBookingOnce   sync.Once
+	demoBookingEngine *BookingEngine
+)
+
+// DemoBookingEngine shared by the API
+func DemoBookingEngine() *BookingEngine {
+	demoBookingOnce.Do(func() {
+		demoBookingEngine = NewBookingEngine(DemoRoutes(time.Now()))
+	})
+	return demoBookingEngine
+}
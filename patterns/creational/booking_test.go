@@ -0,0 +1,155 @@
+package creational
+
+import (
+	"errors"
+	"examples/pricing"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testOrg struct{}
+
+func (o *testOrg) BookFlight(route Route, passengers []Passenger) IFlight {
+	return &ixigoFlight{NewFlight("TST-F", route, passengers)}
+}
+func (o *testOrg) BookTrain(route Route, passengers []Passenger) ITrain {
+	return &ixigoTrain{NewTrain("TST-T", route, passengers)}
+}
+func (o *testOrg) FareRule(mode string) FareRule {
+	return FareRule{Markup: 1000, ConvenienceFee: 10_00, CancellationFee: 50_00, FreeCancellationHours: 48}
+}
+
+var registerTestOrg sync.Once
+
+func testEngine(now time.Time) *BookingEngine {
+	registerTestOrg.Do(func() { RegisterBookingFactory("test", &testOrg{}) })
+	e := NewBookingEngine([]Route{
+		{ID: "F1", Mode: MODE_FLIGHT, Carrier: "Indigo", Source: "DEL", Destination: "BOM", Departure: now.Add(72 * time.Hour), BaseFare: 1000_00, Seats: 2},
+		{ID: "T1", Mode: MODE_TRAIN, Carrier: "Rajdhani", Source: "BCT", Destination: "NDLS", Departure: now.Add(24 * time.Hour), BaseFare: 500_00, Seats: 10},
+	})
+	e.now = func() time.Time { return now }
+	return e
+}
+
+func TestBook(t *testing.T) {
+	now := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	harry := []Passenger{{Name: "Harry", Age: 41}}
+	testCases := []struct {
+		name     string
+		req      BookingRequest
+		prefix   string
+		total    pricing.Money
+		expected error
+		err      string
+	}{
+		{name: "Ixigo-TC-1", req: BookingRequest{Org: "ixigo", RouteID: "F1", Passengers: harry}, prefix: "IXI-F", total: 1099_00},
+		{name: "Makemytrip-TC-2", req: BookingRequest{Org: "MakeMyTrip", RouteID: "T1", Passengers: harry}, prefix: "MMT-T", total: 510_00},
+		{name: "RegisteredOrg-TC-3", req: BookingRequest{Org: "test", RouteID: "F1", Passengers: []Passenger{{"A", 1}, {"B", 2}}}, prefix: "TST-F", total: 2220_00},
+		{name: "UnknownOrg-TC-4", req: BookingRequest{Org: "cleartrip", RouteID: "F1", Passengers: harry}, err: "not defined"},
+		{name: "UnknownRoute-TC-5", req: BookingRequest{Org: "ixigo", RouteID: "X1", Passengers: harry}, expected: ErrRouteNotFound},
+		{name: "SoldOut-TC-6", req: BookingRequest{Org: "ixigo", RouteID: "F1", Passengers: []Passenger{{"A", 1}, {"B", 2}, {"C", 3}}}, expected: ErrSoldOut},
+		{name: "NoPassenger-TC-7", req: BookingRequest{Org: "ixigo", RouteID: "F1"}, err: "passenger"},
+		{name: "InvalidPassenger-TC-8", req: BookingRequest{Org: "ixigo", RouteID: "F1", Passengers: []Passenger{{"", 30}}}, err: "Invalid passenger"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := testEngine(now)
+			b, err := e.Book(tc.req)
+			switch {
+			case tc.expected != nil:
+				if !errors.Is(err, tc.expected) {
+					t.Errorf("Expected: %v, got: %v", tc.expected, err)
+				}
+				return
+			case tc.err != "":
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("Expected error %q, got: %v", tc.err, err)
+				}
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			if !strings.HasPrefix(b.PNR, tc.prefix) || b.Fare.Total != tc.total || b.Status != BOOKING_CONFIRMED {
+				t.Errorf("Unexpected booking: %+v", b)
+			}
+			if got, err := e.Get(strings.ToLower(b.PNR)); err != nil || got.PNR != b.PNR {
+				t.Errorf("Expected lookup by PNR, got: %v", err)
+			}
+			if e.SeatsAvailable(tc.req.RouteID) != b.Route.Seats-len(tc.req.Passengers) {
+				t.Errorf("Expected seats to be reserved, got: %v", e.SeatsAvailable(tc.req.RouteID))
+			}
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	now := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name   string
+		org    string
+		route  string
+		after  time.Duration // cancel this long after booking
+		refund pricing.Money
+		err    error
+	}{
+		// ixigo flight: 1099 - 99 convenience, free cancellation as departure is 72h away
+		{name: "FreeCancellation-TC-1", org: "ixigo", route: "F1", refund: 1000_00},
+		// 22h before departure: 1099 - 99 convenience - 1500 fee, never below zero
+		{name: "CancellationFee-TC-2", org: "ixigo", route: "F1", after: 50 * time.Hour, refund: 0},
+		// makemytrip train: 510 - 120 fee
+		{name: "Makemytrip-TC-3", org: "makemytrip", route: "T1", refund: 390_00},
+		{name: "Departed-TC-4", org: "ixigo", route: "T1", after: 25 * time.Hour, err: ErrDeparted},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := testEngine(now)
+			b, err := e.Book(BookingRequest{Org: tc.org, RouteID: tc.route, Passengers: []Passenger{{"Harry", 41}}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			e.now = func() time.Time { return now.Add(tc.after) }
+
+			c, err := e.Cancel(b.PNR)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("Expected: %v, got: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil || c.Status != BOOKING_CANCELLED || c.Refund != tc.refund {
+				t.Errorf("Expected refund %v, got: %+v %v", tc.refund, c, err)
+			}
+			if e.SeatsAvailable(tc.route) != b.Route.Seats {
+				t.Errorf("Expected seats to be released")
+			}
+			if _, err = e.Cancel(b.PNR); !errors.Is(err, ErrAlreadyCancelled) {
+				t.Errorf("Expected ErrAlreadyCancelled, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestBookConcurrent(t *testing.T) {
+	e := testEngine(time.Now())
+	wg := sync.WaitGroup{}
+	var mulock sync.Mutex
+	booked := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := e.Book(BookingRequest{Org: "ixigo", RouteID: "T1", Passengers: []Passenger{{"Harry", 41}}}); err == nil {
+				mulock.Lock()
+				booked++
+				mulock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if booked != 10 || e.SeatsAvailable("T1") != 0 {
+		t.Errorf("Expected exactly 10 seats booked, got: %v", booked)
+	}
+}
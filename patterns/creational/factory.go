@@ -2,6 +2,7 @@ package creational
 
 import (
 	"fmt"
+	"io"
 )
 
 /*
@@ -13,6 +14,8 @@ All the complexity of creating an object is hidden and a factory is used to crea
 This pattern provides a way to hide the creation logic of the instances being created.
 The client only interacts with a factory struct and tells the kind of instances that needs to be created.
 The factory class interacts with the corresponding concrete structs and returns the correct instance back.
+
+See package notification for a working version, where new types are registered into the factory at runtime.
 */
 
 type NotificationType string
@@ -30,15 +33,16 @@ type iNotification interface {
 type Notification struct {
 	mobile string
 	name   NotificationType
+	out    io.Writer
 }
 
 func (n *Notification) createNotification() (content string) {
-	fmt.Println("Create Notification: ", n.name)
+	fmt.Fprintln(n.out, "Create Notification: ", n.name)
 	content = "Notification Content"
 	return
 }
 func (n *Notification) sendNotification(content string) {
-	fmt.Println("Send Notification: ", n.name)
+	fmt.Fprintln(n.out, "Send Notification: ", n.name)
 	return
 }
 
@@ -47,9 +51,9 @@ type NotificationSMS struct {
 	Notification
 }
 
-func NewInstanceSMS() (s *NotificationSMS) {
+func NewInstanceSMS(w io.Writer) (s *NotificationSMS) {
 	return &NotificationSMS{
-		Notification{name: SMS_NOTIFICATION},
+		Notification{name: SMS_NOTIFICATION, out: w},
 	}
 }
 
@@ -58,25 +62,25 @@ type NotificationWhatsApp struct {
 	Notification
 }
 
-func NewInstanceWhatsapp() (s *NotificationWhatsApp) {
+func NewInstanceWhatsapp(w io.Writer) (s *NotificationWhatsApp) {
 	return &NotificationWhatsApp{
-		Notification{name: WHATSAPP_NOTIFICATION},
+		Notification{name: WHATSAPP_NOTIFICATION, out: w},
 	}
 }
 
-func NotificationFactory(nt NotificationType) (n iNotification) {
+func NotificationFactory(w io.Writer, nt NotificationType) (n iNotification) {
 	switch nt {
 	case SMS_NOTIFICATION:
-		n = NewInstanceSMS()
+		n = NewInstanceSMS(w)
 	case WHATSAPP_NOTIFICATION:
-		n = NewInstanceWhatsapp()
+		n = NewInstanceWhatsapp(w)
 	}
 	return
 }
 
-func ExecuteFactory() {
-	sms := NotificationFactory(SMS_NOTIFICATION)
-	wa := NotificationFactory(WHATSAPP_NOTIFICATION)
+func ExecuteFactory(w io.Writer) {
+	sms := NotificationFactory(w, SMS_NOTIFICATION)
+	wa := NotificationFactory(w, WHATSAPP_NOTIFICATION)
 
 	content := sms.createNotification()
 	sms.sendNotification(content)
@@ -1,16 +1,21 @@
 package creational
 
 import (
+	"context"
+	"errors"
+	"examples/registry"
 	"fmt"
-	"strconv"
+	"io"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 /*
 The Object Pool Design Pattern is a creational design pattern in which a pool of objects is initialized and
 created beforehand and kept in a pool.
 As and when needed, a client can request an object from the pool, use it, and return it to the pool.
-The object in the pool is never destroyed.
+The object in the pool is not destroyed on return, it is reused by the next client.
 
 When to Use:
 1. When the cost to create the object of the class is high and
@@ -24,92 +29,351 @@ the number of such objects that will be needed at a particular time is not much.
 	Almost none of its property needs to be changed
 
 3. For performance reasons. It will boost the application performance significantly since the pool is already created
+
+Pool[T] below is a generic and thread safe implementation:
+- Objects are created lazily via a Factory, up to MaxSize. MinIdle objects are created beforehand (warmup).
+- Borrow blocks when all MaxSize objects are in use, till an object is returned or the context is done.
+- ValidateOnBorrow/ValidateOnReturn hooks discard broken objects ie a closed DB connection.
+- Objects idle for more than MaxIdleTime are evicted, in practice an object in the pool can be destroyed.
+- Close drains the pool: idle objects are destroyed immediately, borrowed objects when they are returned.
+*/
+
+var (
+	ErrPoolClosed  = errors.New("Pool is closed!")
+	ErrNotBorrowed = errors.New("Object was not borrowed from this pool!")
+)
+
+/*
+PoolObject is implemented by the objects kept in the pool.
+The ID identifies a borrowed object when it is returned, so it must be unique within a pool.
 */
+type PoolObject interface {
+	GetID() string
+}
+
+type PoolConfig[T PoolObject] struct {
+	// Factory creates a new object, it is called lazily when no object is idle
+	Factory func(ctx context.Context) (T, error)
+	// Destroy is called when an object leaves the pool (evicted, invalid or pool closed), optional
+	Destroy func(T)
+	// ValidateOnBorrow/ValidateOnReturn discard the object when they return false, optional
+	ValidateOnBorrow func(T) bool
+	ValidateOnReturn func(T) bool
 
-type iPoolObject interface {
-	getID() string
+	MaxSize int // max objects, idle + in use
+	MinIdle int // idle objects created on start up and kept after eviction
+	// MaxIdleTime after which an idle object is evicted, zero means never
+	MaxIdleTime time.Duration
+	// EvictionInterval of the background eviction, zero means Evict is only called explicitly
+	EvictionInterval time.Duration
 }
 
-type pool struct {
-	idle     []iPoolObject
-	active   []iPoolObject
-	capacity int
-	mulock   *sync.Mutex
+type PoolStats struct {
+	MaxSize      int           `json:"max_size"`
+	InUse        int           `json:"in_use"`
+	Idle         int           `json:"idle"`
+	Waiting      int           `json:"waiting"`
+	WaitCount    int64         `json:"wait_count"`    // total borrows which had to wait
+	WaitDuration time.Duration `json:"wait_duration"` // total time spent waiting
+	Created      int64         `json:"created"`
+	Destroyed    int64         `json:"destroyed"`
+	Closed       bool          `json:"closed"`
 }
 
-func (p *pool) borrowObj() (po iPoolObject, err error) {
-	// acquire lock on pool
+type idleObject[T PoolObject] struct {
+	obj   T
+	since time.Time
+}
+
+type Pool[T PoolObject] struct {
+	cfg    PoolConfig[T]
+	mulock sync.Mutex
+
+	idle    []idleObject[T] // FIFO, oldest idle object first
+	active  map[string]T    // borrowed objects by ID
+	size    int             // idle + active + being created
+	waiters []chan struct{} // borrowers waiting for an object, notified in FIFO order
+	closed  bool
+	drained chan struct{} // closed when the pool is closed and all objects are returned
+	stop    chan struct{} // stops the background eviction
+
+	waitCount    int64
+	waitDuration time.Duration
+	created      int64
+	destroyed    int64
+}
+
+/*
+NewPool validates the config and creates MinIdle objects beforehand.
+If EvictionInterval is set, idle objects are evicted in the background till the pool is closed.
+*/
+func NewPool[T PoolObject](ctx context.Context, cfg PoolConfig[T]) (p *Pool[T], err error) {
+	if cfg.Factory == nil {
+		return nil, fmt.Errorf("Pool factory not defined!")
+	}
+	if cfg.MaxSize <= 0 || cfg.MinIdle < 0 || cfg.MinIdle > cfg.MaxSize {
+		return nil, fmt.Errorf("Invalid pool size, max: %v min idle: %v", cfg.MaxSize, cfg.MinIdle)
+	}
+
+	p = &Pool[T]{
+		cfg:     cfg,
+		active:  make(map[string]T),
+		drained: make(chan struct{}),
+		stop:    make(chan struct{}),
+	}
+	if err = p.warmup(ctx); err != nil {
+		p.Close(ctx)
+		return nil, fmt.Errorf("Unable to initialise object pool: %w", err)
+	}
+
+	if cfg.EvictionInterval > 0 {
+		go p.evictor(cfg.EvictionInterval)
+	}
+	return
+}
+
+// Borrow returns an idle object, creates one if the pool is not full, else waits till ctx is done
+func (p *Pool[T]) Borrow(ctx context.Context) (obj T, err error) {
+	var waitStart time.Time
+	defer func() {
+		if !waitStart.IsZero() {
+			p.mulock.Lock()
+			p.waitDuration += time.Since(waitStart)
+			p.mulock.Unlock()
+		}
+	}()
+
+	for {
+		p.mulock.Lock()
+		if p.closed {
+			p.mulock.Unlock()
+			return obj, ErrPoolClosed
+		}
+
+		// move object from idle to active list
+		for len(p.idle) > 0 {
+			idle := p.idle[0]
+			p.idle = p.idle[1:]
+			if p.cfg.ValidateOnBorrow != nil && !p.cfg.ValidateOnBorrow(idle.obj) {
+				p.discardLocked(idle.obj)
+				continue
+			}
+			p.active[idle.obj.GetID()] = idle.obj
+			p.mulock.Unlock()
+			return idle.obj, nil
+		}
+
+		// create a new object, the slot is reserved before unlocking as the factory could be slow
+		if p.size < p.cfg.MaxSize {
+			p.size++
+			p.mulock.Unlock()
+			return p.create(ctx)
+		}
+
+		// wait for an object to be returned
+		ready := make(chan struct{}, 1)
+		p.waiters = append(p.waiters, ready)
+		if waitStart.IsZero() {
+			waitStart = time.Now()
+			p.waitCount++
+		}
+		p.mulock.Unlock()
+
+		select {
+		case <-ready:
+			// retry, an object was returned or a slot was freed
+		case <-ctx.Done():
+			p.mulock.Lock()
+			if !p.removeWaiterLocked(ready) {
+				// notified while giving up, pass it on so that the object is not left waiting
+				p.notifyLocked()
+			}
+			p.mulock.Unlock()
+			return obj, fmt.Errorf("No pool object free/idle: %w", ctx.Err())
+		}
+	}
+}
+
+// Return puts a borrowed object back to the pool, or destroys it if invalid or the pool is closed
+func (p *Pool[T]) Return(obj T) (err error) {
 	p.mulock.Lock()
 	defer p.mulock.Unlock()
 
-	// check if no object idle in pool
-	if len(p.idle) == 0 {
-		return nil, fmt.Errorf("No pool object free/idle. Please request in someitme!")
+	id := obj.GetID()
+	if _, ok := p.active[id]; !ok {
+		return ErrNotBorrowed
+	}
+	delete(p.active, id)
+
+	if p.closed || (p.cfg.ValidateOnReturn != nil && !p.cfg.ValidateOnReturn(obj)) {
+		p.discardLocked(obj)
+	} else {
+		p.idle = append(p.idle, idleObject[T]{obj: obj, since: time.Now()})
 	}
+	p.notifyLocked()
+	p.checkDrainedLocked()
+	return
+}
 
-	// move object from idle to active list
-	po = p.idle[0]
-	p.idle = p.idle[1:]
-	p.active = append(p.active, po)
+/*
+Evict destroys the objects idle for more than MaxIdleTime, keeping at least MinIdle objects,
+then creates objects again if there are less than MinIdle.
+*/
+func (p *Pool[T]) Evict(ctx context.Context) (err error) {
+	p.mulock.Lock()
+	if p.cfg.MaxIdleTime > 0 {
+		kept := p.idle[:0]
+		for i, idle := range p.idle {
+			// idle list is oldest first, so only the first (len - MinIdle) objects can be evicted
+			if i < len(p.idle)-p.cfg.MinIdle && time.Since(idle.since) > p.cfg.MaxIdleTime {
+				p.discardLocked(idle.obj)
+				continue
+			}
+			kept = append(kept, idle)
+		}
+		p.idle = kept
+	}
+	p.mulock.Unlock()
 
-	return //return the pool object
+	return p.warmup(ctx)
 }
 
-func (p *pool) returnObj(po iPoolObject) (err error) {
-	// acquire lock on pool
+/*
+Close stops the pool from handing out objects and destroys the idle objects.
+It then waits till all the borrowed objects are returned (and destroyed) or ctx is done.
+*/
+func (p *Pool[T]) Close(ctx context.Context) (err error) {
 	p.mulock.Lock()
-	defer p.mulock.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.stop)
+		for _, idle := range p.idle {
+			p.discardLocked(idle.obj)
+		}
+		p.idle = nil
+		// wake up all the waiters, they will find the pool closed
+		for len(p.waiters) > 0 {
+			p.notifyLocked()
+		}
+		p.checkDrainedLocked()
+	}
+	p.mulock.Unlock()
 
-	// check if
-	if len(p.idle) == p.capacity {
-		return fmt.Errorf("Pool already full with idle objects, unable to return object to pool!")
+	select {
+	case <-p.drained:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("Pool closed with %v object(s) still borrowed: %w", p.Stats().InUse, ctx.Err())
 	}
+}
 
-	// move object from active to idle list
-	if err = p.removeActiveObj(po); err != nil {
-		return
+func (p *Pool[T]) Stats() (s PoolStats) {
+	p.mulock.Lock()
+	defer p.mulock.Unlock()
+	return PoolStats{
+		MaxSize:      p.cfg.MaxSize,
+		InUse:        len(p.active),
+		Idle:         len(p.idle),
+		Waiting:      len(p.waiters),
+		WaitCount:    p.waitCount,
+		WaitDuration: p.waitDuration,
+		Created:      atomic.LoadInt64(&p.created),
+		Destroyed:    p.destroyed,
+		Closed:       p.closed,
 	}
-	p.idle = append(p.idle, po)
+}
 
+// create calls the factory for a slot already reserved in p.size
+func (p *Pool[T]) create(ctx context.Context) (obj T, err error) {
+	obj, err = p.cfg.Factory(ctx)
+
+	p.mulock.Lock()
+	defer p.mulock.Unlock()
+	if err != nil {
+		p.size--
+		p.notifyLocked() // a waiter can try to create in the freed slot
+		return obj, fmt.Errorf("Unable to create pool object: %w", err)
+	}
+	atomic.AddInt64(&p.created, 1)
+	if p.closed {
+		p.discardLocked(obj)
+		p.checkDrainedLocked()
+		return obj, ErrPoolClosed
+	}
+	p.active[obj.GetID()] = obj
 	return
 }
 
-func (p *pool) removeActiveObj(targetObj iPoolObject) (err error) {
-	/*
-		// Cannot create new slice
-			var newActivePool []iPoolObject
-			for obj := 0; obj < p.capacity; obj++ {
-				if p.active[obj].getID() == targetObj.getID() {
-					newActivePool = p.active[0 : obj-1]
-					newActivePool = append(newActivePool, p.active[obj+1:]...)
-					p.active = newActivePool
-				}
-			}
-	*/
-	currActiveLen := len(p.active)
-	for i, obj := range p.active {
-		if targetObj.getID() == obj.getID() {
-			// need to check this logic ?
-			p.active[i] = p.active[currActiveLen-1] // copy last element of slice to this location
-			p.active = p.active[:currActiveLen-1]   // reslice again and remove the last duplicate value
+// warmup creates idle objects till there are MinIdle of them
+func (p *Pool[T]) warmup(ctx context.Context) (err error) {
+	for {
+		p.mulock.Lock()
+		if p.closed || len(p.idle) >= p.cfg.MinIdle || p.size >= p.cfg.MaxSize {
+			p.mulock.Unlock()
+			return
+		}
+		p.size++
+		p.mulock.Unlock()
+
+		var obj T
+		if obj, err = p.create(ctx); err != nil {
+			return
+		}
+		if err = p.Return(obj); err != nil {
 			return
 		}
 	}
-	return
 }
 
-func InitPool(poolObjs []iPoolObject) (p *pool, err error) {
-	if len(poolObjs) == 0 {
-		return nil, fmt.Errorf("No pool objects to be initialised!")
+func (p *Pool[T]) evictor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.Evict(context.Background())
+		}
 	}
+}
 
-	p = &pool{
-		idle:     poolObjs,
-		active:   make([]iPoolObject, 0),
-		capacity: len(poolObjs),
-		mulock:   &sync.Mutex{}, // new(sync.Mutex)
+// discardLocked destroys an object which is neither idle nor active anymore
+func (p *Pool[T]) discardLocked(obj T) {
+	p.size--
+	p.destroyed++
+	if p.cfg.Destroy != nil {
+		p.cfg.Destroy(obj)
+	}
+}
+
+// notifyLocked wakes up the first waiter
+func (p *Pool[T]) notifyLocked() {
+	if len(p.waiters) == 0 {
+		return
+	}
+	p.waiters[0] <- struct{}{}
+	p.waiters = p.waiters[1:]
+}
+
+func (p *Pool[T]) removeWaiterLocked(ready chan struct{}) (found bool) {
+	for i, ch := range p.waiters {
+		if ch == ready {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Pool[T]) checkDrainedLocked() {
+	if p.closed && p.size == 0 {
+		select {
+		case <-p.drained:
+		default:
+			close(p.drained)
+		}
 	}
-	return
 }
 
 /*
@@ -117,48 +381,114 @@ Create connection entity
 This could be DB connections or logger connections or any worker pool  connections
 */
 type connectionEntity struct {
-	Id string
+	Id        string
+	createdAt time.Time
+	closed    bool
 }
 
-func (ce connectionEntity) getID() (id string) {
-	return
+func (ce *connectionEntity) GetID() (id string) {
+	return ce.Id
 }
-func ExecuteObjectPool() {
-	TOTAL_OBJ := 5
-	//poolObjs := make([]iPoolObject, TOTAL_OBJ)
-	var poolObjs []iPoolObject
-	for i := 1; i <= TOTAL_OBJ; i++ {
-		poolObjs = append(poolObjs, connectionEntity{Id: strconv.Itoa(i)})
-	}
 
-	var (
-		p   *pool
-		err error
-	)
+var (
+	connectionSeq  int64
+	connectionPool *Pool[*connectionEntity]
+	connectionOnce sync.Once
+	connectionErr  error
+)
+
+/*
+ConnectionPool is shared by all the runs of the example, so that concurrent requests compete for
+the same connections and the stats add up over time.
+*/
+func ConnectionPool() (*Pool[*connectionEntity], error) {
+	connectionOnce.Do(func() {
+		connectionPool, connectionErr = NewPool(context.Background(), PoolConfig[*connectionEntity]{
+			Factory: func(ctx context.Context) (*connectionEntity, error) {
+				time.Sleep(20 * time.Millisecond) // creating a connection is costly
+				id := atomic.AddInt64(&connectionSeq, 1)
+				return &connectionEntity{Id: fmt.Sprintf("conn-%d", id), createdAt: time.Now()}, nil
+			},
+			Destroy:          func(ce *connectionEntity) { ce.closed = true },
+			ValidateOnBorrow: func(ce *connectionEntity) bool { return !ce.closed },
+			MaxSize:          5,
+			MinIdle:          2,
+			MaxIdleTime:      time.Minute,
+			EvictionInterval: 30 * time.Second,
+		})
+	})
+	return connectionPool, connectionErr
+}
+
+const (
+	MAX_POOL_WORKERS = 64
+	// the pool is shared by all the runs, a connection is never held nor waited for longer
+	MAX_POOL_HOLD = 5 * time.Second
+	MAX_POOL_WAIT = 10 * time.Second
+)
+
+var (
+	ErrPoolWorkers   = registry.InvalidArgs(fmt.Errorf("Workers should be 1 to %d!", MAX_POOL_WORKERS))
+	ErrPoolDurations = registry.InvalidArgs(fmt.Errorf("Hold should be 0 to %v and wait 0 to %v!", MAX_POOL_HOLD, MAX_POOL_WAIT))
+)
 
-	if p, err = InitPool(poolObjs); err != nil {
-		err = fmt.Errorf("Unable to initialise object pool!")
-		fmt.Println(err)
+/*
+ExecuteObjectPool starts workers which borrow a connection, hold it for some time and return it.
+With more workers than connections, workers wait for a connection and give up after waitTimeout.
+A worker stops holding its connection once ctx is done, so the shared pool gets it back right away.
+*/
+func ExecuteObjectPool(ctx context.Context, w io.Writer, workers int, hold, waitTimeout time.Duration) (err error) {
+	if workers < 1 || workers > MAX_POOL_WORKERS {
+		return ErrPoolWorkers
+	}
+	if hold < 0 || hold > MAX_POOL_HOLD || waitTimeout < 0 || waitTimeout > MAX_POOL_WAIT {
+		return fmt.Errorf("%w: hold %v, wait %v", ErrPoolDurations, hold, waitTimeout)
+	}
+	p, err := ConnectionPool()
+	if err != nil {
+		return
 	}
+	fmt.Fprintf(w, "Object pool : %+v\n", p.Stats())
 
-	fmt.Println("Initialised Object pool")
-	fmt.Printf("Object pool  : %+v", p)
-	fmt.Println()
+	wg := sync.WaitGroup{}
+	for i := 1; i <= workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
 
-	//  borrow object
-	var obj iPoolObject
-	for i := 1; i <= 2; i++ {
-		fmt.Println("Borrow object from pool")
-		obj, _ = p.borrowObj()
-		fmt.Println()
-		fmt.Printf("Object pool : %+v", p)
-		fmt.Println()
+			borrowCtx, cancel := context.WithTimeout(ctx, waitTimeout)
+			defer cancel()
+			start := time.Now()
+			conn, err := p.Borrow(borrowCtx)
+			if err != nil {
+				fmt.Fprintf(w, "Worker %d: %v\n", worker, err)
+				return
+			}
+			fmt.Fprintf(w, "Worker %d: borrowed %s after %v\n", worker, conn.GetID(), time.Since(start).Round(time.Millisecond))
+
+			// use the connection
+			select {
+			case <-time.After(hold):
+			case <-ctx.Done():
+			}
+
+			if err := p.Return(conn); err != nil {
+				fmt.Fprintf(w, "Worker %d: %v\n", worker, err)
+				return
+			}
+			fmt.Fprintf(w, "Worker %d: returned %s\n", worker, conn.GetID())
+		}(i)
 	}
+	wg.Wait()
 
-	//  return object
-	fmt.Println("Return object to pool")
-	p.returnObj(obj)
-	fmt.Println()
-	fmt.Printf("Object pool : %+v", p)
+	// returning an object twice is rejected, instead of moving some other object from the active list
+	conn, err := p.Borrow(ctx)
+	if err != nil {
+		return
+	}
+	p.Return(conn)
+	fmt.Fprintf(w, "Return %s again: %v\n", conn.GetID(), p.Return(conn))
 
+	fmt.Fprintf(w, "Object pool : %+v\n", p.Stats())
+	return nil
 }
@@ -0,0 +1,228 @@
+package creational
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testPool(t *testing.T, cfg PoolConfig[*connectionEntity]) *Pool[*connectionEntity] {
+	var seq int64
+	if cfg.Factory == nil {
+		cfg.Factory = func(ctx context.Context) (*connectionEntity, error) {
+			return &connectionEntity{Id: fmt.Sprintf("conn-%d", atomic.AddInt64(&seq, 1))}, nil
+		}
+	}
+	p, err := NewPool(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+	return p
+}
+
+func TestPoolConfig(t *testing.T) {
+	factory := func(ctx context.Context) (*connectionEntity, error) { return &connectionEntity{Id: "1"}, nil }
+	testCases := []struct {
+		name    string
+		cfg     PoolConfig[*connectionEntity]
+		success bool
+	}{
+		{name: "Valid-TC-1", cfg: PoolConfig[*connectionEntity]{Factory: factory, MaxSize: 1}, success: true},
+		{name: "NoFactory-TC-2", cfg: PoolConfig[*connectionEntity]{MaxSize: 1}},
+		{name: "MaxSize-TC-3", cfg: PoolConfig[*connectionEntity]{Factory: factory}},
+		{name: "MinIdle-TC-4", cfg: PoolConfig[*connectionEntity]{Factory: factory, MaxSize: 1, MinIdle: 2}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewPool(context.Background(), tc.cfg)
+			if (err == nil) != tc.success {
+				t.Errorf("Expected success: %v, got error: %v", tc.success, err)
+			}
+		})
+	}
+}
+
+func TestPoolBorrowBlocks(t *testing.T) {
+	p := testPool(t, PoolConfig[*connectionEntity]{MaxSize: 1, MinIdle: 1})
+
+	conn, err := p.Borrow(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err = p.Borrow(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		p.Return(conn)
+	}()
+	got, err := p.Borrow(context.Background())
+	if err != nil || got != conn {
+		t.Errorf("Expected the returned connection, got: %v %v", got, err)
+	}
+	if s := p.Stats(); s.WaitCount != 2 || s.Created != 1 {
+		t.Errorf("Unexpected stats: %+v", s)
+	}
+}
+
+func TestPoolReturn(t *testing.T) {
+	p := testPool(t, PoolConfig[*connectionEntity]{MaxSize: 2})
+	a, _ := p.Borrow(context.Background())
+	b, _ := p.Borrow(context.Background())
+
+	if err := p.Return(&connectionEntity{Id: "unknown"}); !errors.Is(err, ErrNotBorrowed) {
+		t.Errorf("Expected ErrNotBorrowed, got: %v", err)
+	}
+	if err := p.Return(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Return(a); !errors.Is(err, ErrNotBorrowed) {
+		t.Errorf("Expected ErrNotBorrowed on double return, got: %v", err)
+	}
+	// b is still borrowed, the double return of a must not have touched it
+	if err := p.Return(b); err != nil {
+		t.Errorf("Expected b to be returned, got: %v", err)
+	}
+}
+
+func TestPoolValidate(t *testing.T) {
+	destroyed := 0
+	p := testPool(t, PoolConfig[*connectionEntity]{
+		MaxSize:          1,
+		ValidateOnBorrow: func(ce *connectionEntity) bool { return !ce.closed },
+		ValidateOnReturn: func(ce *connectionEntity) bool { return !ce.closed },
+		Destroy:          func(ce *connectionEntity) { destroyed++ },
+	})
+
+	a, _ := p.Borrow(context.Background())
+	a.closed = true
+	p.Return(a)
+	b, err := p.Borrow(context.Background())
+	if err != nil || b == a {
+		t.Errorf("Expected a new connection, got: %v %v", b, err)
+	}
+	if destroyed != 1 {
+		t.Errorf("Expected 1 destroyed, got: %v", destroyed)
+	}
+}
+
+func TestPoolEvict(t *testing.T) {
+	p := testPool(t, PoolConfig[*connectionEntity]{MaxSize: 3, MinIdle: 1, MaxIdleTime: time.Millisecond})
+	conns := []*connectionEntity{}
+	for i := 0; i < 3; i++ {
+		c, _ := p.Borrow(context.Background())
+		conns = append(conns, c)
+	}
+	for _, c := range conns {
+		p.Return(c)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	p.Evict(context.Background())
+	if s := p.Stats(); s.Idle != 1 || s.Destroyed != 2 {
+		t.Errorf("Expected 1 idle and 2 destroyed, got: %+v", s)
+	}
+}
+
+func TestPoolClose(t *testing.T) {
+	p := testPool(t, PoolConfig[*connectionEntity]{MaxSize: 1})
+	conn, _ := p.Borrow(context.Background())
+
+	waiter := make(chan error)
+	go func() {
+		_, err := p.Borrow(context.Background())
+		waiter <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected close to wait for the borrowed connection, got: %v", err)
+	}
+	if err := <-waiter; !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected waiter to get ErrPoolClosed, got: %v", err)
+	}
+
+	p.Return(conn)
+	if err := p.Close(context.Background()); err != nil {
+		t.Errorf("Expected pool to be drained, got: %v", err)
+	}
+	if s := p.Stats(); s.InUse != 0 || s.Destroyed != 1 {
+		t.Errorf("Unexpected stats: %+v", s)
+	}
+}
+
+func TestPoolConcurrent(t *testing.T) {
+	const maxSize = 3
+	var inUse, peak int64
+	p := testPool(t, PoolConfig[*connectionEntity]{MaxSize: maxSize})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := p.Borrow(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			n := atomic.AddInt64(&inUse, 1)
+			for {
+				old := atomic.LoadInt64(&peak)
+				if n <= old || atomic.CompareAndSwapInt64(&peak, old, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&inUse, -1)
+			p.Return(conn)
+		}()
+	}
+	wg.Wait()
+
+	if peak > maxSize {
+		t.Errorf("Expected at most %v connections in use, got: %v", maxSize, peak)
+	}
+	if s := p.Stats(); s.Created > maxSize || s.InUse != 0 {
+		t.Errorf("Unexpected stats: %+v", s)
+	}
+}
+
+func TestExecuteObjectPoolWorkers(t *testing.T) {
+	for _, workers := range []int{0, MAX_POOL_WORKERS + 1} {
+		if err := ExecuteObjectPool(context.Background(), io.Discard, workers, 0, 0); !errors.Is(err, ErrPoolWorkers) {
+			t.Errorf("Expected %v for %d workers, got: %v", ErrPoolWorkers, workers, err)
+		}
+	}
+	for _, d := range []struct{ hold, wait time.Duration }{{-1, 0}, {MAX_POOL_HOLD + 1, 0}, {0, -1}, {0, MAX_POOL_WAIT + 1}} {
+		if err := ExecuteObjectPool(context.Background(), io.Discard, 1, d.hold, d.wait); !errors.Is(err, ErrPoolDurations) {
+			t.Errorf("Expected %v for %+v, got: %v", ErrPoolDurations, d, err)
+		}
+	}
+}
+
+// a cancelled run returns its connections right away, rather than after hold
+func TestExecuteObjectPoolCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	ExecuteObjectPool(ctx, io.Discard, 2, MAX_POOL_HOLD, time.Second)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the connections to be returned once cancelled, took: %v", elapsed)
+	}
+	p, _ := ConnectionPool()
+	if inUse := p.Stats().InUse; inUse != 0 {
+		t.Errorf("Expected no connection in use, got: %v", inUse)
+	}
+}
@@ -1,5 +1,15 @@
 package creational
 
+import (
+	"errors"
+	"examples/clone"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
 /*
 refer link : https://golangbyexample.com/prototype-pattern-go/
 
@@ -16,4 +26,255 @@ A copy of the object is created instead of creating a new instance from scratch.
 while creating a new object such as database operation.
 When you want to create a copy of a new object, but it is only available to you as an interface. Hence you cannot directly
 create copies of that object.
+
+Below, the project templates are file-system trees of inodes, cloned deeply via clone.DeepCopy including the
+attribute maps, contents and the cyclic parent pointers, and handed out by name from a registry of prototypes.
+*/
+
+var (
+	ErrPrototypeNotFound = errors.New("Prototype not found!")
+	ErrPrototypeExists   = errors.New("Prototype already registered!")
+	ErrInvalidPrototype  = errors.New("Prototype name and object are required!")
+)
+
+// Cloner is implemented by the prototypes, Clone returns a deep copy sharing no memory with the original
+type Cloner interface {
+	Clone() Cloner
+}
+
+/*
+Inode is a node of a file-system tree, either a file or a folder.
+Every node points back to its parent folder, hence the tree is a cyclic graph.
 */
+type Inode interface {
+	Cloner
+	GetName() string
+	SetName(name string)
+	Path() string
+	View() InodeView
+	print(w io.Writer, indent string)
+}
+
+// InodeView is the JSON friendly (acyclic) view of an inode
+type InodeView struct {
+	Name     string            `json:"name"`
+	Path     string            `json:"path"`
+	Type     string            `json:"type"`
+	Size     int               `json:"size,omitempty"`
+	Attrs    map[string]string `json:"attrs,omitempty"`
+	Children []InodeView       `json:"children,omitempty"`
+}
+
+type File struct {
+	name    string
+	content []byte
+	attrs   map[string]string
+	parent  *Folder
+}
+
+type Folder struct {
+	name     string
+	children []Inode
+	attrs    map[string]string
+	parent   *Folder
+}
+
+func NewFile(name string, content string, attrs map[string]string) *File {
+	return &File{name: name, content: []byte(content), attrs: attrs}
+}
+
+func NewFolder(name string, attrs map[string]string, children ...Inode) (f *Folder) {
+	f = &Folder{name: name, attrs: attrs}
+	for _, child := range children {
+		f.Add(child)
+	}
+	return
+}
+
+/*
+Clone copies the file with its content and attributes, the clone is detached from the parent.
+*/
+func (f *File) Clone() Cloner {
+	c := clone.DeepCopyShare(f, f.parent)
+	c.parent = nil
+	return c
+}
+
+func (f *File) GetName() string     { return f.name }
+func (f *File) SetName(name string) { f.name = name }
+func (f *File) Path() string        { return path(f.parent, f.name) }
+
+func (f *File) Write(content string) {
+	f.content = []byte(content)
+}
+
+func (f *File) View() InodeView {
+	return InodeView{Name: f.name, Path: f.Path(), Type: "file", Size: len(f.content), Attrs: f.attrs}
+}
+
+func (f *File) print(w io.Writer, indent string) {
+	fmt.Fprintf(w, "%s%s (%d bytes) %v\n", indent, f.name, len(f.content), f.attrs)
+}
+
+/*
+Clone copies the folder along with the whole sub-tree, the parents of the copied children
+point to the copied folders. The clone is detached from the parent of the folder.
+*/
+func (f *Folder) Clone() Cloner {
+	c := clone.DeepCopyShare(f, f.parent)
+	c.parent = nil
+	return c
+}
+
+func (f *Folder) GetName() string     { return f.name }
+func (f *Folder) SetName(name string) { f.name = name }
+func (f *Folder) Path() string        { return path(f.parent, f.name) }
+
+func (f *Folder) Add(child Inode) {
+	switch c := child.(type) {
+	case *File:
+		c.parent = f
+	case *Folder:
+		c.parent = f
+	}
+	f.children = append(f.children, child)
+}
+
+/*
+Find returns the inode at the path relative to the folder, ie "src/main.go"
+*/
+func (f *Folder) Find(relative string) (Inode, bool) {
+	name, rest, nested := strings.Cut(relative, "/")
+	for _, child := range f.children {
+		if child.GetName() != name {
+			continue
+		}
+		if !nested {
+			return child, true
+		}
+		if folder, ok := child.(*Folder); ok {
+			return folder.Find(rest)
+		}
+	}
+	return nil, false
+}
+
+func (f *Folder) View() (v InodeView) {
+	v = InodeView{Name: f.name, Path: f.Path(), Type: "folder", Attrs: f.attrs}
+	for _, child := range f.children {
+		v.Children = append(v.Children, child.View())
+	}
+	return
+}
+
+func (f *Folder) print(w io.Writer, indent string) {
+	fmt.Fprintf(w, "%s%s/ %v\n", indent, f.name, f.attrs)
+	for _, child := range f.children {
+		child.print(w, indent+"  ")
+	}
+}
+
+func path(parent *Folder, name string) string {
+	if parent == nil {
+		return name
+	}
+	return parent.Path() + "/" + name
+}
+
+// PrototypeRegistry holds the named prototypes, handing out their clones
+type PrototypeRegistry struct {
+	mulock     sync.RWMutex
+	prototypes map[string]Cloner
+}
+
+func NewPrototypeRegistry() *PrototypeRegistry {
+	return &PrototypeRegistry{prototypes: map[string]Cloner{}}
+}
+
+func (r *PrototypeRegistry) Register(name string, prototype Cloner) error {
+	if name == "" || prototype == nil {
+		return ErrInvalidPrototype
+	}
+	r.mulock.Lock()
+	defer r.mulock.Unlock()
+	if _, ok := r.prototypes[name]; ok {
+		return fmt.Errorf("%w: %q", ErrPrototypeExists, name)
+	}
+	r.prototypes[name] = prototype
+	return nil
+}
+
+/*
+Clone returns a deep copy of the named prototype, the prototype itself is never handed out.
+*/
+func (r *PrototypeRegistry) Clone(name string) (Cloner, error) {
+	r.mulock.RLock()
+	prototype, ok := r.prototypes[name]
+	r.mulock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrPrototypeNotFound, name)
+	}
+	return prototype.Clone(), nil
+}
+
+func (r *PrototypeRegistry) Names() (names []string) {
+	r.mulock.RLock()
+	defer r.mulock.RUnlock()
+	for name := range r.prototypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+var (
+	prototypes     *PrototypeRegistry
+	prototypesOnce sync.Once
+)
+
+/*
+Prototypes is the registry of the project templates, shared by the example and the API.
+*/
+func Prototypes() *PrototypeRegistry {
+	prototypesOnce.Do(func() {
+		prototypes = NewPrototypeRegistry()
+		prototypes.Register("go-service", NewFolder("service", map[string]string{"lang": "go"},
+			NewFile("go.mod", "module service\n\ngo 1.19\n", nil),
+			NewFile("README.md", "# service\n", map[string]string{"owner": "platform"}),
+			NewFolder("cmd", nil, NewFile("main.go", "package main\n\nfunc main() {}\n", nil)),
+			NewFolder("internal", nil,
+				NewFolder("handler", nil, NewFile("handler.go", "package handler\n", nil)),
+			),
+		))
+		prototypes.Register("static-site", NewFolder("site", map[string]string{"host": "cdn"},
+			NewFile("index.html", "<html><body></body></html>\n", nil),
+			NewFolder("assets", nil, NewFile("style.css", "body {}\n", nil)),
+		))
+		prototypes.Register("readme", NewFile("README.md", "# title\n", map[string]string{"owner": "docs"}))
+	})
+	return prototypes
+}
+
+func ExecuteProtoType(w io.Writer) {
+	registry := Prototypes()
+	fmt.Fprintln(w, "Prototypes:", registry.Names())
+
+	prototype, _ := registry.Clone("go-service")
+	billing := prototype.(*Folder)
+	billing.SetName("billing")
+	billing.attrs["team"] = "payments"
+	if mod, ok := billing.Find("go.mod"); ok {
+		mod.(*File).Write("module billing\n\ngo 1.19\n")
+	}
+	handler, _ := billing.Find("internal/handler/handler.go")
+	fmt.Fprintln(w, "\nCloned go-service as billing, the parents point into the clone:", handler.Path())
+	billing.print(w, "")
+
+	original, _ := registry.Clone("go-service")
+	fmt.Fprintln(w, "\nThe prototype is unaffected:")
+	original.(Inode).print(w, "")
+
+	if _, err := registry.Clone("java-service"); err != nil {
+		fmt.Fprintln(w, "\nError:", err)
+	}
+}
@@ -0,0 +1,82 @@
+package creational
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrototypeClone(t *testing.T) {
+	r := NewPrototypeRegistry()
+	src := NewFolder("app", map[string]string{"lang": "go"},
+		NewFolder("cmd", nil, NewFile("main.go", "package main", map[string]string{"mode": "0644"})),
+	)
+	if err := r.Register("app", src); err != nil {
+		t.Fatal(err)
+	}
+
+	cloned, err := r.Clone("app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cloned.(*Folder)
+	main, _ := c.Find("cmd/main.go")
+	original, _ := src.Find("cmd/main.go")
+	if c == src || main == original {
+		t.Fatal("Expected a new tree")
+	}
+	if main.(*File).parent.parent != c {
+		t.Error("Expected the parents to point into the clone")
+	}
+
+	c.SetName("billing")
+	c.attrs["lang"] = "rust"
+	main.(*File).Write("package billing")
+	main.(*File).attrs["mode"] = "0600"
+	if got, want := main.Path(), "billing/cmd/main.go"; got != want {
+		t.Errorf("Expected: %v, got: %v", want, got)
+	}
+	if src.name != "app" || src.attrs["lang"] != "go" || string(original.(*File).content) != "package main" || original.(*File).attrs["mode"] != "0644" {
+		t.Errorf("Expected the prototype to be unaffected, got: %+v", src.View())
+	}
+}
+
+func TestPrototypeCloneDetached(t *testing.T) {
+	src := NewFolder("app", nil, NewFolder("cmd", nil, NewFile("main.go", "package main", nil)))
+	cmd, _ := src.Find("cmd")
+
+	c := cmd.Clone().(*Folder)
+	if c.parent != nil || c.Path() != "cmd" || len(src.children) != 1 {
+		t.Errorf("Expected the clone of a sub-tree to be detached, got: %v", c.Path())
+	}
+	if main, _ := c.Find("main.go"); main.(*File).parent != c {
+		t.Error("Expected the parent of the child to be the cloned folder")
+	}
+}
+
+func TestPrototypeRegistry(t *testing.T) {
+	r := NewPrototypeRegistry()
+	testCases := []struct {
+		name      string
+		prototype string
+		object    Cloner
+		expected  error
+	}{
+		{name: "Register-TC-1", prototype: "readme", object: NewFile("README.md", "# title", nil)},
+		{name: "Duplicate-TC-2", prototype: "readme", object: NewFile("README.md", "", nil), expected: ErrPrototypeExists},
+		{name: "NoName-TC-3", object: NewFile("README.md", "", nil), expected: ErrInvalidPrototype},
+		{name: "NoObject-TC-4", prototype: "empty", expected: ErrInvalidPrototype},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := r.Register(tc.prototype, tc.object); !errors.Is(err, tc.expected) {
+				t.Errorf("Expected: %v, got: %v", tc.expected, err)
+			}
+		})
+	}
+	if _, err := r.Clone("license"); !errors.Is(err, ErrPrototypeNotFound) {
+		t.Errorf("Expected: %v, got: %v", ErrPrototypeNotFound, err)
+	}
+	if names := r.Names(); len(names) != 1 || names[0] != "readme" {
+		t.Errorf("Unexpected names: %v", names)
+	}
+}
@@ -0,0 +1,65 @@
+package creational
+
+import (
+	"context"
+	"examples/registry"
+	"io"
+	"time"
+)
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "pattern/creational/factory",
+		Title:       "Factory Pattern",
+		Category:    registry.CATEGORY_CREATIONAL,
+		Description: "NotificationFactory hides the creation of SMS/WhatsApp notifications behind a NotificationType",
+		Source:      "patterns/creational/factory.go",
+		Run:         registry.Simple(ExecuteFactory),
+	})
+	registry.Register(registry.Example{
+		Name:        "pattern/creational/abstract-factory",
+		Title:       "Abstract Factory Pattern",
+		Category:    registry.CATEGORY_CREATIONAL,
+		Description: "ixigo/makemytrip booking factories with their own fare rules, booking and cancelling seats on shared routes",
+		Source:      "patterns/creational/abstract_factory.go",
+		Run:         registry.Simple(ExecuteAbstractFactory),
+	})
+	registry.Register(registry.Example{
+		Name:        "pattern/creational/object-pool",
+		Title:       "Object Pool",
+		Category:    registry.CATEGORY_CREATIONAL,
+		Description: "Concurrent workers borrow and return connections from a generic pool with blocking borrow and a wait timeout",
+		Source:      "patterns/creational/object_pool.go",
+		Params: []registry.Param{
+			{Name: "workers", Type: registry.PARAM_INT, Default: "8", Usage: "workers borrowing a connection concurrently, at most 64"},
+			{Name: "hold", Type: registry.PARAM_INT, Default: "100", Usage: "milliseconds a worker holds the connection, at most 5000"},
+			{Name: "wait", Type: registry.PARAM_INT, Default: "500", Usage: "milliseconds a worker waits for a connection, at most 10000"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return ExecuteObjectPool(ctx, w, args.Int("workers"),
+				time.Duration(args.Int("hold"))*time.Millisecond, time.Duration(args.Int("wait"))*time.Millisecond)
+		},
+	})
+	registry.Register(registry.Example{
+		Name:        "pattern/creational/singleton",
+		Title:       "Singleton Pattern",
+		Category:    registry.CATEGORY_CREATIONAL,
+		Description: "Create a single instance from concurrent goroutines via double checked locking with an atomic check, sync.Once or lazy.Lazy",
+		Source:      "patterns/creational/singleton.go",
+		Params: []registry.Param{
+			{Name: "goroutines", Type: registry.PARAM_INT, Default: "10", Usage: "goroutines requesting the instance, at most 1000"},
+			{Name: "mode", Type: registry.PARAM_STRING, Default: SINGLETON_LAZY, Usage: "locked, once or lazy"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return ExecuteSingleton(ctx, w, args.Int("goroutines"), args.String("mode"))
+		},
+	})
+	registry.Register(registry.Example{
+		Name:        "pattern/creational/prototype",
+		Title:       "Prototype Pattern",
+		Category:    registry.CATEGORY_CREATIONAL,
+		Description: "Clone project templates, file-system trees with parent pointers, deeply from a registry of named prototypes",
+		Source:      "patterns/creational/prototype.go",
+		Run:         registry.Simple(ExecuteProtoType),
+	})
+}
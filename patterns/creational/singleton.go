@@ -1,8 +1,15 @@
 package creational
 
 import (
+	"context"
+	"errors"
+	"examples/lazy"
+	"examples/registry"
 	"fmt"
+	"io"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 /*
@@ -10,44 +17,155 @@ There are 3 ways yto create an object once
 1. Using init()
 2. Using sync.Mutex struct - Lock/Unlock Methods
 3. Using sync.Once struct - Do Method
+
+The classic double checked locking reads the instance without the lock first. In Go that read is
+a data race (see racySingleton in singleton_test.go, run with RACE_DEMO=1 go test -race), the check
+has to be atomic.
+lazy.Lazy wraps the corrected version, while also allowing the creation to fail and be retried.
 */
 type single struct {
+	id int64
+}
+
+const (
+	SINGLETON_LOCKED = "locked"
+	SINGLETON_ONCE   = "once"
+	SINGLETON_LAZY   = "lazy"
+)
+
+const MAX_SINGLETON_GOROUTINES = 1000
+
+var (
+	ErrSingletonMode       = registry.InvalidArgs(fmt.Errorf("Mode should be one of %s, %s or %s!", SINGLETON_LOCKED, SINGLETON_ONCE, SINGLETON_LAZY))
+	ErrSingletonGoroutines = registry.InvalidArgs(fmt.Errorf("Goroutines should be 1 to %d!", MAX_SINGLETON_GOROUTINES))
+)
+
+// singletons keeps the instances of each mode, along with the number of instances created
+type singletons struct {
+	created int64
+
+	mu     sync.Mutex
+	locked atomic.Pointer[single]
+
+	once     sync.Once
+	onceInst *single
+
+	lazy *lazy.Lazy[*single]
+}
+
+func newSingletons() (s *singletons) {
+	s = &singletons{}
+	s.lazy = lazy.New(func() (*single, error) { return s.newSingle(), nil })
+	return
+}
+
+func (s *singletons) newSingle() *single {
+	return &single{id: atomic.AddInt64(&s.created, 1)}
+}
+
+// getLocked is double checked locking with an atomic first check
+func (s *singletons) getLocked() *single {
+	if inst := s.locked.Load(); inst != nil {
+		return inst
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if inst := s.locked.Load(); inst != nil {
+		return inst
+	}
+	inst := s.newSingle()
+	s.locked.Store(inst)
+	return inst
+}
+
+func (s *singletons) getOnce() *single {
+	s.once.Do(func() {
+		s.onceInst = s.newSingle()
+	})
+	return s.onceInst
+}
+
+func (s *singletons) getLazy() *single {
+	inst, _ := s.lazy.Get() // never fails
+	return inst
+}
+
+func (s *singletons) get(mode string) (*single, error) {
+	switch mode {
+	case SINGLETON_LOCKED:
+		return s.getLocked(), nil
+	case SINGLETON_ONCE:
+		return s.getOnce(), nil
+	case SINGLETON_LAZY:
+		return s.getLazy(), nil
+	}
+	return nil, ErrSingletonMode
+}
+
+// dbConnection is created per tenant via lazy.Registry, the first attempt of "globex" fails
+type dbConnection struct {
+	tenant string
 }
 
-var singleInstance *single
-
-var mu = &sync.Mutex{}
-var once sync.Once
-
-var flagSyncOnce = true
-
-func getSingleton() *single {
-	if singleInstance == nil {
-		if flagSyncOnce {
-			once.Do(
-				func() {
-					singleInstance = &single{}
-				},
-			)
-		} else {
-			mu.Lock()
-			defer mu.Unlock()
-			if singleInstance == nil {
-				singleInstance = &single{}
-			} else {
-				fmt.Println("Concurrent Call - Instance already created!")
-			}
+func ExecuteSingleton(ctx context.Context, w io.Writer, goroutines int, mode string) (err error) {
+	switch mode {
+	case SINGLETON_LOCKED, SINGLETON_ONCE, SINGLETON_LAZY:
+	default:
+		return ErrSingletonMode
+	}
+	if goroutines < 1 || goroutines > MAX_SINGLETON_GOROUTINES {
+		return fmt.Errorf("%w: %d", ErrSingletonGoroutines, goroutines)
+	}
+	s := newSingletons()
+
+	fmt.Fprintf(w, "Creating Singleton Instance (%s) from %d goroutines:\n", mode, goroutines)
+	wg := sync.WaitGroup{}
+	ids := make([]int64, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			inst, _ := s.get(mode)
+			ids[i] = inst.id
+		}(i)
+	}
+	wg.Wait()
+	fmt.Fprintf(w, "Instances created: %d, instances seen: %v\n", atomic.LoadInt64(&s.created), distinct(ids))
+
+	attempts := map[string]int{}
+	var mulock sync.Mutex
+	connections := lazy.NewRegistry(func(tenant string) (*dbConnection, error) {
+		mulock.Lock()
+		defer mulock.Unlock()
+		if attempts[tenant]++; tenant == "globex" && attempts[tenant] == 1 {
+			return nil, errors.New("DB not reachable!")
+		}
+		return &dbConnection{tenant: tenant}, nil
+	})
+	fmt.Fprintln(w, "\nConnection per tenant via lazy.Registry:")
+	for _, tenant := range []string{"acme", "globex", "acme", "globex"} {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
-	} else {
-		fmt.Println("Async Call - Instance already created!")
+		conn, err := connections.Get(tenant)
+		if err != nil {
+			fmt.Fprintf(w, "%s: %v, retried on the next call\n", tenant, err)
+			continue
+		}
+		fmt.Fprintf(w, "%s: connection %p\n", tenant, conn)
 	}
-	return singleInstance
+	fmt.Fprintln(w, "Attempts:", attempts)
+	return
 }
 
-func ExecuteSingleton() {
-	fmt.Println("Creating Singleton Instance:")
-	for i := 0; i < 10; i++ {
-		go getSingleton()
+func distinct(ids []int64) (unique []int64) {
+	seen := map[int64]bool{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
 	}
-	fmt.Scanln()
+	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
+	return
 }
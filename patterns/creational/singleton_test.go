@@ -0,0 +1,103 @@
+package creational
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"examples/registry"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func getConcurrently(s *singletons, mode string, goroutines int) {
+	wg := sync.WaitGroup{}
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.get(mode)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSingleton(t *testing.T) {
+	for _, mode := range []string{SINGLETON_LOCKED, SINGLETON_ONCE, SINGLETON_LAZY} {
+		t.Run(mode, func(t *testing.T) {
+			s := newSingletons()
+			getConcurrently(s, mode, 100)
+			if s.created != 1 {
+				t.Errorf("Expected 1 instance, got: %v", s.created)
+			}
+			if mode == SINGLETON_LAZY {
+				s.lazy.Reset()
+				if s.getLazy().id != 2 {
+					t.Errorf("Expected a new instance after reset")
+				}
+			}
+		})
+	}
+}
+
+/*
+racySingleton is double checked locking, as usually written in other languages.
+The first check reads inst while another goroutine might be writing it under the lock.
+*/
+type racySingleton struct {
+	*singletons
+	inst *single // read without the lock, hence racy
+}
+
+func (s *racySingleton) get() *single {
+	if s.inst == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.inst == nil {
+			s.inst = s.newSingle()
+		}
+	}
+	return s.inst
+}
+
+/*
+TestSingletonRace shows the data race of the unguarded first check, it fails by design under
+the race detector, hence only runs on demand: RACE_DEMO=1 go test -race -run TestSingletonRace
+*/
+func TestSingletonRace(t *testing.T) {
+	if os.Getenv("RACE_DEMO") == "" {
+		t.Skip("Set RACE_DEMO=1 and run with -race to see the data race of racySingleton")
+	}
+	s := &racySingleton{singletons: newSingletons()}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.get()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestExecuteSingleton(t *testing.T) {
+	w := &bytes.Buffer{}
+	if err := ExecuteSingleton(context.Background(), w, 20, SINGLETON_LAZY); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(w.String(), "Instances created: 1,") || !strings.Contains(w.String(), "map[acme:1 globex:2]") {
+		t.Errorf("Unexpected output: %v", w.String())
+	}
+	for _, mode := range []string{"mutex", "racy"} {
+		if err := ExecuteSingleton(context.Background(), w, 20, mode); !errors.Is(err, ErrSingletonMode) {
+			t.Errorf("Expected: %v for %v, got: %v", ErrSingletonMode, mode, err)
+		}
+	}
+	for _, goroutines := range []int{-1, 0, MAX_SINGLETON_GOROUTINES + 1} {
+		err := ExecuteSingleton(context.Background(), w, goroutines, SINGLETON_LAZY)
+		if !errors.Is(err, ErrSingletonGoroutines) || !errors.Is(err, registry.ErrInvalidArgs) {
+			t.Errorf("Expected: %v for %d goroutines, got: %v", ErrSingletonGoroutines, goroutines, err)
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package structural
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /*
 https://golangbyexample.com/bridge-design-pattern-in-go/
 Allows the separation of abstraction from its implementation
 In this example, check how any type of notification (Email/Sms) BRIDGES with any type of Vendor (X,Y) on the runtime
+See package notification for a working version with templates, retry and failover between vendors.
 */
 
 /*
@@ -18,20 +22,22 @@ type vendor interface {
 }
 type Xvendor struct {
 	mobile int8
+	out    io.Writer
 }
 
 func (xv *Xvendor) send(data map[string]interface{}) {
-	fmt.Println("Sending notification via Xvendor")
-	fmt.Println("Data : ", data)
+	fmt.Fprintln(xv.out, "Sending notification via Xvendor")
+	fmt.Fprintln(xv.out, "Data : ", data)
 }
 
 type Yvendor struct {
 	emailID string
+	out     io.Writer
 }
 
 func (yv *Yvendor) send(data map[string]interface{}) {
-	fmt.Println("Sending notification via Yvendor")
-	fmt.Println("Data : ", data)
+	fmt.Fprintln(yv.out, "Sending notification via Yvendor")
+	fmt.Fprintln(yv.out, "Data : ", data)
 }
 
 /*
@@ -47,42 +53,44 @@ type Notification interface {
 type Email struct {
 	vendor  vendor
 	emailID string
+	out     io.Writer
 }
 
 func (e *Email) notify() {
-	fmt.Println("\nTrigger Email Notification : " + e.emailID)
+	fmt.Fprintln(e.out, "\nTrigger Email Notification : "+e.emailID)
 	data := map[string]interface{}{"mobile": e.emailID}
 	e.vendor.send(data)
 }
 
 func (e *Email) setVendor(v vendor) {
-	fmt.Printf("\nSet Email Notification Vendor : %T", v)
+	fmt.Fprintf(e.out, "\nSet Email Notification Vendor : %T", v)
 	e.vendor = v
 }
 
 type Sms struct {
 	vendor vendor
 	mobile string
+	out    io.Writer
 }
 
 func (s *Sms) notify() {
-	fmt.Println("\nTrigger Sms Notification : " + s.mobile)
+	fmt.Fprintln(s.out, "\nTrigger Sms Notification : "+s.mobile)
 	data := map[string]interface{}{"mobile": s.mobile}
 	s.vendor.send(data)
 }
 
 func (s *Sms) setVendor(v vendor) {
-	fmt.Printf("\nSet Sms Notification Vendor : %T", v)
+	fmt.Fprintf(s.out, "\nSet Sms Notification Vendor : %T", v)
 	s.vendor = v
 }
 
-func Execute() {
+func Execute(w io.Writer) {
 	// Initialise notification vendors
-	xv := &Xvendor{}
-	yv := &Yvendor{}
+	xv := &Xvendor{out: w}
+	yv := &Yvendor{out: w}
 
 	// Send same SMS via both Vendors
-	sms1 := &Sms{mobile: "9910825975"}
+	sms1 := &Sms{mobile: "9910825975", out: w}
 	// send SMS via vendor X
 	sms1.setVendor(xv)
 	sms1.notify()
@@ -91,7 +99,7 @@ func Execute() {
 	sms1.notify()
 
 	// Send same Email via both Vendors
-	email1 := &Email{emailID: "harry@gmail.com"}
+	email1 := &Email{emailID: "harry@gmail.com", out: w}
 	// send Email via vendor X
 	email1.setVendor(xv)
 	email1.notify()
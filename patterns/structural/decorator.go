@@ -2,6 +2,7 @@ package structural
 
 import (
 	"fmt"
+	"io"
 )
 
 /*
@@ -35,6 +36,8 @@ We have done 2 things:
 - ALSO embedded the same
 This way, we have not changed the existing types (MargerettaPizza & FarmhousePizza),
 Instead we hahve inhanced/decorated them
+
+See package pricing for decorators which are registered by name and can be chained to any depth.
 */
 
 type MargerettaPizzaWithToppings struct {
@@ -55,7 +58,7 @@ func (fpwt *FarmhousePizzaWithToppings) getPrice() (prc int) {
 	return
 }
 
-func ExecuteDecorator() {
+func ExecuteDecorator(w io.Writer) {
 	mp := &MargerettaPizza{}
 	fp := new(FarmhousePizza)
 
@@ -65,11 +68,11 @@ func ExecuteDecorator() {
 	fpwt := &FarmhousePizzaWithToppings{}
 	fpwt.iPizza = fp
 
-	fmt.Println("-- Without Toppings --")
-	fmt.Println("MargerettaPizza : ", mp.getPrice())
-	fmt.Println("FarmhousePizza : ", fp.getPrice())
+	fmt.Fprintln(w, "-- Without Toppings --")
+	fmt.Fprintln(w, "MargerettaPizza : ", mp.getPrice())
+	fmt.Fprintln(w, "FarmhousePizza : ", fp.getPrice())
 
-	fmt.Println("-- With Toppings --")
-	fmt.Println("MargerettaPizza : ", mpwt.getPrice())
-	fmt.Println("FarmhousePizza : ", fpwt.getPrice())
+	fmt.Fprintln(w, "-- With Toppings --")
+	fmt.Fprintln(w, "MargerettaPizza : ", mpwt.getPrice())
+	fmt.Fprintln(w, "FarmhousePizza : ", fpwt.getPrice())
 }
@@ -1,7 +1,12 @@
 package structural
 
 import (
+	"encoding/json"
+	"examples/pricing"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -13,59 +18,269 @@ Basically it provides a higher level abstraction over a complicated system.
 
 The term Facade itself means
 the principal front of a building, that faces on to a street or open space
+
+Here the client only calls Service.Applicable(booking), the ServiceFacade checks the booking against
+3 subsystems (Visibility, Timeframe, Refundable) and returns one Decision with the reasons of each subsystem.
+The rules of the services are loaded from JSON, see DEFAULT_SERVICE_RULES.
 */
 
 type IService interface {
 	//Create()
 	//Read()
 	//Update()
-	Applicable()
+	Applicable(b Booking) Decision
 }
 type Service struct {
-	IService      IService
-	Name          string
-	Desc          string
-	Priority      int8
-	VisibilityMap map[string]interface{}
+	Name          string                 `json:"name"`
+	Desc          string                 `json:"desc"`
+	Priority      int8                   `json:"priority"`
+	VisibilityMap map[string]interface{} `json:"-"`
+	Facade        ServiceFacade          `json:"rules"`
 }
 
-// implement IService for ServiceAbstract struct
-func (sa *Service) Applicable() {
-	fmt.Println("Service: Applicable()")
+const (
+	CANCELLED_BY_CUSTOMER = "CUSTOMER"
+	CANCELLED_BY_AIRLINE  = "AIRLINE"
+)
+
+// Booking the services are evaluated for, Amount is in minor units
+type Booking struct {
+	LOB                string        `json:"lob"`
+	Airline            string        `json:"airline"`
+	TravelDate         time.Time     `json:"travel_date"`
+	Amount             pricing.Money `json:"amount"`
+	CancellationReason string        `json:"cancellation_reason,omitempty"` // CUSTOMER, AIRLINE or empty if not cancelled
+	// At is the time of the request (ie the cancellation), now if not set
+	At time.Time `json:"at,omitempty"`
 }
 
-func ExecuteFacade() {
-	svc := &Service{}
-	svc.Name = "Premium Customer Support"
+type Decision struct {
+	Service      string        `json:"service"`
+	Applicable   bool          `json:"applicable"`
+	RefundAmount pricing.Money `json:"refund_amount"`
+	RefundBy     *time.Time    `json:"refund_by,omitempty"`
+	Reasons      []string      `json:"reasons"`
+}
 
-	sf := &ServiceFacade{
-		visibility: &Visibility{lob: "Flights", airlines: []string{"Indigo", "GoFirst"}},
-	}
-	fmt.Println(sf)
-	//svc.VisibilityMap = *sf.visibility
+// implement IService for Service struct
+func (svc *Service) Applicable(b Booking) (d Decision) {
+	d = svc.Facade.Evaluate(b)
+	d.Service = svc.Name
+	return
 }
 
 /*
 Facade of Service
 */
 type ServiceFacade struct {
-	visibility *Visibility
-	refundable *Refundable
-	timeframe  *Timeframe
+	Visibility *Visibility `json:"visibility,omitempty"`
+	Refundable *Refundable `json:"refundable,omitempty"`
+	Timeframe  *Timeframe  `json:"timeframe,omitempty"`
 }
 type Visibility struct {
-	lob      string
-	airlines []string
+	LOB      string   `json:"lob"`
+	Airlines []string `json:"airlines,omitempty"` // empty means all airlines
 }
 type Refundable struct {
-	customerCancel     bool
-	airlineCancel      bool
-	refundPercentage   int8
-	refundDurationDays int8
+	CustomerCancel     bool `json:"customer_cancel"`
+	AirlineCancel      bool `json:"airline_cancel"`
+	RefundPercentage   int8 `json:"refund_percentage"`
+	RefundDurationDays int8 `json:"refund_duration_days"`
 }
+
+/*
+Timeframe: the service is offered between StartTime and EndTime (zero means open ended),
+and can be used from DaysBeforeTravelDate before till DaysAfterTravelDate after the travel date.
+Both days zero means any day.
+*/
 type Timeframe struct {
-	StartTime            time.Time
-	EndTime              time.Time
-	DaysBeforeTravelDate int8
-	DaysAfterTravelDate  int8
+	StartTime            time.Time `json:"start_time"`
+	EndTime              time.Time `json:"end_time"`
+	DaysBeforeTravelDate int8      `json:"days_before_travel_date"`
+	DaysAfterTravelDate  int8      `json:"days_after_travel_date"`
+}
+
+/*
+Evaluate is the single entry point of the facade: the service applies if every configured subsystem allows it,
+the refund is only computed for an applicable service.
+*/
+func (sf *ServiceFacade) Evaluate(b Booking) (d Decision) {
+	if b.At.IsZero() {
+		b.At = time.Now()
+	}
+	d.Applicable, d.Reasons = true, []string{}
+
+	checks := []func(Booking) (bool, string){}
+	if sf.Visibility != nil {
+		checks = append(checks, sf.Visibility.check)
+	}
+	if sf.Timeframe != nil {
+		checks = append(checks, sf.Timeframe.check)
+	}
+	for _, check := range checks {
+		ok, reason := check(b)
+		d.Applicable = d.Applicable && ok
+		d.Reasons = append(d.Reasons, reason)
+	}
+
+	switch {
+	case !d.Applicable:
+		d.Reasons = append(d.Reasons, "refund: service not applicable")
+	case sf.Refundable == nil:
+		d.Reasons = append(d.Reasons, "refund: service is not refundable")
+	default:
+		var reason string
+		d.RefundAmount, d.RefundBy, reason = sf.Refundable.refund(b)
+		d.Reasons = append(d.Reasons, reason)
+	}
+	return
+}
+
+func (v *Visibility) check(b Booking) (ok bool, reason string) {
+	if !strings.EqualFold(v.LOB, b.LOB) {
+		return false, fmt.Sprintf("visibility: LOB %q is not %q", b.LOB, v.LOB)
+	}
+	if len(v.Airlines) == 0 {
+		return true, fmt.Sprintf("visibility: LOB %q, any airline", b.LOB)
+	}
+	for _, a := range v.Airlines {
+		if strings.EqualFold(a, b.Airline) {
+			return true, fmt.Sprintf("visibility: LOB %q and airline %q match", b.LOB, b.Airline)
+		}
+	}
+	return false, fmt.Sprintf("visibility: airline %q is not one of %v", b.Airline, v.Airlines)
+}
+
+func (tf *Timeframe) check(b Booking) (ok bool, reason string) {
+	if !tf.StartTime.IsZero() && b.At.Before(tf.StartTime) {
+		return false, fmt.Sprintf("timeframe: service starts on %v", tf.StartTime.Format(time.RFC3339))
+	}
+	if !tf.EndTime.IsZero() && b.At.After(tf.EndTime) {
+		return false, fmt.Sprintf("timeframe: service ended on %v", tf.EndTime.Format(time.RFC3339))
+	}
+	if tf.DaysBeforeTravelDate == 0 && tf.DaysAfterTravelDate == 0 {
+		return true, "timeframe: within the service period"
+	}
+
+	from := b.TravelDate.AddDate(0, 0, -int(tf.DaysBeforeTravelDate))
+	till := b.TravelDate.AddDate(0, 0, int(tf.DaysAfterTravelDate))
+	window := fmt.Sprintf("%s to %s", travelDay(-int(tf.DaysBeforeTravelDate)), travelDay(int(tf.DaysAfterTravelDate)))
+	if b.At.Before(from) || b.At.After(till) {
+		return false, "timeframe: request is not within " + window
+	}
+	return true, "timeframe: request is within " + window
+}
+
+func travelDay(days int) string {
+	switch {
+	case days < 0:
+		return fmt.Sprintf("%d day(s) before the travel date", -days)
+	case days > 0:
+		return fmt.Sprintf("%d day(s) after the travel date", days)
+	}
+	return "the travel date"
+}
+
+func (rf *Refundable) refund(b Booking) (amount pricing.Money, by *time.Time, reason string) {
+	switch {
+	case b.CancellationReason == "":
+		return 0, nil, "refund: booking is not cancelled"
+	case strings.EqualFold(b.CancellationReason, CANCELLED_BY_CUSTOMER) && !rf.CustomerCancel:
+		return 0, nil, "refund: not refundable on customer cancellation"
+	case strings.EqualFold(b.CancellationReason, CANCELLED_BY_AIRLINE) && !rf.AirlineCancel:
+		return 0, nil, "refund: not refundable on airline cancellation"
+	case !strings.EqualFold(b.CancellationReason, CANCELLED_BY_CUSTOMER) && !strings.EqualFold(b.CancellationReason, CANCELLED_BY_AIRLINE):
+		return 0, nil, fmt.Sprintf("refund: unknown cancellation reason %q", b.CancellationReason)
+	}
+
+	amount = pricing.BasisPoints(int64(rf.RefundPercentage) * 100).Of(b.Amount)
+	refundBy := b.At.AddDate(0, 0, int(rf.RefundDurationDays))
+	return amount, &refundBy, fmt.Sprintf("refund: %d%% of %v on %s cancellation, within %d day(s)",
+		rf.RefundPercentage, b.Amount, strings.ToLower(b.CancellationReason), rf.RefundDurationDays)
+}
+
+/*
+LoadServices reads the services and their rules from JSON, sorted by priority (1 first).
+VisibilityMap of every service is filled from its visibility rules.
+*/
+func LoadServices(data []byte) (services []*Service, err error) {
+	if err = json.Unmarshal(data, &services); err != nil {
+		return nil, fmt.Errorf("Invalid service rules: %w", err)
+	}
+	for i, svc := range services {
+		if svc == nil || svc.Name == "" {
+			return nil, fmt.Errorf("Invalid service rules: service %d has no name", i+1)
+		}
+		if rf := svc.Facade.Refundable; rf != nil && (rf.RefundPercentage < 0 || rf.RefundPercentage > 100) {
+			return nil, fmt.Errorf("Invalid service rules: %v refund percentage %d", svc.Name, rf.RefundPercentage)
+		}
+		svc.VisibilityMap = map[string]interface{}{}
+		if v := svc.Facade.Visibility; v != nil {
+			svc.VisibilityMap["lob"] = v.LOB
+			svc.VisibilityMap["airlines"] = v.Airlines
+		}
+	}
+	sort.SliceStable(services, func(i, j int) bool { return services[i].Priority < services[j].Priority })
+	return
+}
+
+// EvaluateServices returns the decision of every service for the booking, in order of priority
+func EvaluateServices(services []*Service, b Booking) (decisions []Decision) {
+	decisions = []Decision{}
+	for _, svc := range services {
+		decisions = append(decisions, svc.Applicable(b))
+	}
+	return
+}
+
+const DEFAULT_SERVICE_RULES = `[
+  {
+    "name": "Premium Customer Support",
+    "desc": "Priority support line for Indigo and GoFirst flights, from 7 days before till 2 days after travel",
+    "priority": 1,
+    "rules": {
+      "visibility": {"lob": "Flights", "airlines": ["Indigo", "GoFirst"]},
+      "timeframe": {"start_time": "2023-01-01T00:00:00Z", "days_before_travel_date": 7, "days_after_travel_date": 2},
+      "refundable": {"customer_cancel": false, "airline_cancel": true, "refund_percentage": 100, "refund_duration_days": 7}
+    }
+  },
+  {
+    "name": "Free Cancellation",
+    "desc": "Refund of 90% when the customer cancels up to 1 day before travel, on any airline",
+    "priority": 2,
+    "rules": {
+      "visibility": {"lob": "Flights"},
+      "timeframe": {"days_before_travel_date": 90, "days_after_travel_date": -1},
+      "refundable": {"customer_cancel": true, "airline_cancel": true, "refund_percentage": 90, "refund_duration_days": 5}
+    }
+  }
+]`
+
+func ExecuteFacade(w io.Writer) {
+	services, err := LoadServices([]byte(DEFAULT_SERVICE_RULES))
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return
+	}
+	for _, svc := range services {
+		fmt.Fprintf(w, "Service: %v, visibility: %v\n", svc.Name, svc.VisibilityMap)
+	}
+
+	now := time.Now()
+	bookings := []Booking{
+		{LOB: "Flights", Airline: "Indigo", TravelDate: now.AddDate(0, 0, 3), Amount: 5000_00, CancellationReason: CANCELLED_BY_AIRLINE},
+		{LOB: "Flights", Airline: "SpiceJet", TravelDate: now.AddDate(0, 0, 3), Amount: 5000_00, CancellationReason: CANCELLED_BY_CUSTOMER},
+		{LOB: "Flights", Airline: "Indigo", TravelDate: now.AddDate(0, 0, 30), Amount: 4200_00},
+		{LOB: "Hotels", Airline: "", TravelDate: now.AddDate(0, 0, 3), Amount: 3000_00, CancellationReason: CANCELLED_BY_CUSTOMER},
+	}
+	for _, b := range bookings {
+		fmt.Fprintf(w, "\nBooking: %v %v in %v day(s), %v, cancelled by: %q\n", b.LOB, b.Airline,
+			int(b.TravelDate.Sub(now).Hours()/24+0.5), b.Amount, b.CancellationReason)
+		for _, d := range EvaluateServices(services, b) {
+			fmt.Fprintf(w, "  %v: applicable: %v refund: %v\n", d.Service, d.Applicable, d.RefundAmount)
+			for _, r := range d.Reasons {
+				fmt.Fprintln(w, "    -", r)
+			}
+		}
+	}
 }
@@ -0,0 +1,96 @@
+package structural
+
+import (
+	"examples/pricing"
+	"testing"
+	"time"
+)
+
+func TestFacadeEvaluate(t *testing.T) {
+	services, err := LoadServices([]byte(DEFAULT_SERVICE_RULES))
+	if err != nil {
+		t.Fatal(err)
+	}
+	premium := services[0]
+	at := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		booking    Booking
+		applicable bool
+		refund     pricing.Money
+	}{
+		{
+			name:       "AirlineCancel-TC-1",
+			booking:    Booking{LOB: "Flights", Airline: "indigo", TravelDate: at.AddDate(0, 0, 3), Amount: 5000_00, CancellationReason: CANCELLED_BY_AIRLINE, At: at},
+			applicable: true,
+			refund:     5000_00,
+		},
+		{
+			name:       "CustomerCancel-TC-2",
+			booking:    Booking{LOB: "Flights", Airline: "GoFirst", TravelDate: at.AddDate(0, 0, 3), Amount: 5000_00, CancellationReason: CANCELLED_BY_CUSTOMER, At: at},
+			applicable: true,
+		},
+		{
+			name:    "OtherAirline-TC-3",
+			booking: Booking{LOB: "Flights", Airline: "SpiceJet", TravelDate: at.AddDate(0, 0, 3), Amount: 5000_00, CancellationReason: CANCELLED_BY_AIRLINE, At: at},
+		},
+		{
+			name:    "OtherLOB-TC-4",
+			booking: Booking{LOB: "Hotels", Airline: "Indigo", TravelDate: at.AddDate(0, 0, 3), Amount: 5000_00, At: at},
+		},
+		{
+			name:    "TooEarly-TC-5",
+			booking: Booking{LOB: "Flights", Airline: "Indigo", TravelDate: at.AddDate(0, 0, 8), Amount: 5000_00, At: at},
+		},
+		{
+			name:    "TooLate-TC-6",
+			booking: Booking{LOB: "Flights", Airline: "Indigo", TravelDate: at.AddDate(0, 0, -3), Amount: 5000_00, At: at},
+		},
+		{
+			name:    "BeforeStart-TC-7",
+			booking: Booking{LOB: "Flights", Airline: "Indigo", TravelDate: time.Date(2022, 12, 30, 0, 0, 0, 0, time.UTC), At: time.Date(2022, 12, 29, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := premium.Applicable(tc.booking)
+			if d.Applicable != tc.applicable || d.RefundAmount != tc.refund || len(d.Reasons) != 3 {
+				t.Errorf("Actual output: %+v, Expected applicable: %v refund: %v", d, tc.applicable, tc.refund)
+			}
+			if tc.refund > 0 && (d.RefundBy == nil || !d.RefundBy.Equal(at.AddDate(0, 0, 7))) {
+				t.Errorf("Expected refund within 7 days, got: %v", d.RefundBy)
+			}
+		})
+	}
+}
+
+func TestLoadServices(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rules string
+		valid bool
+	}{
+		{name: "Default-TC-1", rules: DEFAULT_SERVICE_RULES, valid: true},
+		{name: "NoRules-TC-2", rules: `[{"name": "Lounge"}]`, valid: true},
+		{name: "NoName-TC-3", rules: `[{"priority": 1}]`},
+		{name: "Percentage-TC-4", rules: `[{"name": "Lounge", "rules": {"refundable": {"refund_percentage": 101}}}]`},
+		{name: "Invalid-TC-5", rules: `{"name": "Lounge"}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LoadServices([]byte(tc.rules)); (err == nil) != tc.valid {
+				t.Errorf("Expected valid: %v, got: %v", tc.valid, err)
+			}
+		})
+	}
+
+	// a service without rules applies to every booking, with no refund
+	services, _ := LoadServices([]byte(`[{"name": "B", "priority": 2}, {"name": "A", "priority": 1}]`))
+	if services[0].Name != "A" {
+		t.Errorf("Expected services sorted by priority, got: %v", services[0].Name)
+	}
+	if d := services[0].Applicable(Booking{CancellationReason: CANCELLED_BY_AIRLINE}); !d.Applicable || d.RefundAmount != 0 {
+		t.Errorf("Unexpected decision: %+v", d)
+	}
+}
@@ -1,5 +1,16 @@
 package structural
 
+import (
+	"errors"
+	"examples/registry"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
 /*
 Refer: https://golangbyexample.com/flyweight-design-pattern-golang/
 
@@ -17,13 +28,157 @@ When to Use:
   In case figure out all the common or intrinsic state and create flyweight objects for that.
 */
 
+const (
+	POINTER_SIZE        = strconv.IntSize / 8 // same as the pointer size on the supported platforms
+	CREST_SIZE          = 1024                // bytes of the crest bitmap of a house
+	MAX_ROSTER_STUDENTS = 10_000              // about 10 MiB without flyweights, BenchmarkRoster loads larger rosters
+)
+
+var (
+	ErrUnknownHouse    = errors.New("Unknown house!")
+	ErrInvalidStudents = registry.InvalidArgs(fmt.Errorf("Students should be between 1 and %d!", MAX_ROSTER_STUDENTS))
+)
+
+/*
+FlyweightCache hands out one shared *V per key (the intrinsic state), creating it on the
+first request. It is safe for concurrent use; the values must be treated as immutable.
+*/
+type FlyweightCache[K comparable, V any] struct {
+	create func(K) (*V, error)
+
+	mulock sync.RWMutex
+	items  map[K]*V
+	bytes  int64 // estimated bytes held by the unique values
+
+	hits   int64
+	misses int64
+}
+
+type FlyweightStats struct {
+	Requests    int64 `json:"requests"`
+	Hits        int64 `json:"hits"`
+	Unique      int   `json:"unique"`
+	UniqueBytes int64 `json:"unique_bytes"`
+	// BytesSaved estimates the memory saved against every request holding its own copy
+	// rather than a pointer to the shared one; negative while there is nothing to share yet
+	BytesSaved int64 `json:"bytes_saved"`
+}
+
+func NewFlyweightCache[K comparable, V any](create func(K) (*V, error)) *FlyweightCache[K, V] {
+	return &FlyweightCache[K, V]{create: create, items: map[K]*V{}}
+}
+
+/*
+Get returns the shared value of the key. Errors of create are returned as is and not cached.
+*/
+func (fc *FlyweightCache[K, V]) Get(key K) (v *V, err error) {
+	fc.mulock.RLock()
+	v, ok := fc.items[key]
+	fc.mulock.RUnlock()
+	if ok {
+		atomic.AddInt64(&fc.hits, 1)
+		return
+	}
+
+	fc.mulock.Lock()
+	defer fc.mulock.Unlock()
+	// another goroutine might have created it, while waiting for the lock
+	if v, ok = fc.items[key]; ok {
+		atomic.AddInt64(&fc.hits, 1)
+		return
+	}
+	if v, err = fc.create(key); err != nil {
+		return nil, err
+	}
+	fc.items[key] = v
+	fc.bytes += estimateSize(reflect.ValueOf(v).Elem())
+	atomic.AddInt64(&fc.misses, 1)
+	return
+}
+
+func (fc *FlyweightCache[K, V]) Len() int {
+	fc.mulock.RLock()
+	defer fc.mulock.RUnlock()
+	return len(fc.items)
+}
+
+func (fc *FlyweightCache[K, V]) Stats() (s FlyweightStats) {
+	fc.mulock.RLock()
+	s.Unique, s.UniqueBytes = len(fc.items), fc.bytes
+	fc.mulock.RUnlock()
+
+	s.Hits = atomic.LoadInt64(&fc.hits)
+	s.Requests = s.Hits + atomic.LoadInt64(&fc.misses)
+	if s.Unique > 0 {
+		average := s.UniqueBytes / int64(s.Unique)
+		s.BytesSaved = s.Requests*average - s.UniqueBytes - s.Requests*POINTER_SIZE
+	}
+	return
+}
+
+/*
+estimateSize is the size of the value along with the memory it references via strings,
+slices and pointers, a pointed value being counted once however many pointers share it.
+Maps, channels and functions are counted by their header only.
+*/
+func estimateSize(v reflect.Value) int64 {
+	return sizeOf(v, map[uintptr]bool{})
+}
+
+func sizeOf(v reflect.Value, seen map[uintptr]bool) int64 {
+	return int64(v.Type().Size()) + referencedSize(v, seen)
+}
+
+func referencedSize(v reflect.Value, seen map[uintptr]bool) (size int64) {
+	switch v.Kind() {
+	case reflect.String:
+		size = int64(v.Len())
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		size = int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			size += referencedSize(v.Index(i), seen)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			size += referencedSize(v.Index(i), seen)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			size += referencedSize(v.Field(i), seen)
+		}
+	case reflect.Pointer:
+		if !v.IsNil() && !seen[v.Pointer()] {
+			seen[v.Pointer()] = true
+			size = sizeOf(v.Elem(), seen)
+		}
+	}
+	return
+}
+
+// House is the intrinsic state, shared by all the students of the house
 type House struct {
-	Name  HouseNameEnum
-	Color string
+	Name  HouseNameEnum `json:"name"`
+	Color string        `json:"color"`
+	Motto string        `json:"motto"`
+	Crest []byte        `json:"-"` // bitmap printed on the badges
 }
 
+// Student carries the extrinsic state and points to its (shared) house
 type Student struct {
+	House      *House
+	RollNo     int
+	SchoolName string
+	Class      int
+	Section    string
+}
+
+// studentCopy is a student holding its own copy of the house, ie without flyweights
+type studentCopy struct {
 	House      House
+	RollNo     int
 	SchoolName string
 	Class      int
 	Section    string
@@ -38,25 +193,134 @@ const (
 	HOUSE_AGNI    HouseNameEnum = "AGNI"
 )
 
-// Initialise immutable object map of house objects
-type HouseFactory struct {
-	houseMap map[HouseNameEnum]House
-}
-
-func (hm *HouseFactory) InitializeHouseMap(houseName HouseNameEnum) House {
-	if _, ok := hm.houseMap[houseName]; ok == true {
-		return hm.houseMap[houseName]
-	}
+var (
+	houses   = []HouseNameEnum{HOUSE_PRITHVI, HOUSE_JAL, HOUSE_AAKASH, HOUSE_AGNI}
+	sections = []string{"A", "B", "C", "D"}
+)
 
+/*
+newHouse builds the house from scratch, as it would be when loaded from the DB
+*/
+func newHouse(houseName HouseNameEnum) (h *House, err error) {
 	switch houseName {
 	case HOUSE_PRITHVI:
-		hm.houseMap[houseName] = House{Name: HOUSE_PRITHVI, Color: "Green"}
+		h = &House{Name: HOUSE_PRITHVI, Color: "Green", Motto: "Steady as the earth"}
 	case HOUSE_JAL:
-		hm.houseMap[houseName] = House{Name: HOUSE_JAL, Color: "White"}
+		h = &House{Name: HOUSE_JAL, Color: "White", Motto: "Flow around every obstacle"}
 	case HOUSE_AAKASH:
-		hm.houseMap[houseName] = House{Name: HOUSE_AAKASH, Color: "Blue"}
+		h = &House{Name: HOUSE_AAKASH, Color: "Blue", Motto: "No limit but the sky"}
 	case HOUSE_AGNI:
-		hm.houseMap[houseName] = House{Name: HOUSE_AGNI, Color: "Red"}
+		h = &House{Name: HOUSE_AGNI, Color: "Red", Motto: "Burn bright"}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownHouse, houseName)
+	}
+	h.Crest = make([]byte, CREST_SIZE)
+	for i := range h.Crest {
+		h.Crest[i] = h.Color[i%len(h.Color)]
+	}
+	return
+}
+
+// HouseFactory hands out the shared, immutable house objects
+type HouseFactory struct {
+	cache *FlyweightCache[HouseNameEnum, House]
+}
+
+func NewHouseFactory() *HouseFactory {
+	return &HouseFactory{cache: NewFlyweightCache(newHouse)}
+}
+
+func (hf *HouseFactory) GetHouse(houseName HouseNameEnum) (*House, error) {
+	return hf.cache.Get(houseName)
+}
+
+func (hf *HouseFactory) Stats() FlyweightStats {
+	return hf.cache.Stats()
+}
+
+/*
+LoadRoster creates n students spread across the houses, sharing the houses of the factory
+*/
+func LoadRoster(hf *HouseFactory, n int) (students []Student, err error) {
+	students = make([]Student, n)
+	for i := range students {
+		house, err := hf.GetHouse(houses[i%len(houses)])
+		if err != nil {
+			return nil, err
+		}
+		students[i] = Student{House: house, RollNo: i + 1, SchoolName: "DPS", Class: i%12 + 1, Section: sections[i%len(sections)]}
+	}
+	return
+}
+
+/*
+loadRosterCopies creates n students, each with its own copy of the house
+*/
+func loadRosterCopies(n int) (students []studentCopy, err error) {
+	students = make([]studentCopy, n)
+	for i := range students {
+		house, err := newHouse(houses[i%len(houses)])
+		if err != nil {
+			return nil, err
+		}
+		students[i] = studentCopy{House: *house, RollNo: i + 1, SchoolName: "DPS", Class: i%12 + 1, Section: sections[i%len(sections)]}
+	}
+	return
+}
+
+type RosterReport struct {
+	Students int `json:"students"`
+	// bytes of the loaded roster as estimated by estimateSize, BenchmarkRoster measures the heap
+	WithFlyweight    int64          `json:"with_flyweight_bytes"`
+	WithoutFlyweight int64          `json:"without_flyweight_bytes"`
+	Saved            int64          `json:"saved_bytes"`
+	Cache            FlyweightStats `json:"cache"`
+}
+
+/*
+MeasureRoster loads n students with and without flyweights and reports the memory used by each.
+It only estimates the sizes, rather than forcing a GC around every load.
+*/
+func MeasureRoster(n int) (report RosterReport, err error) {
+	if n < 1 || n > MAX_ROSTER_STUDENTS {
+		return report, ErrInvalidStudents
+	}
+
+	hf := NewHouseFactory()
+	report.Students = n
+	shared, err := LoadRoster(hf, n)
+	if err != nil {
+		return
+	}
+	copies, err := loadRosterCopies(n)
+	if err != nil {
+		return
+	}
+	report.WithFlyweight = estimateSize(reflect.ValueOf(shared))
+	report.WithoutFlyweight = estimateSize(reflect.ValueOf(copies))
+	report.Saved = report.WithoutFlyweight - report.WithFlyweight
+	report.Cache = hf.Stats()
+	return
+}
+
+func ExecuteFlyweight(w io.Writer, students int) (err error) {
+	hf := NewHouseFactory()
+	for _, name := range []HouseNameEnum{HOUSE_AGNI, HOUSE_JAL, HOUSE_AGNI} {
+		house, _ := hf.GetHouse(name)
+		fmt.Fprintf(w, "House %v (%v) at %p\n", house.Name, house.Color, house)
+	}
+	if _, err = hf.GetHouse("VAYU"); err != nil {
+		fmt.Fprintln(w, "Error:", err)
+	}
+
+	report, err := MeasureRoster(students)
+	if err != nil {
+		return
 	}
-	return hm.houseMap[houseName]
+	fmt.Fprintf(w, "\n%d students\n", report.Students)
+	fmt.Fprintf(w, "with flyweight    : %d KiB\n", report.WithFlyweight/1024)
+	fmt.Fprintf(w, "without flyweight : %d KiB\n", report.WithoutFlyweight/1024)
+	fmt.Fprintf(w, "saved             : %d KiB (cache estimate %d KiB)\n", report.Saved/1024, report.Cache.BytesSaved/1024)
+	fmt.Fprintf(w, "cache             : %d requests, %d hits, %d unique houses\n", report.Cache.Requests, report.Cache.Hits, report.Cache.Unique)
+	return
 }
@@ -0,0 +1,129 @@
+package structural
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestFlyweightCache(t *testing.T) {
+	hf := NewHouseFactory()
+	wg := sync.WaitGroup{}
+	got := make([]*House, 100)
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i], _ = hf.GetHouse(houses[i%len(houses)])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, house := range got {
+		if house != got[i%len(houses)] {
+			t.Fatalf("Expected the same %v house to be shared", house.Name)
+		}
+	}
+	stats := hf.Stats()
+	if stats.Requests != 100 || stats.Hits != 96 || stats.Unique != 4 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.BytesSaved <= 96*CREST_SIZE-4*CREST_SIZE {
+		t.Errorf("Expected at least the crests of the hits to be saved, got: %v", stats.BytesSaved)
+	}
+}
+
+func TestFlyweightCacheError(t *testing.T) {
+	hf := NewHouseFactory()
+	testCases := []struct {
+		name     string
+		house    HouseNameEnum
+		expected error
+	}{
+		{name: "Known-TC-1", house: HOUSE_JAL},
+		{name: "Unknown-TC-2", house: "VAYU", expected: ErrUnknownHouse},
+		{name: "UnknownAgain-TC-3", house: "VAYU", expected: ErrUnknownHouse},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := hf.GetHouse(tc.house); !errors.Is(err, tc.expected) {
+				t.Errorf("Expected: %v, got: %v", tc.expected, err)
+			}
+		})
+	}
+	if hf.cache.Len() != 1 {
+		t.Errorf("Expected errors not to be cached, got: %v houses", hf.cache.Len())
+	}
+}
+
+func TestMeasureRoster(t *testing.T) {
+	report, err := MeasureRoster(MAX_ROSTER_STUDENTS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every copy holds its own crest, the roster with flyweights shares 4
+	if report.Saved < 10000*CREST_SIZE/2 || report.Cache.Unique != 4 || report.Cache.Hits != 9996 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	for _, n := range []int{0, MAX_ROSTER_STUDENTS + 1} {
+		if _, err = MeasureRoster(n); !errors.Is(err, ErrInvalidStudents) {
+			t.Errorf("Expected: %v for %d students, got: %v", ErrInvalidStudents, n, err)
+		}
+	}
+}
+
+/*
+heapInUse returns the heap retained by whatever load allocates and returns.
+*/
+func heapInUse(load func() (interface{}, error)) (int64, error) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	v, err := load()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(v)
+	return int64(after.HeapAlloc) - int64(before.HeapAlloc), err
+}
+
+/*
+BenchmarkRoster loads a roster 10 times the size allowed by MeasureRoster and reports the heap
+it retains, along with the estimate of MeasureRoster:
+
+	go test -run NONE -bench Roster ./patterns/structural/
+*/
+func BenchmarkRoster(b *testing.B) {
+	const students = 10 * MAX_ROSTER_STUDENTS
+	b.Run("WithFlyweight", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			LoadRoster(NewHouseFactory(), students)
+		}
+	})
+	b.Run("WithoutFlyweight", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			loadRosterCopies(students)
+		}
+	})
+	b.Run("HeapInUse", func(b *testing.B) {
+		var with, without int64
+		var err error
+		for i := 0; i < b.N; i++ {
+			if with, err = heapInUse(func() (interface{}, error) { return LoadRoster(NewHouseFactory(), students) }); err != nil {
+				b.Fatal(err)
+			}
+			if without, err = heapInUse(func() (interface{}, error) { return loadRosterCopies(students) }); err != nil {
+				b.Fatal(err)
+			}
+		}
+		report, err := MeasureRoster(MAX_ROSTER_STUDENTS)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportMetric(float64(with)/students, "heap-B/student")
+		b.ReportMetric(float64(without)/students, "heap-copy-B/student")
+		b.ReportMetric(float64(report.WithoutFlyweight)/MAX_ROSTER_STUDENTS, "estimated-copy-B/student")
+	})
+}
@@ -0,0 +1,47 @@
+package structural
+
+import (
+	"context"
+	"examples/registry"
+	"io"
+)
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "pattern/structural/bridge",
+		Title:       "Bridge Pattern",
+		Category:    registry.CATEGORY_STRUCTURAL,
+		Description: "Email/Sms notifications bridged at runtime with any vendor (Xvendor/Yvendor)",
+		Source:      "patterns/structural/bridge.go",
+		Run:         registry.Simple(Execute),
+	})
+	registry.Register(registry.Example{
+		Name:        "pattern/structural/decorator",
+		Title:       "Decorator Pattern",
+		Category:    registry.CATEGORY_STRUCTURAL,
+		Description: "Decorate pizzas with toppings without changing the base pizza types",
+		Source:      "patterns/structural/decorator.go",
+		Run:         registry.Simple(ExecuteDecorator),
+	})
+	registry.Register(registry.Example{
+		Name:        "pattern/structural/facade",
+		Title:       "Facade Pattern",
+		Category:    registry.CATEGORY_STRUCTURAL,
+		Description: "Evaluate travel services for bookings via one facade over the visibility, timeframe and refund rules loaded from JSON",
+		Source:      "patterns/structural/facade.go",
+		Run:         registry.Simple(ExecuteFacade),
+	})
+	registry.Register(registry.Example{
+		Name:        "pattern/structural/flyweight",
+		Title:       "Flyweight Pattern",
+		Category:    registry.CATEGORY_STRUCTURAL,
+		Description: "Students share the immutable house objects of a concurrency-safe flyweight cache, measuring the memory saved",
+		Source:      "patterns/structural/flyweight.go",
+		Params: []registry.Param{
+			{Name: "students", Type: registry.PARAM_INT, Default: "1000", Usage: "students to load in the roster, at most 10000"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return ExecuteFlyweight(w, args.Int("students"))
+		},
+	})
+}
@@ -0,0 +1,137 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+Pipeline ties the stages composed on it to one context and one error.
+
+Every stage runs in its own goroutine(s) and closes its output channel once its input is
+exhausted or the pipeline is cancelled. The first stage failing (ie a Map function returning an
+error) cancels the whole pipeline, the error is returned by Wait once all the stages are done.
+
+	p := pipeline.New(ctx)
+	squares := pipeline.Map(p, pipeline.Source(p, iterator.FromSlice(numbers)), square)
+	values, err := pipeline.Collect(p, squares)
+
+A consumer stopping early should call Cancel, so the stages are not left blocked on their sends.
+*/
+type Pipeline struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+}
+
+func New(ctx context.Context) *Pipeline {
+	p := &Pipeline{}
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	return p
+}
+
+// Context is cancelled once a stage fails or the pipeline is cancelled
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Cancel stops all the stages, Wait returns context.Canceled unless a stage failed before
+func (p *Pipeline) Cancel() {
+	p.fail(context.Canceled)
+}
+
+/*
+Go runs a stage, an error returned by it cancels the pipeline. It is how the stages below are
+built, custom stages can use it as well.
+*/
+func (p *Pipeline) Go(stage func(ctx context.Context) error) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		if err := stage(p.ctx); err != nil {
+			p.fail(err)
+		}
+	}()
+}
+
+/*
+Wait waits for all the stages to exit and returns the first error. A stage only exits once its
+output is read, hence Wait is called after draining the last stage, see Collect.
+The cancellation of the parent context is an error as well.
+*/
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	if err := p.ctx.Err(); err != nil {
+		p.fail(err)
+	}
+	p.cancel()
+	return p.err
+}
+
+func (p *Pipeline) fail(err error) {
+	p.errOnce.Do(func() {
+		p.err = err
+		p.cancel()
+	})
+}
+
+// send sends v unless the context is done first
+func send[T any](ctx context.Context, ch chan<- T, v T) bool {
+	select {
+	case ch <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+/*
+OrDone reads from in till it is closed or ctx is done, so a range over it never outlives the context
+even when the sender of in does not watch the context.
+*/
+func OrDone[T any](ctx context.Context, in <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok || !send(ctx, out, v) {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+/*
+Collect drains the stage and returns its values along with the error of the pipeline.
+*/
+func Collect[T any](p *Pipeline, in <-chan T) (values []T, err error) {
+	for v := range in {
+		values = append(values, v)
+	}
+	return values, p.Wait()
+}
+
+/*
+ForEach calls fn for every value of the stage, an error returned by fn cancels the pipeline.
+*/
+func ForEach[T any](p *Pipeline, in <-chan T, fn func(T) error) error {
+	for v := range in {
+		if err := fn(v); err != nil {
+			p.fail(err)
+			break
+		}
+	}
+	// unblock the stages, which might be sending still
+	for range in {
+	}
+	return p.Wait()
+}
@@ -0,0 +1,155 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"examples/iterator"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+var errTooBig = errors.New("Too big!")
+
+func source(p *Pipeline, n int) <-chan int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = i + 1
+	}
+	return Source(p, iterator.FromSlice(values))
+}
+
+func double(ctx context.Context, v int) (int, error) {
+	return v * 2, nil
+}
+
+func TestStages(t *testing.T) {
+	testCases := []struct {
+		name            string
+		build           func(p *Pipeline) <-chan interface{}
+		output_expected []interface{}
+	}{
+		{name: "Map-TC-1", build: func(p *Pipeline) <-chan interface{} {
+			return box(p, Map(p, source(p, 3), double))
+		}, output_expected: []interface{}{2, 4, 6}},
+		{name: "Filter-TC-2", build: func(p *Pipeline) <-chan interface{} {
+			return box(p, Filter(p, source(p, 6), func(v int) bool { return v%2 == 0 }))
+		}, output_expected: []interface{}{2, 4, 6}},
+		{name: "FlatMap-TC-3", build: func(p *Pipeline) <-chan interface{} {
+			return box(p, FlatMap(p, source(p, 3), func(ctx context.Context, v int) ([]int, error) {
+				return []int{v, -v}, nil
+			}))
+		}, output_expected: []interface{}{1, -1, 2, -2, 3, -3}},
+		{name: "Batch-TC-4", build: func(p *Pipeline) <-chan interface{} {
+			return box(p, Batch(p, source(p, 5), 2, 0))
+		}, output_expected: []interface{}{[]int{1, 2}, []int{3, 4}, []int{5}}},
+		{name: "Window-TC-5", build: func(p *Pipeline) <-chan interface{} {
+			return box(p, Window(p, source(p, 5), 3, 1))
+		}, output_expected: []interface{}{[]int{1, 2, 3}, []int{2, 3, 4}, []int{3, 4, 5}}},
+		{name: "WindowTumbling-TC-6", build: func(p *Pipeline) <-chan interface{} {
+			return box(p, Window(p, source(p, 7), 2, 3))
+		}, output_expected: []interface{}{[]int{1, 2}, []int{4, 5}}},
+		{name: "FanOutOrdered-TC-7", build: func(p *Pipeline) <-chan interface{} {
+			return box(p, FanOut(p, source(p, 20), 4, func(ctx context.Context, v int) (int, error) {
+				time.Sleep(time.Duration(20-v) * time.Millisecond)
+				return v * v, nil
+			}, Ordered()))
+		}, output_expected: []interface{}{1, 4, 9, 16, 25, 36, 49, 64, 81, 100, 121, 144, 169, 196, 225, 256, 289, 324, 361, 400}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(context.Background())
+			output, err := Collect(p, tc.build(p))
+			if err != nil || !reflect.DeepEqual(output, tc.output_expected) {
+				t.Errorf("Expected: %v, got: %v %v", tc.output_expected, output, err)
+			}
+		})
+	}
+}
+
+func box[T any](p *Pipeline, in <-chan T) <-chan interface{} {
+	return Map(p, in, func(ctx context.Context, v T) (interface{}, error) { return v, nil })
+}
+
+func TestFanOutMergeTee(t *testing.T) {
+	p := New(context.Background())
+	a, b := Tee(p, FanOut(p, source(p, 10), 3, double))
+	merged := Merge(p, a, Map(p, b, func(ctx context.Context, v int) (int, error) { return -v, nil }))
+	output, err := Collect(p, merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Ints(output)
+	expected := []int{-20, -18, -16, -14, -12, -10, -8, -6, -4, -2, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, output)
+	}
+}
+
+func TestBatchMaxWait(t *testing.T) {
+	p := New(context.Background())
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		in <- 1
+		time.Sleep(50 * time.Millisecond) // the partial batch is flushed meanwhile
+		in <- 2
+		in <- 3
+	}()
+	output, err := Collect(p, Batch(p, in, 2, 10*time.Millisecond))
+	if expected := [][]int{{1}, {2, 3}}; err != nil || !reflect.DeepEqual(output, expected) {
+		t.Errorf("Expected: %v, got: %v %v", expected, output, err)
+	}
+}
+
+func TestErrorPropagation(t *testing.T) {
+	p := New(context.Background())
+	out := FanOut(p, source(p, 1000), 4, func(ctx context.Context, v int) (int, error) {
+		if v == 10 {
+			return 0, errTooBig
+		}
+		return v, nil
+	})
+	output, err := Collect(p, Map(p, out, double))
+	if !errors.Is(err, errTooBig) || len(output) >= 1000 {
+		t.Errorf("Expected: %v, got: %v after %v values", errTooBig, err, len(output))
+	}
+
+	p = New(context.Background())
+	count := 0
+	err = ForEach(p, source(p, 1000), func(v int) error {
+		if count++; v == 5 {
+			return errTooBig
+		}
+		return nil
+	})
+	if !errors.Is(err, errTooBig) || count != 5 {
+		t.Errorf("Expected: %v after 5 values, got: %v after %v", errTooBig, err, count)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := New(ctx)
+	out := Map(p, source(p, 1000), double)
+	<-out
+	cancel()
+	if _, err := Collect(p, out); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected: %v, got: %v", context.Canceled, err)
+	}
+
+	// a sender which does not watch the context
+	in := make(chan int)
+	ctx, cancel = context.WithCancel(context.Background())
+	done := OrDone(ctx, in)
+	cancel()
+	select {
+	case _, ok := <-done:
+		if ok {
+			t.Error("Expected no value")
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected OrDone to close once cancelled")
+	}
+}
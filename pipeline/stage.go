@@ -0,0 +1,306 @@
+package pipeline
+
+import (
+	"context"
+	"examples/iterator"
+	"sync"
+	"time"
+)
+
+// Source emits the values of the iterator, ie iterator.FromSlice or the Iter of a collection
+func Source[T any](p *Pipeline, it iterator.Iterator[T]) <-chan T {
+	out := make(chan T)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+		for v, ok := it.Next(); ok; v, ok = it.Next() {
+			if !send(ctx, out, v) {
+				return nil
+			}
+		}
+		return nil
+	})
+	return out
+}
+
+// Map emits fn of every value, an error of fn cancels the pipeline
+func Map[In, Out any](p *Pipeline, in <-chan In, fn func(context.Context, In) (Out, error)) <-chan Out {
+	out := make(chan Out)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+		for v := range OrDone(ctx, in) {
+			res, err := fn(ctx, v)
+			if err != nil {
+				return err
+			}
+			if !send(ctx, out, res) {
+				return nil
+			}
+		}
+		return nil
+	})
+	return out
+}
+
+// Filter emits the values for which keep returns true
+func Filter[T any](p *Pipeline, in <-chan T, keep func(T) bool) <-chan T {
+	out := make(chan T)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+		for v := range OrDone(ctx, in) {
+			if keep(v) && !send(ctx, out, v) {
+				return nil
+			}
+		}
+		return nil
+	})
+	return out
+}
+
+// FlatMap emits each of the values fn returns for a value, an error of fn cancels the pipeline
+func FlatMap[In, Out any](p *Pipeline, in <-chan In, fn func(context.Context, In) ([]Out, error)) <-chan Out {
+	out := make(chan Out)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+		for v := range OrDone(ctx, in) {
+			res, err := fn(ctx, v)
+			if err != nil {
+				return err
+			}
+			for _, r := range res {
+				if !send(ctx, out, r) {
+					return nil
+				}
+			}
+		}
+		return nil
+	})
+	return out
+}
+
+/*
+Batch groups the values in slices of size. When maxWait is set, a batch is emitted as well once
+its first value has waited for maxWait, so a slow input does not hold back a partial batch.
+The last batch might be partial.
+*/
+func Batch[T any](p *Pipeline, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	if size < 1 {
+		size = 1
+	}
+	out := make(chan []T)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+		var batch []T
+		var timeout <-chan time.Time
+		var timer *time.Timer
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+			b := batch
+			batch = nil
+			return send(ctx, out, b)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-timeout:
+				if !flush() {
+					return nil
+				}
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return nil
+				}
+				batch = append(batch, v)
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					timeout = timer.C
+				}
+				if len(batch) == size && !flush() {
+					return nil
+				}
+			}
+		}
+	})
+	return out
+}
+
+/*
+Window emits sliding windows of size values, moving by step values, ie size 3 and step 1 over
+1..5 emits [1 2 3] [2 3 4] [3 4 5]. With step equal to size the windows do not overlap.
+Values left over at the end, not filling a window, are not emitted.
+*/
+func Window[T any](p *Pipeline, in <-chan T, size, step int) <-chan []T {
+	if size < 1 {
+		size = 1
+	}
+	if step < 1 {
+		step = 1
+	}
+	out := make(chan []T)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+		var window []T
+		skip := 0 // values to drop, when step is beyond size
+		for v := range OrDone(ctx, in) {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			window = append(window, v)
+			if len(window) < size {
+				continue
+			}
+			w := make([]T, size)
+			copy(w, window)
+			if !send(ctx, out, w) {
+				return nil
+			}
+			if step >= size {
+				window, skip = window[:0], step-size
+			} else {
+				window = window[step:]
+			}
+		}
+		return nil
+	})
+	return out
+}
+
+// Merge emits the values of all the inputs, in the order they arrive
+func Merge[T any](p *Pipeline, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	wg := sync.WaitGroup{}
+	for _, in := range ins {
+		in := in
+		wg.Add(1)
+		p.Go(func(ctx context.Context) error {
+			defer wg.Done()
+			for v := range OrDone(ctx, in) {
+				if !send(ctx, out, v) {
+					return nil
+				}
+			}
+			return nil
+		})
+	}
+	p.Go(func(ctx context.Context) error {
+		wg.Wait()
+		close(out)
+		return nil
+	})
+	return out
+}
+
+/*
+Tee emits every value on both the outputs, a value is sent to both before the next one is read,
+so both the outputs need to be read.
+*/
+func Tee[T any](p *Pipeline, in <-chan T) (<-chan T, <-chan T) {
+	out1, out2 := make(chan T), make(chan T)
+	p.Go(func(ctx context.Context) error {
+		defer close(out1)
+		defer close(out2)
+		for v := range OrDone(ctx, in) {
+			o1, o2 := out1, out2
+			for o1 != nil || o2 != nil {
+				select {
+				case <-ctx.Done():
+					return nil
+				case o1 <- v:
+					o1 = nil
+				case o2 <- v:
+					o2 = nil
+				}
+			}
+		}
+		return nil
+	})
+	return out1, out2
+}
+
+type fanOutOptions struct {
+	ordered bool
+}
+
+type FanOutOption func(*fanOutOptions)
+
+// Ordered makes FanOut emit the results in the order of the input, rather than as they complete
+func Ordered() FanOutOption {
+	return func(o *fanOutOptions) { o.ordered = true }
+}
+
+type sequenced[T any] struct {
+	seq   int
+	value T
+}
+
+/*
+FanOut runs fn on n workers, for a stage which is much slower than the others. The results are
+merged back (fan-in) as they complete, or in the order of the input with the Ordered option.
+An error of fn cancels the pipeline.
+*/
+func FanOut[In, Out any](p *Pipeline, in <-chan In, n int, fn func(context.Context, In) (Out, error), opts ...FanOutOption) <-chan Out {
+	o := fanOutOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	// number the inputs, so the results can be put back in order
+	seqIn := make(chan sequenced[In])
+	p.Go(func(ctx context.Context) error {
+		defer close(seqIn)
+		seq := 0
+		for v := range OrDone(ctx, in) {
+			if !send(ctx, seqIn, sequenced[In]{seq, v}) {
+				return nil
+			}
+			seq++
+		}
+		return nil
+	})
+
+	workers := make([]<-chan sequenced[Out], n)
+	for i := range workers {
+		workers[i] = Map(p, seqIn, func(ctx context.Context, v sequenced[In]) (sequenced[Out], error) {
+			res, err := fn(ctx, v.value)
+			return sequenced[Out]{v.seq, res}, err
+		})
+	}
+	merged := Merge(p, workers...)
+
+	out := make(chan Out)
+	p.Go(func(ctx context.Context) error {
+		defer close(out)
+		pending := map[int]Out{}
+		next := 0
+		for r := range OrDone(ctx, merged) {
+			if !o.ordered {
+				if !send(ctx, out, r.value) {
+					return nil
+				}
+				continue
+			}
+			pending[r.seq] = r.value
+			for v, ok := pending[next]; ok; v, ok = pending[next] {
+				delete(pending, next)
+				if !send(ctx, out, v) {
+					return nil
+				}
+				next++
+			}
+		}
+		return nil
+	})
+	return out
+}
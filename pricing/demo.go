@@ -0,0 +1,34 @@
+package pricing
+
+import "sync"
+
+var (
+	demoOnce sync.Once
+	demoMenu *Menu
+)
+
+// Demo menu shared by the examples and the API, with the pizzas of the decorator example
+func Demo() *Menu {
+	demoOnce.Do(func() {
+		m := NewMenu("INR")
+		m.AddBase("margherita", 100_00)
+		m.AddBase("farmhouse", 200_00)
+
+		m.AddDecorator(KIND_SIZE, "small", Percent(-2000))
+		m.AddDecorator(KIND_SIZE, "medium", Percent(0))
+		m.AddDecorator(KIND_SIZE, "large", Percent(3000))
+
+		m.AddDecorator(KIND_TOPPING, "cheese", Fixed(20_00))
+		m.AddDecorator(KIND_TOPPING, "mushroom", Fixed(30_00))
+		m.AddDecorator(KIND_TOPPING, "jalapeno", Fixed(50_00))
+
+		m.AddDecorator(KIND_COMBO, "meal", Fixed(79_00)) // coke + garlic bread, 99.00 separately
+
+		m.AddDecorator(KIND_DISCOUNT, "WELCOME20", UpTo(Percent(-2000), 100_00))
+		m.AddDecorator(KIND_DISCOUNT, "FLAT50", Fixed(-50_00))
+
+		m.AddDecorator(KIND_TAX, "GST", Percent(500))
+		demoMenu = m
+	})
+	return demoMenu
+}
@@ -0,0 +1,102 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+Money is an amount in minor units (paise/cents), so prices are only added and multiplied as integers.
+Percentages are basis points (1% = 100 bps) and are rounded half away from zero to the minor unit.
+*/
+type Money int64
+
+var ErrOverflow = errors.New("Amount overflow!")
+
+// Add returns m + o, ErrOverflow beyond the range of Money
+func (m Money) Add(o Money) (Money, error) {
+	if (o > 0 && m > math.MaxInt64-o) || (o < 0 && m < math.MinInt64-o) {
+		return 0, fmt.Errorf("%w: %v + %v", ErrOverflow, m, o)
+	}
+	return m + o, nil
+}
+
+// Times returns m x n, ErrOverflow beyond the range of Money
+func (m Money) Times(n int64) (Money, error) {
+	if n == 0 || m == 0 {
+		return 0, nil
+	}
+	p := m * Money(n)
+	if p/Money(n) != m || (m == -1 && n == math.MinInt64) || (n == -1 && m == math.MinInt64) {
+		return 0, fmt.Errorf("%w: %v x %d", ErrOverflow, m, n)
+	}
+	return p, nil
+}
+
+// String formats the amount with 2 decimals ie 12050 -> "120.50"
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign, m = "-", -m
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, m/100, m%100)
+}
+
+// ParseMoney parses "120.50" or "120" into minor units
+func ParseMoney(s string) (m Money, err error) {
+	units, err := parseDecimal(s, 2)
+	return Money(units), err
+}
+
+type BasisPoints int64
+
+func (bps BasisPoints) String() string {
+	return strings.TrimSuffix(strings.TrimRight(Money(bps).String(), "0"), ".") + "%"
+}
+
+// ParsePercent parses "12.5" (%) into basis points
+func ParsePercent(s string) (bps BasisPoints, err error) {
+	units, err := parseDecimal(s, 2)
+	return BasisPoints(units), err
+}
+
+/*
+Of returns the percentage of an amount, rounded half away from zero.
+The amount is split by 10000 first, so m x bps does not overflow for a large amount.
+*/
+func (bps BasisPoints) Of(m Money) Money {
+	neg := (m < 0) != (bps < 0)
+	a, b := int64(m), int64(bps)
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	p := (a/10000)*b + ((a%10000)*b+5000)/10000
+	if neg {
+		return Money(-p)
+	}
+	return Money(p)
+}
+
+// parseDecimal parses a decimal with at most scale fraction digits into an integer of 10^-scale units
+func parseDecimal(s string, scale int) (units int64, err error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	intPart, fracPart, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if intPart == "" || len(fracPart) > scale || strings.HasPrefix(fracPart, "+") || strings.HasPrefix(fracPart, "-") {
+		return 0, fmt.Errorf("Invalid amount %q, at most %d decimals allowed", s, scale)
+	}
+	fracPart += strings.Repeat("0", scale-len(fracPart))
+	if units, err = strconv.ParseInt(intPart+fracPart, 10, 64); err != nil || strings.HasPrefix(intPart, "+") {
+		return 0, fmt.Errorf("Invalid amount %q", s)
+	}
+	if neg {
+		units = -units
+	}
+	return
+}
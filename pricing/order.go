@@ -0,0 +1,162 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+/*
+OrderSpec is the JSON of an order, decorators are applied in the given order:
+
+	{
+	  "items": [
+	    {"base": "margherita", "quantity": 2, "decorators": [
+	      {"kind": "size", "name": "large"},
+	      {"kind": "topping", "name": "cheese", "quantity": 2}
+	    ]}
+	  ],
+	  "decorators": [{"kind": "discount", "name": "WELCOME20"}, {"kind": "tax", "name": "GST"}]
+	}
+
+The decorators of an item apply to one unit of it, the decorators of the order apply to the sum of the items.
+An order has at most MAX_ORDER_ITEMS items, a quantity is 1 to MAX_QUANTITY and an item (or the order)
+has at most MAX_DECORATORS decorators, counting their quantity.
+*/
+const (
+	MAX_ORDER_ITEMS = 50
+	MAX_QUANTITY    = 100
+	MAX_DECORATORS  = 100
+)
+
+var (
+	ErrQuantity   = fmt.Errorf("Quantity should be 1 to %d!", MAX_QUANTITY)
+	ErrOrderItems = fmt.Errorf("Order should have at most %d items!", MAX_ORDER_ITEMS)
+	ErrDecorators = fmt.Errorf("At most %d decorators are allowed!", MAX_DECORATORS)
+)
+
+type OrderSpec struct {
+	Items      []ItemSpec      `json:"items"`
+	Decorators []DecoratorSpec `json:"decorators,omitempty"`
+}
+
+type ItemSpec struct {
+	Base       string          `json:"base"`
+	Quantity   int             `json:"quantity,omitempty"` // default 1
+	Decorators []DecoratorSpec `json:"decorators,omitempty"`
+}
+
+type DecoratorSpec struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity,omitempty"` // the decorator is chained this many times, default 1
+}
+
+// Quote is the itemised price of an order, all amounts are in minor units
+type Quote struct {
+	Currency string      `json:"currency"`
+	Items    []ItemQuote `json:"items"`
+	Lines    []Line      `json:"lines"` // order level: the items, then the order decorators
+	Total    Money       `json:"total"`
+}
+
+type ItemQuote struct {
+	Base      string `json:"base"`
+	Quantity  int    `json:"quantity"`
+	Lines     []Line `json:"lines"` // breakdown of one unit
+	UnitPrice Money  `json:"unit_price"`
+	Total     Money  `json:"total"`
+}
+
+// ParseOrder reads an OrderSpec, unknown fields are an error to catch typos in the kinds/names
+func ParseOrder(data []byte) (spec OrderSpec, err error) {
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&spec); err != nil {
+		return spec, fmt.Errorf("Invalid order: %w", err)
+	}
+	return
+}
+
+func (m *Menu) Quote(spec OrderSpec) (q Quote, err error) {
+	if len(spec.Items) == 0 {
+		return q, fmt.Errorf("Order has no items!")
+	}
+	if len(spec.Items) > MAX_ORDER_ITEMS {
+		return q, fmt.Errorf("%w: %d", ErrOrderItems, len(spec.Items))
+	}
+
+	q = Quote{Currency: m.Currency, Items: []ItemQuote{}}
+	var total Item = &base{name: "items", price: 0}
+	for i, is := range spec.Items {
+		if is.Quantity == 0 {
+			is.Quantity = 1
+		}
+		if is.Quantity < 0 || is.Quantity > MAX_QUANTITY {
+			return q, fmt.Errorf("Item %d: %w: %d", i+1, ErrQuantity, is.Quantity)
+		}
+
+		item, err := m.Base(is.Base)
+		if err != nil {
+			return q, fmt.Errorf("Item %d: %w", i+1, err)
+		}
+		if item, err = m.decorate(item, is.Decorators); err != nil {
+			return q, fmt.Errorf("Item %d: %w", i+1, err)
+		}
+
+		iq := ItemQuote{Base: is.Base, Quantity: is.Quantity, Lines: item.Breakdown()}
+		if err = checkLines(iq.Lines); err != nil {
+			return q, fmt.Errorf("Item %d: %w", i+1, err)
+		}
+		iq.UnitPrice = iq.Lines[len(iq.Lines)-1].Subtotal
+		if iq.Total, err = iq.UnitPrice.Times(int64(is.Quantity)); err != nil {
+			return q, fmt.Errorf("Item %d: %w", i+1, err)
+		}
+		q.Items = append(q.Items, iq)
+		total = Decorate(total, "item", fmt.Sprintf("%s x%d", is.Base, is.Quantity), Fixed(iq.Total))
+	}
+
+	if total, err = m.decorate(total, spec.Decorators); err != nil {
+		return q, fmt.Errorf("Order: %w", err)
+	}
+	lines := total.Breakdown()
+	if err = checkLines(lines); err != nil {
+		return q, fmt.Errorf("Order: %w", err)
+	}
+	// skip the zero base which only starts the chain
+	q.Lines, q.Total = lines[1:], lines[len(lines)-1].Subtotal
+	return
+}
+
+// checkLines adds up the amounts of a breakdown again, failing with ErrOverflow if a subtotal overflowed
+func checkLines(lines []Line) (err error) {
+	subtotal := lines[0].Subtotal
+	for _, l := range lines[1:] {
+		if subtotal, err = subtotal.Add(l.Amount); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (m *Menu) decorate(item Item, specs []DecoratorSpec) (Item, error) {
+	var err error
+	decorators := 0
+	for _, ds := range specs {
+		if ds.Quantity == 0 {
+			ds.Quantity = 1
+		}
+		if ds.Quantity < 0 || ds.Quantity > MAX_QUANTITY {
+			return nil, fmt.Errorf("%v %q: %w: %d", ds.Kind, ds.Name, ErrQuantity, ds.Quantity)
+		}
+		if decorators += ds.Quantity; decorators > MAX_DECORATORS {
+			return nil, fmt.Errorf("%w: %d", ErrDecorators, decorators)
+		}
+		for n := 0; n < ds.Quantity; n++ {
+			if item, err = m.Decorate(item, ds.Kind, ds.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return item, nil
+}
@@ -0,0 +1,213 @@
+package pricing
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+/*
+Pricing engine, the working version of the pizza decorator example.
+
+Every base item (a pizza) and every decorated item implements Item. A decorator wraps an Item and
+adjusts its price, so decorators can be chained to any depth:
+
+	tax(discount(topping(topping(size(margherita)))))
+
+The base items and the decorators are not types, they are registered in a Menu by name,
+so an order is plain data (see OrderSpec) and the menu can change at runtime.
+*/
+
+// Kinds of decorators, any other kind can be registered too
+const (
+	KIND_BASE     = "base"
+	KIND_SIZE     = "size"
+	KIND_TOPPING  = "topping"
+	KIND_COMBO    = "combo"
+	KIND_DISCOUNT = "discount"
+	KIND_TAX      = "tax"
+)
+
+// Line of a price breakdown, Amount is what it added (negative for a discount) and Subtotal the price after it
+type Line struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Amount   Money  `json:"amount"`
+	Subtotal Money  `json:"subtotal"`
+}
+
+type Item interface {
+	Price() Money
+	Breakdown() []Line
+}
+
+type base struct {
+	name  string
+	price Money
+}
+
+func (b *base) Price() Money {
+	return b.price
+}
+
+func (b *base) Breakdown() []Line {
+	return []Line{{Kind: KIND_BASE, Name: b.name, Amount: b.price, Subtotal: b.price}}
+}
+
+// Adjustment returns the amount a decorator adds to the subtotal of the item it wraps
+type Adjustment func(subtotal Money) Money
+
+// Fixed amount, ie a topping
+func Fixed(amount Money) Adjustment {
+	return func(Money) Money { return amount }
+}
+
+// Percent of the subtotal, ie a tax (positive) or a discount (negative)
+func Percent(bps BasisPoints) Adjustment {
+	return func(subtotal Money) Money { return bps.Of(subtotal) }
+}
+
+// UpTo caps the absolute amount of an adjustment, ie 20% off up to 100.00
+func UpTo(adj Adjustment, max Money) Adjustment {
+	return func(subtotal Money) (amount Money) {
+		amount = adj(subtotal)
+		if amount > max {
+			return max
+		}
+		if amount < -max {
+			return -max
+		}
+		return
+	}
+}
+
+type decorator struct {
+	item   Item
+	kind   string
+	name   string
+	adjust Adjustment
+}
+
+// Decorate wraps an item, the price never goes below zero however big the discount is
+func Decorate(item Item, kind, name string, adjust Adjustment) Item {
+	return &decorator{item: item, kind: kind, name: name, adjust: adjust}
+}
+
+// adjustment of the subtotal, limited so the price does not go below zero
+func (d *decorator) adjustment(subtotal Money) (amount Money) {
+	amount = d.adjust(subtotal)
+	if amount < -subtotal {
+		amount = -subtotal
+	}
+	return
+}
+
+func (d *decorator) Price() Money {
+	lines := d.Breakdown()
+	return lines[len(lines)-1].Subtotal
+}
+
+/*
+Breakdown walks the chain once: down to the innermost item, then back up applying every decorator
+to the subtotal of the ones below it, so a chain of n decorators is O(n) rather than every
+decorator pricing the whole chain below it again.
+*/
+func (d *decorator) Breakdown() (lines []Line) {
+	var chain []*decorator
+	var item Item = d
+	for {
+		dec, ok := item.(*decorator)
+		if !ok {
+			break
+		}
+		chain = append(chain, dec)
+		item = dec.item
+	}
+
+	lines = item.Breakdown()
+	subtotal := item.Price()
+	for i := len(chain) - 1; i >= 0; i-- {
+		amount := chain[i].adjustment(subtotal)
+		subtotal += amount
+		lines = append(lines, Line{Kind: chain[i].kind, Name: chain[i].name, Amount: amount, Subtotal: subtotal})
+	}
+	return
+}
+
+/*
+Menu has the base items and the decorators, both registered by name.
+A decorator is registered per kind and name ie (topping, cheese) or (tax, GST).
+*/
+type Menu struct {
+	Currency string
+
+	mulock     sync.RWMutex
+	bases      map[string]Money
+	decorators map[string]map[string]Adjustment
+}
+
+func NewMenu(currency string) *Menu {
+	return &Menu{Currency: currency, bases: map[string]Money{}, decorators: map[string]map[string]Adjustment{}}
+}
+
+func (m *Menu) AddBase(name string, price Money) (err error) {
+	if name == "" || price < 0 {
+		return fmt.Errorf("Invalid base item %q: %v", name, price)
+	}
+	m.mulock.Lock()
+	defer m.mulock.Unlock()
+	m.bases[name] = price
+	return
+}
+
+func (m *Menu) AddDecorator(kind, name string, adjust Adjustment) (err error) {
+	if kind == "" || kind == KIND_BASE || name == "" || adjust == nil {
+		return fmt.Errorf("Invalid decorator %q/%q", kind, name)
+	}
+	m.mulock.Lock()
+	defer m.mulock.Unlock()
+	if m.decorators[kind] == nil {
+		m.decorators[kind] = map[string]Adjustment{}
+	}
+	m.decorators[kind][name] = adjust
+	return
+}
+
+func (m *Menu) Base(name string) (item Item, err error) {
+	m.mulock.RLock()
+	defer m.mulock.RUnlock()
+	price, ok := m.bases[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown item: %q", name)
+	}
+	return &base{name: name, price: price}, nil
+}
+
+func (m *Menu) Decorate(item Item, kind, name string) (Item, error) {
+	m.mulock.RLock()
+	adjust, ok := m.decorators[kind][name]
+	m.mulock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unknown %v: %q", kind, name)
+	}
+	return Decorate(item, kind, name, adjust), nil
+}
+
+// Entries lists the names on the menu, by kind
+func (m *Menu) Entries() (entries map[string][]string) {
+	m.mulock.RLock()
+	defer m.mulock.RUnlock()
+	entries = map[string][]string{KIND_BASE: {}}
+	for name := range m.bases {
+		entries[KIND_BASE] = append(entries[KIND_BASE], name)
+	}
+	for kind, names := range m.decorators {
+		for name := range names {
+			entries[kind] = append(entries[kind], name)
+		}
+	}
+	for _, names := range entries {
+		sort.Strings(names)
+	}
+	return
+}
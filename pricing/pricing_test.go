@@ -0,0 +1,174 @@
+package pricing
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	testCases := []struct {
+		name            string
+		input           string
+		output_expected Money
+		valid           bool
+	}{
+		{name: "Integer-TC-1", input: "120", output_expected: 120_00, valid: true},
+		{name: "Decimal-TC-2", input: "120.5", output_expected: 120_50, valid: true},
+		{name: "Decimal-TC-3", input: "0.05", output_expected: 5, valid: true},
+		{name: "Negative-TC-4", input: "-1.25", output_expected: -1_25, valid: true},
+		{name: "TooPrecise-TC-5", input: "1.005"},
+		{name: "Invalid-TC-6", input: "1.x"},
+		{name: "Empty-TC-7", input: ""},
+		{name: "Sign-TC-8", input: "1.-5"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := ParseMoney(tc.input)
+			if (err == nil) != tc.valid || m != tc.output_expected {
+				t.Errorf("Actual output: %v %v, Expected output: %v", int64(m), err, int64(tc.output_expected))
+			}
+		})
+	}
+
+	if s := Money(-1_05).String(); s != "-1.05" {
+		t.Errorf("Expected -1.05, got: %v", s)
+	}
+}
+
+func TestPercent(t *testing.T) {
+	testCases := []struct {
+		name            string
+		bps             BasisPoints
+		amount          Money
+		output_expected Money
+	}{
+		{name: "Exact-TC-1", bps: 500, amount: 100_00, output_expected: 5_00},
+		{name: "RoundUp-TC-2", bps: 500, amount: 569_00, output_expected: 28_45},
+		{name: "HalfUp-TC-3", bps: 5000, amount: 1, output_expected: 1},
+		{name: "HalfDown-TC-4", bps: -5000, amount: 1, output_expected: -1},
+		{name: "RoundDown-TC-5", bps: 1250, amount: 3, output_expected: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if out := tc.bps.Of(tc.amount); out != tc.output_expected {
+				t.Errorf("Actual output: %v, Expected output: %v", int64(out), int64(tc.output_expected))
+			}
+		})
+	}
+}
+
+func testMenu() *Menu {
+	m := NewMenu("INR")
+	m.AddBase("pizza", 100_00)
+	m.AddBase("gold", math.MaxInt64/3)
+	m.AddDecorator(KIND_TOPPING, "cheese", Fixed(20_00))
+	m.AddDecorator(KIND_SIZE, "large", Percent(3000))
+	m.AddDecorator(KIND_DISCOUNT, "HALF", UpTo(Percent(-5000), 60_00))
+	m.AddDecorator(KIND_DISCOUNT, "ALL", Fixed(-5000_00))
+	m.AddDecorator(KIND_TAX, "GST", Percent(500))
+	return m
+}
+
+func TestDecoratorChain(t *testing.T) {
+	m := testMenu()
+	item, _ := m.Base("pizza")
+	// decorators can be chained to any depth
+	for i := 0; i < 50; i++ {
+		item, _ = m.Decorate(item, KIND_TOPPING, "cheese")
+	}
+	if item.Price() != 1100_00 || len(item.Breakdown()) != 51 {
+		t.Errorf("Expected 1100.00 with 51 lines, got: %v %v", item.Price(), len(item.Breakdown()))
+	}
+
+	// the price never goes below zero
+	item, _ = m.Decorate(item, KIND_DISCOUNT, "ALL")
+	lines := item.Breakdown()
+	if item.Price() != 0 || lines[len(lines)-1].Amount != -1100_00 {
+		t.Errorf("Expected the discount to be limited to the price, got: %v %+v", item.Price(), lines[len(lines)-1])
+	}
+
+	if _, err := m.Decorate(item, KIND_TOPPING, "pineapple"); err == nil {
+		t.Errorf("Expected unknown topping to fail")
+	}
+}
+
+func TestQuote(t *testing.T) {
+	testCases := []struct {
+		name        string
+		order       string
+		total       Money
+		order_lines []string
+		err         string
+	}{
+		{
+			name:        "Items-TC-1",
+			order:       `{"items": [{"base": "pizza", "quantity": 2, "decorators": [{"kind": "size", "name": "large"}, {"kind": "topping", "name": "cheese", "quantity": 2}]}]}`,
+			total:       340_00, // (100 + 30 + 20 + 20) x 2
+			order_lines: []string{"item:pizza x2:34000"},
+		},
+		{
+			name:        "OrderDecorators-TC-2",
+			order:       `{"items": [{"base": "pizza"}, {"base": "pizza", "decorators": [{"kind": "topping", "name": "cheese"}]}], "decorators": [{"kind": "discount", "name": "HALF"}, {"kind": "tax", "name": "GST"}]}`,
+			total:       168_00, // 220 - 60 (capped) = 160 + 5%
+			order_lines: []string{"item:pizza x1:10000", "item:pizza x1:12000", "discount:HALF:-6000", "tax:GST:800"},
+		},
+		{name: "NoItems-TC-3", order: `{"items": []}`, err: "no items"},
+		{name: "UnknownBase-TC-4", order: `{"items": [{"base": "burger"}]}`, err: "Unknown item"},
+		{name: "UnknownDecorator-TC-5", order: `{"items": [{"base": "pizza"}], "decorators": [{"kind": "tax", "name": "VAT"}]}`, err: "Unknown tax"},
+		{name: "UnknownField-TC-6", order: `{"items": [{"base": "pizza", "toppings": ["cheese"]}]}`, err: "unknown field"},
+		{name: "Quantity-TC-7", order: `{"items": [{"base": "pizza", "quantity": -1}]}`, err: "Quantity should be"},
+		{name: "MaxQuantity-TC-8", order: `{"items": [{"base": "pizza", "quantity": 101}]}`, err: "Quantity should be"},
+		{name: "MaxDecorators-TC-9", order: `{"items": [{"base": "pizza", "decorators": [{"kind": "topping", "name": "cheese", "quantity": 60}, {"kind": "topping", "name": "cheese", "quantity": 60}]}]}`,
+			err: "At most 100 decorators"},
+		{name: "Overflow-TC-10", order: `{"items": [{"base": "gold", "quantity": 100}]}`, err: "Amount overflow"},
+		{name: "Overflow-Sum-TC-11", order: `{"items": [{"base": "gold", "quantity": 2}, {"base": "gold", "quantity": 2}]}`, err: "Amount overflow"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, err := ParseOrder([]byte(tc.order))
+			var q Quote
+			if err == nil {
+				q, err = testMenu().Quote(spec)
+			}
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("Expected error %q, got: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			lines := []string{}
+			for _, l := range q.Lines {
+				lines = append(lines, l.Kind+":"+l.Name+":"+strings.TrimSpace(strings.Replace(l.Amount.String(), ".", "", 1)))
+			}
+			if q.Total != tc.total || !reflect.DeepEqual(lines, tc.order_lines) {
+				t.Errorf("Actual output: %v %v, Expected output: %v %v", q.Total, lines, tc.total, tc.order_lines)
+			}
+		})
+	}
+}
+
+func TestMoneyOverflow(t *testing.T) {
+	if _, err := Money(math.MaxInt64).Add(1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected %v, got: %v", ErrOverflow, err)
+	}
+	if _, err := Money(math.MinInt64).Add(-1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected %v, got: %v", ErrOverflow, err)
+	}
+	if _, err := Money(math.MaxInt64 / 2).Times(3); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected %v, got: %v", ErrOverflow, err)
+	}
+	if m, err := Money(-250).Times(4); m != -1000 || err != nil {
+		t.Errorf("Expected -10.00, got: %v %v", m, err)
+	}
+	// the percentage of a large amount does not overflow
+	if p := BasisPoints(3000).Of(math.MaxInt64 / 2); p != Money(math.MaxInt64/2/10*3+1) {
+		t.Errorf("Expected 30%% of %d, got: %d", int64(math.MaxInt64/2), p)
+	}
+}
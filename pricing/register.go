@@ -0,0 +1,54 @@
+package pricing
+
+import (
+	"context"
+	"examples/registry"
+	"fmt"
+	"io"
+)
+
+const DEMO_ORDER = `{"items":[{"base":"margherita","quantity":2,"decorators":[{"kind":"size","name":"large"},{"kind":"topping","name":"cheese","quantity":2}]},{"base":"farmhouse","decorators":[{"kind":"topping","name":"jalapeno"},{"kind":"combo","name":"meal"}]}],"decorators":[{"kind":"discount","name":"WELCOME20"},{"kind":"tax","name":"GST"}]}`
+
+func init() {
+	registry.Register(registry.Example{
+		Name:        "pricing",
+		Title:       "Pricing Engine: Decorator",
+		Category:    registry.CATEGORY_STRUCTURAL,
+		Description: "Price a JSON order by chaining registered decorators (size, toppings, combo, discount, tax) on base items",
+		Source:      "pricing/pricing.go",
+		Params: []registry.Param{
+			{Name: "order", Type: registry.PARAM_STRING, Default: DEMO_ORDER, Usage: "order spec as JSON"},
+		},
+		Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+			return ExamplePricing(w, args.String("order"))
+		},
+	})
+}
+
+// ExamplePricing prices the order on the demo menu, the errors are the ones of the order ie an unknown item
+func ExamplePricing(w io.Writer, order string) (err error) {
+	spec, err := ParseOrder([]byte(order))
+	if err != nil {
+		return registry.InvalidArgs(err)
+	}
+	q, err := Demo().Quote(spec)
+	if err != nil {
+		return registry.InvalidArgs(err)
+	}
+
+	for _, iq := range q.Items {
+		fmt.Fprintf(w, "%s x%d\n", iq.Base, iq.Quantity)
+		printLines(w, iq.Lines)
+		fmt.Fprintf(w, "  = %v x %d = %v\n", iq.UnitPrice, iq.Quantity, iq.Total)
+	}
+	fmt.Fprintln(w, "Order")
+	printLines(w, q.Lines)
+	fmt.Fprintf(w, "Total: %v %v\n", q.Total, q.Currency)
+	return
+}
+
+func printLines(w io.Writer, lines []Line) {
+	for _, l := range lines {
+		fmt.Fprintf(w, "  %-9s %-22s %10v %10v\n", l.Kind, l.Name, l.Amount, l.Subtotal)
+	}
+}
@@ -0,0 +1,116 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+Examples can accept arguments, which are query parameters over HTTP and flags on the command line.
+Each example declares its Params, the values are validated and defaulted before the example is run,
+so the example itself can read them without handling errors.
+*/
+
+type ParamType string
+
+const (
+	PARAM_STRING ParamType = "string"
+	PARAM_INT    ParamType = "int"
+)
+
+type Param struct {
+	Name    string    `json:"name"`
+	Type    ParamType `json:"type"`
+	Default string    `json:"default"`
+	Usage   string    `json:"usage"`
+}
+
+// Args are the validated values of the params of an example
+type Args map[string]string
+
+// String returns the value of the param name
+func (a Args) String(name string) string {
+	return a[name]
+}
+
+// Int returns the value of the int param name, it is validated already by Example.Args
+func (a Args) Int(name string) int {
+	n, _ := strconv.Atoi(a[name])
+	return n
+}
+
+// RunFunc runs an example, writing its output to w
+type RunFunc func(ctx context.Context, w io.Writer, args Args) error
+
+// Simple adapts an example which only prints and takes no arguments
+func Simple(fn func(w io.Writer)) RunFunc {
+	return func(ctx context.Context, w io.Writer, args Args) error {
+		fn(w)
+		return nil
+	}
+}
+
+/*
+Args validates values against the params of the example and fills in the defaults.
+Unknown names and invalid values return an error.
+*/
+func (e Example) Args(values map[string]string) (args Args, err error) {
+	params := map[string]Param{}
+	for _, p := range e.Params {
+		params[p.Name] = p
+	}
+
+	var unknown []string
+	for name := range values {
+		if _, ok := params[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("Unknown argument(s) for %s: %s", e.Name, strings.Join(unknown, ", "))
+	}
+
+	args = Args{}
+	for _, p := range e.Params {
+		val, ok := values[p.Name]
+		if !ok {
+			val = p.Default
+		}
+		if p.Type == PARAM_INT {
+			if _, err = strconv.Atoi(val); err != nil {
+				return nil, fmt.Errorf("Argument %s must be an integer, got %q", p.Name, val)
+			}
+		}
+		args[p.Name] = val
+	}
+	return
+}
+
+// ErrInvalidArgs is the cause of the errors marked by InvalidArgs
+var ErrInvalidArgs = errors.New("Invalid arguments!")
+
+/*
+InvalidArgs marks err as caused by the arguments of the example (ie out of range), rather than by
+the example failing, so the server replies 400 and the circuit breaker does not count it.
+errors.Is holds for both ErrInvalidArgs and err, the message is the one of err. A nil err stays nil.
+*/
+func InvalidArgs(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &argsError{err: err}
+}
+
+type argsError struct {
+	err error
+}
+
+func (e *argsError) Error() string        { return e.err.Error() }
+func (e *argsError) Unwrap() error        { return e.err }
+func (e *argsError) Is(target error) bool { return target == ErrInvalidArgs }
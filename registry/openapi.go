@@ -0,0 +1,213 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+/*
+OpenAPI (v3.0) document of all the registered examples.
+Each example is a GET operation, tagged with its category, returning the captured output (capture.Result).
+The routes (see RegisterRoute) are tagged API, they reply with their own JSON (Response).
+*/
+
+var operationID = strings.NewReplacer("/", "_", "-", "_")
+
+var openapiType = map[ParamType]string{PARAM_STRING: "string", PARAM_INT: "integer"}
+
+// marshalIndent is json.MarshalIndent without escaping &, < and > in the URLs
+func marshalIndent(v interface{}, indent string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func jsonContent(ref string) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{
+			"schema": map[string]interface{}{"$ref": "#/components/schemas/" + ref},
+		},
+	}
+}
+
+/*
+OpenAPI returns the document as JSON.
+baseURL is the URL under which the examples are mounted ie http://localhost:3000/golang
+*/
+func OpenAPI(baseURL string) (doc []byte, err error) {
+	paths := map[string]interface{}{
+		"/catalog": map[string]interface{}{
+			"get": map[string]interface{}{
+				"tags":        []string{"Catalog"},
+				"summary":     "List all the examples",
+				"operationId": "catalog",
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "Registered examples",
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type":  "array",
+									"items": map[string]interface{}{"$ref": "#/components/schemas/Example"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tags := []interface{}{map[string]interface{}{"name": "Catalog"}}
+	seen := map[Category]bool{}
+	for _, e := range All() {
+		if !seen[e.Category] {
+			seen[e.Category] = true
+			tags = append(tags, map[string]interface{}{"name": string(e.Category)})
+		}
+		params := []interface{}{map[string]interface{}{
+			"name":        "timeout",
+			"in":          "query",
+			"description": "time the example may run, ie 5s",
+			"schema":      map[string]interface{}{"type": "string"},
+		}}
+		for _, p := range e.Params {
+			params = append(params, map[string]interface{}{
+				"name":        p.Name,
+				"in":          "query",
+				"description": p.Usage,
+				"schema":      map[string]interface{}{"type": openapiType[p.Type], "default": p.Default},
+			})
+		}
+		paths["/"+e.Name] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"tags":        []string{string(e.Category)},
+				"parameters":  params,
+				"summary":     e.Title,
+				"description": fmt.Sprintf("%s\n\nSource: %s", e.Description, e.Source),
+				"operationId": operationID.Replace(e.Name),
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{"description": "Output of the example", "content": jsonContent("Result")},
+					"400": map[string]interface{}{"description": "Invalid arguments", "content": jsonContent("Result")},
+					"500": map[string]interface{}{"description": "Example failed", "content": jsonContent("Result")},
+				},
+			},
+		}
+	}
+
+	if all := Routes(); len(all) > 0 {
+		tags = append(tags, map[string]interface{}{"name": string(CATEGORY_API)})
+		for _, r := range all {
+			path := openapiPath(r.Path)
+			ops, ok := paths[path].(map[string]interface{})
+			if !ok {
+				ops = map[string]interface{}{}
+				paths[path] = ops
+			}
+			ops[strings.ToLower(r.Method)] = routeOperation(r)
+		}
+	}
+
+	str := map[string]interface{}{"type": "string"}
+	return marshalIndent(map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Golang Design Patterns - Examples",
+			"description": "Generated from the example registry, do not edit by hand. Run: make docs",
+			"version":     "1.0.0",
+		},
+		"servers": []interface{}{map[string]interface{}{"url": strings.TrimSuffix(baseURL, "/")}},
+		"tags":    tags,
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Example": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name": str, "title": str, "category": str, "description": str, "source": str, "path": str,
+					},
+				},
+				"Result": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"example":     str,
+						"success":     map[string]interface{}{"type": "boolean"},
+						"output":      map[string]interface{}{"type": "array", "items": str},
+						"duration":    str,
+						"duration_ms": map[string]interface{}{"type": "number"},
+						"error":       map[string]interface{}{"type": "string", "nullable": true},
+					},
+				},
+				"Response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"success": map[string]interface{}{"type": "boolean"},
+						"error":   str,
+					},
+					"additionalProperties": true,
+				},
+			},
+		},
+	}, "  ")
+}
+
+// openapiPath turns the path parameters :name into {name}
+func openapiPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func routeOperation(r Route) map[string]interface{} {
+	params := []interface{}{}
+	for _, name := range r.PathParams() {
+		params = append(params, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	for _, p := range r.Params {
+		params = append(params, map[string]interface{}{
+			"name":        p.Name,
+			"in":          "query",
+			"description": p.Usage,
+			"schema":      map[string]interface{}{"type": openapiType[p.Type], "default": p.Default},
+		})
+	}
+	op := map[string]interface{}{
+		"tags":        []string{string(CATEGORY_API)},
+		"summary":     r.Title,
+		"description": r.Description,
+		"operationId": strings.ToLower(r.Method) + operationID.Replace(strings.NewReplacer(":", "").Replace(r.Path)),
+		"parameters":  params,
+		"responses": map[string]interface{}{
+			"200":     map[string]interface{}{"description": "Success", "content": jsonContent("Response")},
+			"default": map[string]interface{}{"description": "Failed, see error", "content": jsonContent("Response")},
+		},
+	}
+	if r.Body != "" {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema":  map[string]interface{}{"type": "object"},
+					"example": json.RawMessage(r.Body),
+				},
+			},
+		}
+	}
+	return op
+}
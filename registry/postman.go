@@ -0,0 +1,181 @@
+package registry
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+/*
+Postman collection (schema v2.1) of all the registered examples.
+Examples are grouped into nested folders as per their Category, ie "Design Pattern/Creational"
+becomes folder "Design Pattern" having a sub folder "Creational". The routes (see RegisterRoute)
+are in the folder API.
+*/
+
+const POSTMAN_SCHEMA = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type postmanCollection struct {
+	Info postmanInfo    `json:"info"`
+	Item []*postmanItem `json:"item"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type postmanItem struct {
+	Name     string          `json:"name"`
+	Item     []*postmanItem  `json:"item,omitempty"`
+	Request  *postmanRequest `json:"request,omitempty"`
+	Response []interface{}   `json:"response,omitempty"`
+}
+
+type postmanRequest struct {
+	Method      string        `json:"method"`
+	Header      []interface{} `json:"header"`
+	Body        *postmanBody  `json:"body,omitempty"`
+	URL         postmanURL    `json:"url"`
+	Description string        `json:"description,omitempty"`
+}
+
+type postmanBody struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string         `json:"raw"`
+	Protocol string         `json:"protocol"`
+	Host     []string       `json:"host"`
+	Port     string         `json:"port,omitempty"`
+	Path     []string       `json:"path"`
+	Query    []postmanQuery `json:"query,omitempty"`
+	Variable []postmanQuery `json:"variable,omitempty"` // path parameters
+}
+
+type postmanQuery struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// folder returns the sub folder with name, creating it if required
+func (pi *postmanItem) folder(name string) *postmanItem {
+	for _, item := range pi.Item {
+		if item.Request == nil && item.Name == name {
+			return item
+		}
+	}
+	f := &postmanItem{Name: name}
+	pi.Item = append(pi.Item, f)
+	return f
+}
+
+/*
+Postman returns the collection of all registered examples as JSON.
+baseURL is the URL under which the examples are mounted ie http://localhost:3000/golang
+*/
+func Postman(baseURL string) (collection []byte, err error) {
+	base, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("Invalid base url: %q", baseURL)
+	}
+	basePath := strings.Split(strings.Trim(base.Path, "/"), "/")
+	if basePath[0] == "" {
+		basePath = nil
+	}
+
+	root := &postmanItem{}
+	for _, e := range All() {
+		f := root
+		for _, name := range e.Category.Folders() {
+			f = f.folder(name)
+		}
+
+		path := append(append([]string{}, basePath...), strings.Split(e.Name, "/")...)
+		raw := base.String() + "/" + e.Name
+		var query []postmanQuery
+		for i, p := range e.Params {
+			sep := "&"
+			if i == 0 {
+				sep = "?"
+			}
+			raw += sep + p.Name + "=" + url.QueryEscape(p.Default)
+			query = append(query, postmanQuery{Key: p.Name, Value: p.Default, Description: p.Usage})
+		}
+		f.Item = append(f.Item, &postmanItem{
+			Name: e.Title,
+			Request: &postmanRequest{
+				Method: "GET",
+				Header: []interface{}{},
+				URL: postmanURL{
+					Raw:      raw,
+					Protocol: base.Scheme,
+					Host:     strings.Split(base.Hostname(), "."),
+					Port:     base.Port(),
+					Path:     path,
+					Query:    query,
+				},
+				Description: fmt.Sprintf("%s\n\nSource: %s", e.Description, e.Source),
+			},
+			Response: []interface{}{},
+		})
+	}
+
+	if all := Routes(); len(all) > 0 {
+		f := root.folder(string(CATEGORY_API))
+		for _, r := range all {
+			f.Item = append(f.Item, postmanRoute(base, basePath, r))
+		}
+	}
+
+	return marshalIndent(postmanCollection{
+		Info: postmanInfo{
+			Name:        "Examples",
+			Description: "Generated from the example registry, do not edit by hand. Run: make docs",
+			Schema:      POSTMAN_SCHEMA,
+		},
+		Item: root.Item,
+	}, "\t")
+}
+
+func postmanRoute(base *url.URL, basePath []string, r Route) *postmanItem {
+	path := append(append([]string{}, basePath...), strings.Split(strings.Trim(r.Path, "/"), "/")...)
+	raw := base.String() + r.Path
+	var query, variables []postmanQuery
+	for i, p := range r.Params {
+		sep := "&"
+		if i == 0 {
+			sep = "?"
+		}
+		raw += sep + p.Name + "=" + url.QueryEscape(p.Default)
+		query = append(query, postmanQuery{Key: p.Name, Value: p.Default, Description: p.Usage})
+	}
+	for _, name := range r.PathParams() {
+		variables = append(variables, postmanQuery{Key: name})
+	}
+
+	req := &postmanRequest{
+		Method: r.Method,
+		Header: []interface{}{},
+		URL: postmanURL{
+			Raw:      raw,
+			Protocol: base.Scheme,
+			Host:     strings.Split(base.Hostname(), "."),
+			Port:     base.Port(),
+			Path:     path,
+			Query:    query,
+			Variable: variables,
+		},
+		Description: r.Description,
+	}
+	if r.Body != "" {
+		req.Header = append(req.Header, map[string]string{"key": "Content-Type", "value": "application/json"})
+		req.Body = &postmanBody{Mode: "raw", Raw: r.Body, Options: map[string]interface{}{"raw": map[string]string{"language": "json"}}}
+	}
+	return &postmanItem{Name: r.Title, Request: req, Response: []interface{}{}}
+}
@@ -0,0 +1,121 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+Registry of all the runnable examples.
+
+Every example package registers its examples from an init() function (see register.go in each package),
+main.go then mounts a route for each of them, and the catalog, Postman collection and OpenAPI document
+are all generated from the same list. So adding an example is a single Register call.
+
+	func init() {
+		registry.Register(registry.Example{
+			Name:        "pattern/creational/singleton",
+			Title:       "Singleton Pattern",
+			Category:    registry.CATEGORY_CREATIONAL,
+			Description: "Create a single instance via sync.Once or double checked locking",
+			Source:      "patterns/creational/singleton.go",
+			Params: []registry.Param{
+				{Name: "goroutines", Type: registry.PARAM_INT, Default: "10", Usage: "goroutines requesting the instance"},
+			},
+			Run: func(ctx context.Context, w io.Writer, args registry.Args) error {
+				ExecuteSingleton(w, args.Int("goroutines"))
+				return nil
+			},
+		})
+	}
+
+Examples which take no arguments are registered with Run: registry.Simple(ExecuteFactory)
+*/
+
+// Category of an example, nested categories are separated by "/"
+type Category string
+
+const (
+	CATEGORY_CREATIONAL     Category = "Design Pattern/Creational"
+	CATEGORY_STRUCTURAL     Category = "Design Pattern/Structural"
+	CATEGORY_BEHAVIOURAL    Category = "Design Pattern/Behavioural"
+	CATEGORY_DATA_TYPES     Category = "Data Types"
+	CATEGORY_CHANNEL        Category = "Data Types/Channel"
+	CATEGORY_DATA_STRUCTURE Category = "Data Structure"
+	CATEGORY_MISC           Category = "Misc"
+)
+
+// Folders returns the nested folder names of the category
+func (c Category) Folders() []string {
+	return strings.Split(string(c), "/")
+}
+
+type Example struct {
+	// Name is unique, it is also the route of the example under /golang
+	Name        string   `json:"name"`
+	Title       string   `json:"title"`
+	Category    Category `json:"category"`
+	Description string   `json:"description"`
+	Source      string   `json:"source"`
+	Params      []Param  `json:"params,omitempty"`
+	Run         RunFunc  `json:"-"`
+}
+
+var (
+	mu       sync.RWMutex
+	examples = map[string]Example{}
+)
+
+// RESERVED_PARAMS are used by the HTTP server and command line runner themselves
+var RESERVED_PARAMS = map[string]bool{"timeout": true, "json": true}
+
+/*
+Register adds an example to the registry.
+Similar to http.Handle or sql.Register, it panics on programming errors ie empty name,
+missing run function, reserved param or a duplicate name, as these are detected on start up.
+*/
+func Register(e Example) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if e.Name == "" || e.Run == nil {
+		panic(fmt.Sprintf("registry: example %q must have a name and a run function", e.Name))
+	}
+	for _, p := range e.Params {
+		if RESERVED_PARAMS[p.Name] {
+			panic(fmt.Sprintf("registry: example %q uses reserved param %q", e.Name, p.Name))
+		}
+	}
+	if _, ok := examples[e.Name]; ok {
+		panic(fmt.Sprintf("registry: example %q already registered", e.Name))
+	}
+	examples[e.Name] = e
+}
+
+// Get returns the example registered with name
+func Get(name string) (e Example, ok bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	e, ok = examples[name]
+	return
+}
+
+// All returns all the registered examples, ordered by category and name
+func All() (all []Example) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	all = make([]Example, 0, len(examples))
+	for _, e := range examples {
+		all = append(all, e)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Category != all[j].Category {
+			return all[i].Category < all[j].Category
+		}
+		return all[i].Name < all[j].Name
+	})
+	return
+}
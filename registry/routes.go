@@ -0,0 +1,79 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+Route is an API endpoint which is not an example, ie the POST endpoints working on the pattern
+implementations. The server mounts their handlers itself, they are registered so the Postman
+collection and the OpenAPI document cover them along with the examples.
+
+	registry.RegisterRoute(registry.Route{
+		Method: "PUT", Path: "/cache/:key", Title: "Cache Set",
+		Description: "Set the JSON value of the key",
+		Body:        `{"value": {"name": "Harry"}, "ttl": "30s"}`,
+	})
+*/
+type Route struct {
+	Method      string  `json:"method"`
+	Path        string  `json:"path"` // under /golang, path parameters as :name
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Params      []Param `json:"params,omitempty"` // query parameters
+	Body        string  `json:"body,omitempty"`   // example JSON body
+}
+
+// CATEGORY_API is the folder (Postman) and tag (OpenAPI) of the routes
+const CATEGORY_API Category = "API"
+
+var routes = map[string]Route{}
+
+/*
+RegisterRoute adds a route to the docs, it panics on an empty method or path and on a duplicate,
+as Register does.
+*/
+func RegisterRoute(r Route) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if r.Method == "" || !strings.HasPrefix(r.Path, "/") {
+		panic(fmt.Sprintf("registry: route %q %q must have a method and a path from /", r.Method, r.Path))
+	}
+	r.Method = strings.ToUpper(r.Method)
+	key := r.Method + " " + r.Path
+	if _, ok := routes[key]; ok {
+		panic(fmt.Sprintf("registry: route %q already registered", key))
+	}
+	routes[key] = r
+}
+
+// Routes returns all the registered routes, ordered by path and method
+func Routes() (all []Route) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	all = make([]Route, 0, len(routes))
+	for _, r := range routes {
+		all = append(all, r)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Path != all[j].Path {
+			return all[i].Path < all[j].Path
+		}
+		return all[i].Method < all[j].Method
+	})
+	return
+}
+
+// PathParams returns the names of the path parameters ie key of /cache/:key
+func (r Route) PathParams() (names []string) {
+	for _, segment := range strings.Split(r.Path, "/") {
+		if strings.HasPrefix(segment, ":") {
+			names = append(names, segment[1:])
+		}
+	}
+	return
+}
@@ -0,0 +1,215 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type State string
+
+const (
+	STATE_CLOSED    State = "closed"    // requests pass, failures are counted
+	STATE_OPEN      State = "open"      // requests fail fast, till OpenTimeout passes
+	STATE_HALF_OPEN State = "half-open" // a few trial requests pass, to decide to close or open again
+)
+
+var ErrCircuitOpen = errors.New("Circuit breaker is open!")
+
+/*
+OpenError is returned while the breaker rejects requests, RetryAfter is the time till it
+lets a trial request through. It matches ErrCircuitOpen via errors.Is.
+*/
+type OpenError struct {
+	RetryAfter time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%v Retry after %v", ErrCircuitOpen, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *OpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+type BreakerConfig struct {
+	FailureThreshold int           // consecutive failures opening the breaker, default 5
+	OpenTimeout      time.Duration // time the breaker stays open, default 10s
+	HalfOpenRequests int           // trial requests, all of them have to succeed to close, default 1
+	// IsFailure decides which errors count as failures, by default all but the cancellation of the caller
+	IsFailure     func(error) bool
+	OnStateChange func(from, to State) // called with the breaker locked, it must not call the breaker
+
+	Clock Clock
+}
+
+type BreakerStats struct {
+	State       State     `json:"state"`
+	Failures    int       `json:"failures"` // consecutive, while closed
+	Requests    int64     `json:"requests"`
+	Rejected    int64     `json:"rejected"`
+	Successes   int64     `json:"successes"`
+	TotalFailed int64     `json:"total_failures"`
+	OpenedAt    time.Time `json:"opened_at,omitempty"`
+}
+
+/*
+Breaker stops calling a failing downstream for a while, rather than piling up requests on it:
+closed -> (FailureThreshold consecutive failures) -> open -> (OpenTimeout) -> half-open
+-> (HalfOpenRequests successes) -> closed, or -> (any failure) -> open again.
+*/
+type Breaker struct {
+	cfg BreakerConfig
+
+	mulock    sync.Mutex
+	state     State
+	failures  int
+	openedAt  time.Time
+	trials    int // requests let through while half-open
+	succeeded int // of the trials
+
+	requests, rejected, successes, failed int64
+}
+
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	if cfg.FailureThreshold < 1 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 10 * time.Second
+	}
+	if cfg.HalfOpenRequests < 1 {
+		cfg.HalfOpenRequests = 1
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = func(err error) bool { return !errors.Is(err, context.Canceled) }
+	}
+	cfg.Clock = clockOrReal(cfg.Clock)
+	return &Breaker{cfg: cfg, state: STATE_CLOSED}
+}
+
+/*
+Allow reports whether a request may go through. When it may, done has to be called with the
+outcome of the request (nil on success), else an *OpenError is returned.
+*/
+func (b *Breaker) Allow() (done func(err error), err error) {
+	b.mulock.Lock()
+	defer b.mulock.Unlock()
+	b.requests++
+
+	if b.state == STATE_OPEN {
+		if elapsed := b.cfg.Clock.Now().Sub(b.openedAt); elapsed < b.cfg.OpenTimeout {
+			b.rejected++
+			return nil, &OpenError{RetryAfter: b.cfg.OpenTimeout - elapsed}
+		}
+		b.setState(STATE_HALF_OPEN)
+	}
+	if b.state == STATE_HALF_OPEN {
+		if b.trials >= b.cfg.HalfOpenRequests {
+			b.rejected++
+			return nil, &OpenError{RetryAfter: b.cfg.OpenTimeout}
+		}
+		b.trials++
+	}
+
+	state := b.state
+	once := sync.Once{}
+	return func(err error) {
+		once.Do(func() { b.record(state, err) })
+	}, nil
+}
+
+/*
+Execute runs fn when the breaker allows it, recording its outcome.
+*/
+func (b *Breaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	err = fn(ctx)
+	done(err)
+	return err
+}
+
+func (b *Breaker) State() State {
+	b.mulock.Lock()
+	defer b.mulock.Unlock()
+	// report open as half-open once the timeout passed, though it changes on the next request only
+	if b.state == STATE_OPEN && b.cfg.Clock.Now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		return STATE_HALF_OPEN
+	}
+	return b.state
+}
+
+func (b *Breaker) Stats() BreakerStats {
+	b.mulock.Lock()
+	defer b.mulock.Unlock()
+	return BreakerStats{
+		State:       b.state,
+		Failures:    b.failures,
+		Requests:    b.requests,
+		Rejected:    b.rejected,
+		Successes:   b.successes,
+		TotalFailed: b.failed,
+		OpenedAt:    b.openedAt,
+	}
+}
+
+func (b *Breaker) record(state State, err error) {
+	b.mulock.Lock()
+	defer b.mulock.Unlock()
+
+	// the outcome of a request allowed before the last change of state is stale
+	stale := state != b.state
+	if err != nil && !b.cfg.IsFailure(err) {
+		// neither a success nor a failure, a trial is let through again
+		if !stale && b.state == STATE_HALF_OPEN {
+			b.trials--
+		}
+		return
+	}
+	failed := err != nil
+	if failed {
+		b.failed++
+	} else {
+		b.successes++
+	}
+	if stale {
+		return
+	}
+
+	switch b.state {
+	case STATE_CLOSED:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		if b.failures++; b.failures >= b.cfg.FailureThreshold {
+			b.setState(STATE_OPEN)
+		}
+	case STATE_HALF_OPEN:
+		if failed {
+			b.setState(STATE_OPEN)
+			return
+		}
+		if b.succeeded++; b.succeeded >= b.cfg.HalfOpenRequests {
+			b.setState(STATE_CLOSED)
+		}
+	}
+}
+
+// setState is called with the lock held
+func (b *Breaker) setState(to State) {
+	from := b.state
+	b.state = to
+	b.failures, b.trials, b.succeeded = 0, 0, 0
+	if to == STATE_OPEN {
+		b.openedAt = b.cfg.Clock.Now()
+	}
+	if b.cfg.OnStateChange != nil && from != to {
+		b.cfg.OnStateChange(from, to)
+	}
+}
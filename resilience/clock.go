@@ -0,0 +1,27 @@
+package resilience
+
+import "time"
+
+/*
+Clock is the time source of the limiters and the breaker, tests inject a fake one to move the
+time forward without sleeping.
+*/
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// RealClock is the wall clock, the default of the limiters and the breaker
+var RealClock Clock = realClock{}
+
+func clockOrReal(c Clock) Clock {
+	if c == nil {
+		return RealClock
+	}
+	return c
+}
@@ -0,0 +1,141 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrRateLimited = errors.New("Too many requests!")
+
+/*
+Limiter limits the rate of requests, ie to a downstream such as a remote cache.
+*/
+type Limiter interface {
+	// Allow takes a permit if there is one right now, else reports how long till the next one
+	Allow() (ok bool, retryAfter time.Duration)
+	// Wait blocks till a permit is taken, or returns ctx.Err() once ctx is done before
+	Wait(ctx context.Context) error
+}
+
+/*
+wait retries Allow of the limiter after the delay it reports, till it succeeds or ctx is done
+*/
+func wait(ctx context.Context, clock Clock, allow func() (bool, time.Duration)) error {
+	for {
+		ok, retryAfter := allow()
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock.After(retryAfter):
+		}
+	}
+}
+
+/*
+TokenBucket holds up to burst tokens, refilled at rate tokens per second. A request takes a token,
+so bursts up to burst requests are allowed while the average stays at rate.
+*/
+type TokenBucket struct {
+	rate  float64
+	burst float64
+	clock Clock
+
+	mulock sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+/*
+NewTokenBucket starts with a full bucket, a nil clock is the wall clock
+*/
+func NewTokenBucket(rate float64, burst int, clock Clock) *TokenBucket {
+	clock = clockOrReal(clock)
+	return &TokenBucket{rate: rate, burst: float64(burst), clock: clock, tokens: float64(burst), last: clock.Now()}
+}
+
+func (tb *TokenBucket) Allow() (ok bool, retryAfter time.Duration) {
+	tb.mulock.Lock()
+	defer tb.mulock.Unlock()
+
+	now := tb.clock.Now()
+	if elapsed := now.Sub(tb.last); elapsed > 0 {
+		tb.tokens += elapsed.Seconds() * tb.rate
+		if tb.tokens > tb.burst {
+			tb.tokens = tb.burst
+		}
+	}
+	tb.last = now
+
+	if tb.tokens >= 1 {
+		tb.tokens--
+		return true, 0
+	}
+	if tb.rate <= 0 {
+		return false, time.Hour // never refilled
+	}
+	return false, time.Duration((1 - tb.tokens) / tb.rate * float64(time.Second))
+}
+
+func (tb *TokenBucket) Wait(ctx context.Context) error {
+	return wait(ctx, tb.clock, tb.Allow)
+}
+
+// Tokens returns the tokens available right now
+func (tb *TokenBucket) Tokens() float64 {
+	tb.mulock.Lock()
+	defer tb.mulock.Unlock()
+	tokens := tb.tokens + tb.clock.Now().Sub(tb.last).Seconds()*tb.rate
+	if tokens > tb.burst {
+		tokens = tb.burst
+	}
+	return tokens
+}
+
+/*
+SlidingWindow allows limit requests in any window of the given length, tracking the time of the
+requests in the window. Unlike fixed windows, it does not let 2*limit requests through around the
+boundary of two windows.
+*/
+type SlidingWindow struct {
+	limit  int
+	window time.Duration
+	clock  Clock
+
+	mulock sync.Mutex
+	times  []time.Time // of the allowed requests in the window, oldest first, at most limit
+}
+
+func NewSlidingWindow(limit int, window time.Duration, clock Clock) *SlidingWindow {
+	return &SlidingWindow{limit: limit, window: window, clock: clockOrReal(clock)}
+}
+
+func (sw *SlidingWindow) Allow() (ok bool, retryAfter time.Duration) {
+	sw.mulock.Lock()
+	defer sw.mulock.Unlock()
+
+	now := sw.clock.Now()
+	start := now.Add(-sw.window)
+	expired := 0
+	for expired < len(sw.times) && !sw.times[expired].After(start) {
+		expired++
+	}
+	sw.times = sw.times[expired:]
+
+	if len(sw.times) < sw.limit {
+		sw.times = append(sw.times, now)
+		return true, 0
+	}
+	if sw.limit <= 0 {
+		return false, sw.window
+	}
+	return false, sw.times[0].Add(sw.window).Sub(now)
+}
+
+func (sw *SlidingWindow) Wait(ctx context.Context) error {
+	return wait(ctx, sw.clock, sw.Allow)
+}
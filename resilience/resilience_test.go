@@ -0,0 +1,211 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock moves only via Advance, firing the timers due
+type fakeClock struct {
+	mulock sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mulock.Lock()
+	defer c.mulock.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mulock.Lock()
+	defer c.mulock.Unlock()
+	ch := make(chan time.Time, 1)
+	c.timers = append(c.timers, fakeTimer{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mulock.Lock()
+	defer c.mulock.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.ch <- c.now
+	}
+	c.timers = pending
+}
+
+func (c *fakeClock) waiting() int {
+	c.mulock.Lock()
+	defer c.mulock.Unlock()
+	return len(c.timers)
+}
+
+type step struct {
+	advance    time.Duration
+	ok         bool
+	retryAfter time.Duration
+}
+
+func TestLimiters(t *testing.T) {
+	testCases := []struct {
+		name    string
+		limiter func(Clock) Limiter
+		steps   []step
+	}{
+		{name: "TokenBucket-TC-1", limiter: func(c Clock) Limiter { return NewTokenBucket(2, 3, c) }, steps: []step{
+			{ok: true}, {ok: true}, {ok: true}, // the burst
+			{ok: false, retryAfter: 500 * time.Millisecond},
+			{advance: 250 * time.Millisecond, ok: false, retryAfter: 250 * time.Millisecond},
+			{advance: 250 * time.Millisecond, ok: true},
+			{advance: 10 * time.Second, ok: true}, {ok: true}, {ok: true}, // refilled up to the burst only
+			{ok: false, retryAfter: 500 * time.Millisecond},
+		}},
+		{name: "SlidingWindow-TC-2", limiter: func(c Clock) Limiter { return NewSlidingWindow(2, time.Second, c) }, steps: []step{
+			{ok: true},
+			{advance: 600 * time.Millisecond, ok: true},
+			{ok: false, retryAfter: 400 * time.Millisecond},
+			// the first one left the window, unlike a fixed window the second one still counts
+			{advance: 400 * time.Millisecond, ok: true},
+			{advance: 100 * time.Millisecond, ok: false, retryAfter: 500 * time.Millisecond},
+			{advance: 500 * time.Millisecond, ok: true},
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := newFakeClock()
+			l := tc.limiter(clock)
+			for i, s := range tc.steps {
+				clock.Advance(s.advance)
+				if ok, retryAfter := l.Allow(); ok != s.ok || retryAfter != s.retryAfter {
+					t.Errorf("Step %d: expected %v %v, got: %v %v", i, s.ok, s.retryAfter, ok, retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	clock := newFakeClock()
+	tb := NewTokenBucket(1, 1, clock)
+	tb.Allow()
+
+	errCh := make(chan error)
+	go func() { errCh <- tb.Wait(context.Background()) }()
+	for clock.waiting() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Second)
+	if err := <-errCh; err != nil {
+		t.Errorf("Expected the token after a second, got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { errCh <- tb.Wait(ctx) }()
+	for clock.waiting() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected: %v, got: %v", context.Canceled, err)
+	}
+}
+
+var errDown = errors.New("Down!")
+
+func TestBreaker(t *testing.T) {
+	clock := newFakeClock()
+	var changes []State
+	b := NewBreaker(BreakerConfig{FailureThreshold: 3, OpenTimeout: 5 * time.Second, HalfOpenRequests: 2, Clock: clock,
+		OnStateChange: func(from, to State) { changes = append(changes, to) }})
+	call := func(err error) error {
+		return b.Execute(context.Background(), func(ctx context.Context) error { return err })
+	}
+
+	testCases := []struct {
+		name     string
+		advance  time.Duration
+		result   error
+		expected error
+		state    State
+	}{
+		{name: "Success-TC-1", state: STATE_CLOSED},
+		{name: "Failure-TC-2", result: errDown, expected: errDown, state: STATE_CLOSED},
+		{name: "Failure-TC-3", result: errDown, expected: errDown, state: STATE_CLOSED},
+		{name: "Cancelled-TC-4", result: context.Canceled, expected: context.Canceled, state: STATE_CLOSED},
+		{name: "Opens-TC-5", result: errDown, expected: errDown, state: STATE_OPEN},
+		{name: "Rejected-TC-6", advance: time.Second, expected: ErrCircuitOpen, state: STATE_OPEN},
+		{name: "HalfOpenFails-TC-7", advance: 4 * time.Second, result: errDown, expected: errDown, state: STATE_OPEN},
+		{name: "HalfOpenTrial-TC-8", advance: 5 * time.Second, state: STATE_HALF_OPEN},
+		{name: "Closes-TC-9", state: STATE_CLOSED},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock.Advance(tc.advance)
+			if err := call(tc.result); !errors.Is(err, tc.expected) {
+				t.Errorf("Expected: %v, got: %v", tc.expected, err)
+			}
+			if state := b.State(); state != tc.state {
+				t.Errorf("Expected: %v, got: %v", tc.state, state)
+			}
+		})
+	}
+
+	expected := []State{STATE_OPEN, STATE_HALF_OPEN, STATE_OPEN, STATE_HALF_OPEN, STATE_CLOSED}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected: %v, got: %v", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Expected: %v, got: %v", expected, changes)
+		}
+	}
+	if s := b.Stats(); s.Requests != 9 || s.Rejected != 1 || s.TotalFailed != 4 {
+		t.Errorf("Unexpected stats: %+v", s)
+	}
+}
+
+func TestBreakerHalfOpenLimit(t *testing.T) {
+	clock := newFakeClock()
+	b := NewBreaker(BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Second, Clock: clock})
+	done, _ := b.Allow()
+	done(errDown)
+
+	_, err := b.Allow()
+	var openErr *OpenError
+	if !errors.As(err, &openErr) || openErr.RetryAfter != time.Second {
+		t.Errorf("Expected retry after 1s, got: %v", err)
+	}
+
+	clock.Advance(time.Second)
+	trial, err := b.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected a single trial while half-open, got: %v", err)
+	}
+	trial(nil)
+	trial(errDown) // reported once only
+	if b.State() != STATE_CLOSED {
+		t.Errorf("Expected closed, got: %v", b.State())
+	}
+}
@@ -0,0 +1,212 @@
+package semaphore
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+Weighted is a semaphore of size permits, where each acquire takes n of them at once,
+ie a request taking more connections of a pool or more memory than the others.
+
+The waiters are served in FIFO order: once a request has to wait, the later ones wait behind it
+even when they would fit, so a large request is not starved by a stream of small ones.
+
+Unlike the buffered channel semaphore of channel.Semaphore, the wait can be cancelled via the
+context and releasing more than held is reported rather than deadlocking.
+*/
+type Weighted struct {
+	size int64
+
+	mulock  sync.Mutex
+	held    int64
+	waiters list.List // of *waiter, in arrival order
+
+	acquired     int64 // successful acquires
+	waitCount    int64 // acquires which had to wait
+	waitDuration time.Duration
+	maxWait      time.Duration
+}
+
+var (
+	ErrSize        = errors.New("Semaphore size should be at least 1!")
+	ErrPermits     = errors.New("Semaphore permits should be at least 1!")
+	ErrTooLarge    = errors.New("Semaphore acquire is larger than its size!")
+	ErrOverRelease = errors.New("Semaphore released more than held!")
+)
+
+type waiter struct {
+	n     int64
+	ready chan struct{} // closed once the permits are granted
+}
+
+type Metrics struct {
+	Size         int64         `json:"size"`
+	Held         int64         `json:"held"`
+	Waiters      int           `json:"waiters"`
+	Acquired     int64         `json:"acquired"`
+	WaitCount    int64         `json:"wait_count"`
+	WaitDuration time.Duration `json:"wait_duration"` // total time spent waiting
+	MaxWait      time.Duration `json:"max_wait"`
+}
+
+// NewWeighted fails with ErrSize below 1, as every acquire would wait forever
+func NewWeighted(size int64) (*Weighted, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrSize, size)
+	}
+	return &Weighted{size: size}, nil
+}
+
+/*
+Acquire takes n permits, waiting for them in FIFO order. It returns ctx.Err() when ctx is done
+before, holding no permits then. A request larger than the size fails with ErrTooLarge, as it
+would wait forever, and one below 1 permit with ErrPermits.
+*/
+func (s *Weighted) Acquire(ctx context.Context, n int64) error {
+	if n < 1 {
+		return fmt.Errorf("%w: %d", ErrPermits, n)
+	}
+	if n > s.size {
+		return fmt.Errorf("%w: %d of %d", ErrTooLarge, n, s.size)
+	}
+
+	s.mulock.Lock()
+	if s.waiters.Len() == 0 && s.size-s.held >= n {
+		s.held += n
+		s.acquired++
+		s.mulock.Unlock()
+		return nil
+	}
+	// fail fast, rather than racing the context with an immediate grant below
+	if err := ctx.Err(); err != nil {
+		s.mulock.Unlock()
+		return err
+	}
+
+	w := &waiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.mulock.Unlock()
+
+	start := time.Now()
+	select {
+	case <-w.ready:
+		s.recordWait(time.Since(start))
+		return nil
+
+	case <-ctx.Done():
+		s.mulock.Lock()
+		defer s.mulock.Unlock()
+		select {
+		case <-w.ready:
+			// granted meanwhile, the caller holds the permits already
+			s.recordWaitLocked(time.Since(start))
+			return nil
+		default:
+		}
+		front := s.waiters.Front() == elem
+		s.waiters.Remove(elem)
+		// the ones behind might fit now, as this one is not blocking them anymore
+		if front && s.size > s.held {
+			s.notifyWaiters()
+		}
+		return ctx.Err()
+	}
+}
+
+/*
+TryAcquire takes n permits only if available right away and nobody is waiting before it,
+n below 1 fails with ErrPermits.
+*/
+func (s *Weighted) TryAcquire(n int64) (ok bool, err error) {
+	if n < 1 {
+		return false, fmt.Errorf("%w: %d", ErrPermits, n)
+	}
+	s.mulock.Lock()
+	defer s.mulock.Unlock()
+	if s.waiters.Len() == 0 && s.size-s.held >= n {
+		s.held += n
+		s.acquired++
+		return true, nil
+	}
+	return false, nil
+}
+
+/*
+Release returns n permits, waking the waiters which fit now. Releasing more than held changes
+nothing and returns ErrOverRelease, as it is a bug in the caller, so does releasing below 1
+permit with ErrPermits.
+*/
+func (s *Weighted) Release(n int64) error {
+	if n < 1 {
+		return fmt.Errorf("%w: %d", ErrPermits, n)
+	}
+	s.mulock.Lock()
+	defer s.mulock.Unlock()
+	if n > s.held {
+		return fmt.Errorf("%w: %d of %d", ErrOverRelease, n, s.held)
+	}
+	s.held -= n
+	s.notifyWaiters()
+	return nil
+}
+
+// MustRelease is Release, panicking on an over-release or invalid permits
+func (s *Weighted) MustRelease(n int64) {
+	if err := s.Release(n); err != nil {
+		panic(err)
+	}
+}
+
+func (s *Weighted) Metrics() Metrics {
+	s.mulock.Lock()
+	defer s.mulock.Unlock()
+	return Metrics{
+		Size:         s.size,
+		Held:         s.held,
+		Waiters:      s.waiters.Len(),
+		Acquired:     s.acquired,
+		WaitCount:    s.waitCount,
+		WaitDuration: s.waitDuration,
+		MaxWait:      s.maxWait,
+	}
+}
+
+/*
+notifyWaiters grants the permits to the waiters in order, stopping at the first one which does
+not fit so that it is not overtaken. Called with the lock held.
+*/
+func (s *Weighted) notifyWaiters() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+		w := front.Value.(*waiter)
+		if s.size-s.held < w.n {
+			return
+		}
+		s.held += w.n
+		s.acquired++
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
+
+func (s *Weighted) recordWait(d time.Duration) {
+	s.mulock.Lock()
+	defer s.mulock.Unlock()
+	s.recordWaitLocked(d)
+}
+
+func (s *Weighted) recordWaitLocked(d time.Duration) {
+	s.waitCount++
+	s.waitDuration += d
+	if d > s.maxWait {
+		s.maxWait = d
+	}
+}
@@ -0,0 +1,199 @@
+package semaphore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newWeighted(t *testing.T, size int64) *Weighted {
+	t.Helper()
+	s, err := NewWeighted(size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// waitFor polls till the semaphore has the waiters
+func waitFor(s *Weighted, waiters int) {
+	for s.Metrics().Waiters != waiters {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	s := newWeighted(t, 10)
+	testCases := []struct {
+		name     string
+		acquire  int64
+		release  int64
+		ok       bool
+		held     int64
+		expected error
+	}{
+		{name: "TryAcquire-TC-1", acquire: 6, ok: true, held: 6},
+		{name: "TryAcquireTooMany-TC-2", acquire: 5, ok: false, held: 6},
+		{name: "TryAcquireRest-TC-3", acquire: 4, ok: true, held: 10},
+		{name: "Release-TC-4", release: 7, held: 3},
+		{name: "OverRelease-TC-5", release: 4, held: 3, expected: ErrOverRelease},
+		{name: "ReleaseAll-TC-6", release: 3, held: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.acquire > 0 {
+				if ok, err := s.TryAcquire(tc.acquire); ok != tc.ok || err != nil {
+					t.Errorf("Expected: %v, got: %v %v", tc.ok, ok, err)
+				}
+			}
+			if tc.release > 0 {
+				if err := s.Release(tc.release); !errors.Is(err, tc.expected) {
+					t.Errorf("Expected: %v, got: %v", tc.expected, err)
+				}
+			}
+			if held := s.Metrics().Held; held != tc.held {
+				t.Errorf("Expected %v held, got: %v", tc.held, held)
+			}
+		})
+	}
+
+	if err := s.Acquire(context.Background(), 11); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected: %v, got: %v", ErrTooLarge, err)
+	}
+	if held := s.Metrics().Held; held != 0 {
+		t.Errorf("Expected nothing held, got: %v", held)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected MustRelease to panic")
+		}
+	}()
+	s.MustRelease(1)
+}
+
+func TestWeightedInvalid(t *testing.T) {
+	for _, size := range []int64{0, -1} {
+		if _, err := NewWeighted(size); !errors.Is(err, ErrSize) {
+			t.Errorf("Expected: %v for size %d, got: %v", ErrSize, size, err)
+		}
+	}
+
+	s := newWeighted(t, 2)
+	s.Acquire(context.Background(), 1)
+	testCases := []struct {
+		name string
+		call func(n int64) error
+	}{
+		{name: "Acquire-TC-1", call: func(n int64) error { return s.Acquire(context.Background(), n) }},
+		{name: "TryAcquire-TC-2", call: func(n int64) (err error) {
+			_, err = s.TryAcquire(n)
+			return
+		}},
+		{name: "Release-TC-3", call: s.Release},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, n := range []int64{0, -1} {
+				if err := tc.call(n); !errors.Is(err, ErrPermits) {
+					t.Errorf("Expected: %v for %d permits, got: %v", ErrPermits, n, err)
+				}
+			}
+		})
+	}
+	if m := s.Metrics(); m.Held != 1 || m.Acquired != 1 {
+		t.Errorf("Expected the invalid calls to change nothing, got: %+v", m)
+	}
+}
+
+func TestWeightedFIFO(t *testing.T) {
+	s := newWeighted(t, 4)
+	s.Acquire(context.Background(), 3)
+
+	// the large request waits first, the small ones after it must not overtake it
+	order := make(chan int64, 3)
+	wg := sync.WaitGroup{}
+	for i, n := range []int64{4, 1, 1} {
+		wg.Add(1)
+		go func(n int64) {
+			defer wg.Done()
+			s.Acquire(context.Background(), n)
+			order <- n
+			s.Release(n)
+		}(n)
+		waitFor(s, i+1)
+	}
+	if ok, _ := s.TryAcquire(1); ok {
+		t.Error("Expected TryAcquire to fail while there are waiters")
+	}
+
+	s.Release(3)
+	wg.Wait()
+	close(order)
+	if first := <-order; first != 4 {
+		t.Errorf("Expected the large request first, got: %v", first)
+	}
+	m := s.Metrics()
+	if m.Held != 0 || m.Waiters != 0 || m.Acquired != 4 || m.WaitCount != 3 || m.MaxWait <= 0 {
+		t.Errorf("Unexpected metrics: %+v", m)
+	}
+}
+
+func TestWeightedCancel(t *testing.T) {
+	s := newWeighted(t, 2)
+	s.Acquire(context.Background(), 1)
+
+	// the cancelled large waiter at the front must not block the small one behind it
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() { errCh <- s.Acquire(ctx, 2) }()
+	waitFor(s, 1)
+	go s.Acquire(context.Background(), 1)
+	waitFor(s, 2)
+
+	cancel()
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected: %v, got: %v", context.Canceled, err)
+	}
+	waitFor(s, 0)
+	if s.Metrics().Held != 2 {
+		t.Errorf("Expected the waiter behind to acquire, got: %+v", s.Metrics())
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Acquire(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected: %v, got: %v", context.DeadlineExceeded, err)
+	}
+	if m := s.Metrics(); m.Held != 2 || m.Waiters != 0 {
+		t.Errorf("Expected no permits held by the cancelled acquire, got: %+v", m)
+	}
+}
+
+func TestWeightedConcurrent(t *testing.T) {
+	s := newWeighted(t, 5)
+	var current, peak int64
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(n int64) {
+			defer wg.Done()
+			if err := s.Acquire(context.Background(), n); err != nil {
+				t.Error(err)
+				return
+			}
+			if c := atomic.AddInt64(&current, n); c > atomic.LoadInt64(&peak) {
+				atomic.StoreInt64(&peak, c)
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&current, -n)
+			s.Release(n)
+		}(int64(i%3 + 1))
+	}
+	wg.Wait()
+	if peak > 5 || s.Metrics().Held != 0 {
+		t.Errorf("Expected at most 5 permits held, got peak: %v, %+v", peak, s.Metrics())
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"examples/capture"
+	"examples/eventbus"
+	"examples/registry"
+	"examples/resilience"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+/*
+serve listens till ctx is cancelled (SIGINT/SIGTERM), then stops accepting connections and waits up to
+-shutdown-timeout for the requests in flight. The examples run under ctx as well, so they are cancelled
+right away and reply with what they did so far.
+*/
+func serve(ctx context.Context, args []string) (err error) {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	port := fs.String("port", "3000", "port to listen on")
+	timeout := fs.Duration("timeout", 30*time.Second, "default time an example may run, overridden by ?timeout=")
+	maxTimeout := fs.Duration("max-timeout", 2*time.Minute, "longest ?timeout= allowed, longer ones are clamped")
+	shutdownTimeout := fs.Duration("shutdown-timeout", 10*time.Second, "time the requests in flight have to finish on shutdown")
+	limiter := fs.String("limiter", "token", "rate limiter of the /golang routes, token (bucket) or window (sliding)")
+	rate := fs.Float64("rate", 100, "requests per second allowed by the rate limiter, 0 disables it")
+	burst := fs.Int("burst", 200, "requests allowed at once by the token bucket")
+	failures := fs.Int("breaker-failures", 5, "consecutive 5xx responses opening the circuit breaker")
+	openTimeout := fs.Duration("breaker-timeout", 10*time.Second, "time the circuit breaker stays open")
+	if err = fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if err = checkServeFlags(*timeout, *maxTimeout, *limiter, *rate, *burst); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	// Fiber instance
+	app := fiber.New()
+	// requests run under the root context, rather than context.Background()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(ctx)
+		return c.Next()
+	})
+
+	// Routes
+	api := app.Group("golang")
+
+	// Middlewares, protecting the examples from too many requests and failing fast while they fail
+	switch {
+	case *rate <= 0:
+	case *limiter == "token":
+		api.Use(rateLimit(resilience.NewTokenBucket(*rate, *burst, nil)))
+	case *limiter == "window":
+		api.Use(rateLimit(resilience.NewSlidingWindow(int(*rate), time.Second, nil)))
+	}
+	breaker := resilience.NewBreaker(resilience.BreakerConfig{FailureThreshold: *failures, OpenTimeout: *openTimeout})
+	// registered before the breaker, so its state is available while it is open
+	api.Get("/resilience/breaker", func(c *fiber.Ctx) error { return c.JSON(breaker.Stats()) })
+	// the output of the examples is published while they run, to be streamed to the clients
+	bus := eventbus.New()
+	api.Get("/events", events(bus))
+	api.Get("/events/metrics", func(c *fiber.Ctx) error { return c.JSON(bus.Metrics()) })
+	api.Use(circuitBreaker(breaker))
+
+	api.Get("/catalog", catalog)
+	api.Get("/catalog/postman", func(c *fiber.Ctx) error { return sendDoc(c, registry.Postman) })
+	api.Get("/catalog/openapi", func(c *fiber.Ctx) error { return sendDoc(c, registry.OpenAPI) })
+
+	mountAPI(api)
+
+	for _, e := range registry.All() {
+		api.Get("/"+e.Name, exampleHandler(e, *timeout, *maxTimeout, bus))
+	}
+
+	shutdown := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		fmt.Fprintln(os.Stderr, "Shutting down, waiting for the requests in flight...")
+		shutdown <- app.ShutdownWithTimeout(*shutdownTimeout)
+	}()
+
+	// Start server, Listen returns once the shutdown starts
+	if err = app.Listen(fmt.Sprintf(":%v", *port)); err != nil {
+		return
+	}
+	if err = <-shutdown; err != nil {
+		return fmt.Errorf("Shutdown: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, "Server stopped")
+	return
+}
+
+/*
+checkServeFlags rejects the flags which would break every request, ie a token bucket without burst
+rejects them all, and the sliding window counts whole requests.
+*/
+func checkServeFlags(timeout, maxTimeout time.Duration, limiter string, rate float64, burst int) error {
+	if timeout <= 0 || maxTimeout < timeout {
+		return fmt.Errorf("-timeout %v should be positive and at most -max-timeout %v", timeout, maxTimeout)
+	}
+	if limiter != "token" && limiter != "window" {
+		return fmt.Errorf("invalid -limiter %q", limiter)
+	}
+	switch {
+	case rate <= 0:
+	case limiter == "token" && burst < 1:
+		return fmt.Errorf("-burst %d should be at least 1", burst)
+	case limiter == "window" && (rate < 1 || rate != math.Trunc(rate)):
+		return fmt.Errorf("-rate %v of -limiter window should be a whole number of requests", rate)
+	}
+	return nil
+}
+
+// Handler
+
+func errorJSON(c *fiber.Ctx, status int, err error) error {
+	return c.Status(status).JSON(map[string]interface{}{"success": false, "error": err.Error()})
+}
+
+/*
+exampleHandler runs the example with its own output buffer and replies with
+the captured output, duration and error as JSON.
+Query parameters are the arguments of the example, ?timeout= limits how long it may run, clamped to maxTimeout.
+Every line of the output is published on the bus as it is written (topic output.<name>),
+the captured result once done (topic result.<name>).
+*/
+func exampleHandler(e registry.Example, timeout, maxTimeout time.Duration, bus *eventbus.Bus) fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		values := map[string]string{}
+		c.Context().QueryArgs().VisitAll(func(key, val []byte) {
+			values[string(key)] = string(val)
+		})
+
+		// a local copy, the default timeout is shared by the requests
+		runTimeout := timeout
+		if t, ok := values["timeout"]; ok {
+			delete(values, "timeout")
+			if runTimeout, err = time.ParseDuration(t); err != nil {
+				return errorJSON(c, fiber.StatusBadRequest, fmt.Errorf("Invalid timeout: %v", err))
+			}
+			if runTimeout > maxTimeout {
+				runTimeout = maxTimeout
+			}
+		}
+		args, err := e.Args(values)
+		if err != nil {
+			return errorJSON(c, fiber.StatusBadRequest, err)
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), runTimeout)
+		defer cancel()
+
+		lw := eventbus.NewLineWriter(bus, exampleTopic("output", e.Name))
+		res := capture.Run(ctx, e.Title, func(w io.Writer) error {
+			return e.Run(ctx, io.MultiWriter(w, lw), args)
+		})
+		lw.Flush()
+		bus.Publish(exampleTopic("result", e.Name), res)
+		return c.Status(exampleStatus(res.Err)).JSON(res)
+	}
+}
+
+/*
+exampleStatus is the HTTP status of a run: the client is at fault for invalid arguments (400) and
+for an example not finishing within the ?timeout= it chose or the default one (408), only the other
+errors are the server's (5xx), which the circuit breaker counts.
+*/
+func exampleStatus(err error) int {
+	switch {
+	case err == nil:
+		return fiber.StatusOK
+	case errors.Is(err, registry.ErrInvalidArgs):
+		return fiber.StatusBadRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusRequestTimeout
+	case errors.Is(err, context.Canceled):
+		// the server is shutting down
+		return fiber.StatusServiceUnavailable
+	}
+	return fiber.StatusInternalServerError
+}
+
+type catalogEntry struct {
+	registry.Example
+	Path string `json:"path"`
+}
+
+func catalog(c *fiber.Ctx) error {
+	all := registry.All()
+	entries := make([]catalogEntry, 0, len(all))
+	for _, e := range all {
+		entries = append(entries, catalogEntry{Example: e, Path: "/golang/" + e.Name})
+	}
+	return c.JSON(entries)
+}
+
+func sendDoc(c *fiber.Ctx, gen func(string) ([]byte, error)) error {
+	doc, err := gen(c.BaseURL() + "/golang")
+	if err != nil {
+		return errorJSON(c, fiber.StatusInternalServerError, err)
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(doc)
+}
@@ -0,0 +1,337 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+WorkerPool runs the tasks submitted to it on a fixed number of workers.
+
+  - the queue is bounded, Submit blocks while it is full and TrySubmit fails fast
+  - every task yields a Result with its own error, a failing task does not stop the others
+  - results are emitted as they complete, or in the order of submission when Ordered
+  - cancelling the context cancels the running tasks, the queued ones fail with the context error
+  - Shutdown stops the intake and drains the queue, the results must be read till Results is closed
+
+The results channel is bounded as well, the workers wait for it to be read, ie back pressure.
+When Ordered, at most QueueSize + Workers tasks are in flight (queued, running or held back behind
+a slow one), Submit waits for the oldest to be emitted, so the held back results stay bounded too.
+*/
+
+var (
+	ErrPoolClosed = errors.New("Worker pool is closed!")
+	ErrQueueFull  = errors.New("Worker pool queue is full!")
+	ErrTaskPanic  = errors.New("Task panicked!")
+)
+
+type Config struct {
+	Workers   int  // default 1
+	QueueSize int  // default as many as the workers
+	Ordered   bool // emit the results in the order of submission
+}
+
+type Result[In, Out any] struct {
+	Seq     int // order of submission, from 0
+	Input   In
+	Output  Out
+	Err     error
+	Latency time.Duration
+}
+
+type Metrics struct {
+	Workers    int           `json:"workers"`
+	QueueDepth int           `json:"queue_depth"`
+	InFlight   int64         `json:"in_flight"`
+	Submitted  int64         `json:"submitted"`
+	Completed  int64         `json:"completed"`
+	Failed     int64         `json:"failed"`
+	AvgLatency time.Duration `json:"avg_latency"`
+	MaxLatency time.Duration `json:"max_latency"`
+}
+
+type task[In any] struct {
+	seq   int
+	input In
+}
+
+type WorkerPool[In, Out any] struct {
+	cfg    Config
+	fn     func(context.Context, In) (Out, error)
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	queue    chan task[In]
+	done     chan Result[In, Out] // results of the workers, to be emitted
+	results  chan Result[In, Out]
+	finished chan struct{} // closed once all results are emitted
+	slots    chan struct{} // of the tasks in flight when ordered, nil otherwise
+
+	submitLock sync.Mutex // serialises the submissions, so the sequence matches the queue
+	seq        int
+	closed     bool
+	closing    chan struct{}
+	closeOnce  sync.Once
+
+	inFlight  int64
+	submitted int64
+	completed int64
+	failed    int64
+
+	mulock       sync.Mutex
+	runs         int64
+	totalLatency time.Duration
+	maxLatency   time.Duration
+}
+
+/*
+New starts the workers of the pool, running fn for every submitted input till the context is
+cancelled or the pool is shut down.
+*/
+func New[In, Out any](ctx context.Context, cfg Config, fn func(context.Context, In) (Out, error)) *WorkerPool[In, Out] {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = cfg.Workers
+	}
+	p := &WorkerPool[In, Out]{
+		cfg:      cfg,
+		fn:       fn,
+		queue:    make(chan task[In], cfg.QueueSize),
+		done:     make(chan Result[In, Out], cfg.Workers),
+		results:  make(chan Result[In, Out], cfg.QueueSize),
+		finished: make(chan struct{}),
+		closing:  make(chan struct{}),
+	}
+	if cfg.Ordered {
+		p.slots = make(chan struct{}, cfg.QueueSize+cfg.Workers)
+	}
+	p.ctx, p.cancel = context.WithCancel(ctx)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work()
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(p.done)
+	}()
+	go p.emit()
+	// a cancelled pool takes no more tasks, the workers exit once the queue is drained
+	go func() {
+		<-p.ctx.Done()
+		p.Close()
+	}()
+	return p
+}
+
+/*
+Submit queues the input, waiting while the queue is full (or, when ordered, while too many
+tasks are in flight).
+It fails once the pool is closed or when ctx is done before there is room in the queue.
+*/
+func (p *WorkerPool[In, Out]) Submit(ctx context.Context, input In) (err error) {
+	p.submitLock.Lock()
+	defer p.submitLock.Unlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	if p.slots != nil {
+		select {
+		case p.slots <- struct{}{}:
+		case <-p.closing:
+			return ErrPoolClosed
+		case <-p.ctx.Done():
+			return p.ctx.Err()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		defer func() {
+			if err != nil {
+				p.release()
+			}
+		}()
+	}
+
+	select {
+	case p.queue <- task[In]{seq: p.seq, input: input}:
+		p.seq++
+		atomic.AddInt64(&p.submitted, 1)
+		return nil
+	case <-p.closing:
+		return ErrPoolClosed
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TrySubmit queues the input only if there is room in the queue (and a slot when ordered), else returns ErrQueueFull
+func (p *WorkerPool[In, Out]) TrySubmit(input In) error {
+	p.submitLock.Lock()
+	defer p.submitLock.Unlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	if p.slots != nil {
+		select {
+		case p.slots <- struct{}{}:
+		default:
+			return ErrQueueFull
+		}
+	}
+	select {
+	case p.queue <- task[In]{seq: p.seq, input: input}:
+		p.seq++
+		atomic.AddInt64(&p.submitted, 1)
+		return nil
+	default:
+		p.release()
+		return ErrQueueFull
+	}
+}
+
+// release the slot of a task once its result is emitted, when ordered
+func (p *WorkerPool[In, Out]) release() {
+	if p.slots != nil {
+		<-p.slots
+	}
+}
+
+// Results is closed once the pool is shut down and every queued task has its result
+func (p *WorkerPool[In, Out]) Results() <-chan Result[In, Out] {
+	return p.results
+}
+
+/*
+Close stops the intake, the queued tasks still run. It does not wait for them, see Shutdown.
+*/
+func (p *WorkerPool[In, Out]) Close() {
+	p.closeOnce.Do(func() {
+		close(p.closing) // releases the blocked submitters, before waiting for them
+		p.submitLock.Lock()
+		p.closed = true
+		close(p.queue)
+		p.submitLock.Unlock()
+	})
+}
+
+/*
+Shutdown closes the pool and waits for the queue to drain. Once ctx is done before that,
+the running tasks are cancelled and ctx.Err() is returned.
+The results have to be read meanwhile, else the workers wait on them.
+*/
+func (p *WorkerPool[In, Out]) Shutdown(ctx context.Context) error {
+	p.Close()
+	select {
+	case <-p.finished:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+func (p *WorkerPool[In, Out]) Metrics() Metrics {
+	m := Metrics{
+		Workers:    p.cfg.Workers,
+		QueueDepth: len(p.queue),
+		InFlight:   atomic.LoadInt64(&p.inFlight),
+		Submitted:  atomic.LoadInt64(&p.submitted),
+		Completed:  atomic.LoadInt64(&p.completed),
+		Failed:     atomic.LoadInt64(&p.failed),
+	}
+	p.mulock.Lock()
+	defer p.mulock.Unlock()
+	if p.runs > 0 {
+		m.AvgLatency = p.totalLatency / time.Duration(p.runs)
+	}
+	m.MaxLatency = p.maxLatency
+	return m
+}
+
+func (p *WorkerPool[In, Out]) work() {
+	for t := range p.queue {
+		p.done <- p.run(t)
+	}
+}
+
+func (p *WorkerPool[In, Out]) run(t task[In]) (r Result[In, Out]) {
+	r = Result[In, Out]{Seq: t.seq, Input: t.input}
+	// the queued tasks are not run, once the pool is cancelled
+	if r.Err = p.ctx.Err(); r.Err != nil {
+		atomic.AddInt64(&p.failed, 1)
+		return
+	}
+
+	atomic.AddInt64(&p.inFlight, 1)
+	start := time.Now()
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.Err = fmt.Errorf("%w: %v\n%s", ErrTaskPanic, rec, debug.Stack())
+		}
+		r.Latency = time.Since(start)
+		atomic.AddInt64(&p.inFlight, -1)
+		p.record(r)
+	}()
+	r.Output, r.Err = p.fn(p.ctx, t.input)
+	return
+}
+
+func (p *WorkerPool[In, Out]) record(r Result[In, Out]) {
+	if r.Err != nil {
+		atomic.AddInt64(&p.failed, 1)
+	} else {
+		atomic.AddInt64(&p.completed, 1)
+	}
+	p.mulock.Lock()
+	defer p.mulock.Unlock()
+	p.runs++
+	p.totalLatency += r.Latency
+	if r.Latency > p.maxLatency {
+		p.maxLatency = r.Latency
+	}
+}
+
+/*
+emit forwards the results of the workers, holding back the early ones when ordered
+*/
+func (p *WorkerPool[In, Out]) emit() {
+	defer p.cancel()
+	defer close(p.finished)
+	defer close(p.results)
+
+	pending := map[int]Result[In, Out]{}
+	next := 0
+	for r := range p.done {
+		if !p.cfg.Ordered {
+			p.results <- r
+			continue
+		}
+		pending[r.Seq] = r
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			p.results <- r
+			p.release()
+			next++
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errOdd = errors.New("Odd!")
+
+func half(ctx context.Context, n int) (int, error) {
+	// the later inputs complete first, to check the order
+	time.Sleep(time.Duration(10-n%10) * time.Millisecond)
+	if n%2 == 1 {
+		return 0, errOdd
+	}
+	return n / 2, nil
+}
+
+func TestWorkerPool(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     Config
+		ordered bool
+	}{
+		{name: "Unordered-TC-1", cfg: Config{Workers: 4, QueueSize: 2}},
+		{name: "Ordered-TC-2", cfg: Config{Workers: 4, QueueSize: 2, Ordered: true}, ordered: true},
+		{name: "Defaults-TC-3", cfg: Config{Ordered: true}, ordered: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(context.Background(), tc.cfg, half)
+			go func() {
+				for i := 0; i < 20; i++ {
+					if err := p.Submit(context.Background(), i); err != nil {
+						t.Error(err)
+					}
+				}
+				p.Shutdown(context.Background())
+			}()
+
+			seen, failed := map[int]bool{}, 0
+			for r := range p.Results() {
+				if tc.ordered && r.Seq != len(seen) {
+					t.Errorf("Expected seq %v, got: %v", len(seen), r.Seq)
+				}
+				if r.Seq != r.Input || seen[r.Seq] {
+					t.Errorf("Unexpected result: %+v", r)
+				}
+				seen[r.Seq] = true
+				switch {
+				case r.Input%2 == 1 && errors.Is(r.Err, errOdd):
+					failed++
+				case r.Err != nil || r.Output != r.Input/2:
+					t.Errorf("Unexpected result: %+v", r)
+				}
+			}
+			m := p.Metrics()
+			if len(seen) != 20 || failed != 10 || m.Submitted != 20 || m.Completed != 10 || m.Failed != 10 || m.InFlight != 0 || m.QueueDepth != 0 {
+				t.Errorf("Expected 20 results, 10 failed, got: %v %v %+v", len(seen), failed, m)
+			}
+			if m.AvgLatency <= 0 || m.MaxLatency < m.AvgLatency {
+				t.Errorf("Unexpected latencies: %+v", m)
+			}
+			if err := p.Submit(context.Background(), 1); !errors.Is(err, ErrPoolClosed) {
+				t.Errorf("Expected: %v, got: %v", ErrPoolClosed, err)
+			}
+		})
+	}
+}
+
+func TestWorkerPoolQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	p := New(context.Background(), Config{Workers: 1, QueueSize: 1}, func(ctx context.Context, n int) (int, error) {
+		<-release
+		return n, nil
+	})
+
+	// one running, one queued
+	p.Submit(context.Background(), 1)
+	for p.Metrics().InFlight != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := p.TrySubmit(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.TrySubmit(3); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected: %v, got: %v", ErrQueueFull, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Submit(ctx, 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected: %v, got: %v", context.DeadlineExceeded, err)
+	}
+	if depth := p.Metrics().QueueDepth; depth != 1 {
+		t.Errorf("Expected queue depth 1, got: %v", depth)
+	}
+
+	close(release)
+	p.Close()
+	count := 0
+	for range p.Results() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 results, got: %v", count)
+	}
+}
+
+// a slow first task holds back the results of the fast ones, Submit waits rather than piling them up
+func TestWorkerPoolOrderedSlowHead(t *testing.T) {
+	release := make(chan struct{})
+	cfg := Config{Workers: 2, QueueSize: 2, Ordered: true}
+	p := New(context.Background(), cfg, func(ctx context.Context, n int) (int, error) {
+		if n == 0 {
+			<-release
+		}
+		return n, nil
+	})
+	go func() {
+		for i := 0; i < 100; i++ {
+			if err := p.Submit(context.Background(), i); err != nil {
+				t.Error(err)
+			}
+		}
+		p.Close()
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if submitted := p.Metrics().Submitted; submitted != int64(cfg.QueueSize+cfg.Workers) {
+		t.Errorf("Expected %v tasks in flight, got: %v", cfg.QueueSize+cfg.Workers, submitted)
+	}
+
+	close(release)
+	count := 0
+	for r := range p.Results() {
+		if r.Seq != count || r.Output != count {
+			t.Errorf("Expected seq %v, got: %+v", count, r)
+		}
+		count++
+	}
+	if count != 100 {
+		t.Errorf("Expected 100 results, got: %v", count)
+	}
+}
+
+func TestWorkerPoolCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := int32(0)
+	p := New(ctx, Config{Workers: 2, QueueSize: 10}, func(ctx context.Context, n int) (int, error) {
+		atomic.AddInt32(&started, 1)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	for i := 0; i < 10; i++ {
+		p.Submit(context.Background(), i)
+	}
+	for atomic.LoadInt32(&started) != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	count := 0
+	for r := range p.Results() {
+		count++
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("Expected: %v, got: %v", context.Canceled, r.Err)
+		}
+	}
+	if count != 10 || started != 2 {
+		t.Errorf("Expected the queued tasks to fail without running, got %v results, %v started", count, started)
+	}
+	if err := p.Submit(context.Background(), 1); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Expected: %v, got: %v", ErrPoolClosed, err)
+	}
+}
+
+func TestWorkerPoolShutdownTimeout(t *testing.T) {
+	p := New(context.Background(), Config{Workers: 1}, func(ctx context.Context, n int) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	p.Submit(context.Background(), 1)
+	go func() {
+		for range p.Results() {
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected: %v, got: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestWorkerPoolPanic(t *testing.T) {
+	p := New(context.Background(), Config{}, func(ctx context.Context, n int) (int, error) {
+		panic("boom")
+	})
+	p.Submit(context.Background(), 1)
+	p.Close()
+	if r := <-p.Results(); !errors.Is(r.Err, ErrTaskPanic) {
+		t.Errorf("Expected: %v, got: %v", ErrTaskPanic, r.Err)
+	}
+}